@@ -223,6 +223,10 @@ cover: cover.out
 cover-html: cover.out
 	@go tool cover -html=cover.out
 
+.PHONY: bench
+bench:
+	go test -run '^$$' -bench . -benchmem ./...
+
 .PHONY: vulncheck
 vulncheck:
 	govulncheck ./...
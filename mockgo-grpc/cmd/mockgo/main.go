@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/alitari/mockgo-server/mockgo/mock"
 	"github.com/alitari/mockgo-server/mockgo/starter"
 
 	"github.com/alitari/mockgo-server/mockgo-grpc/kvstore"
@@ -65,7 +66,7 @@ GRPC:
 
 func main() {
 	matchStore, err := matchstore.NewGrpcMatchstore(createAddresses(config.ClusterHostnames, config.MatchstorePort),
-		config.MatchstorePort, uint16(starter.BasicConfig.MatchesCapacity), starter.BasicConfig.LoglevelAPI)
+		config.MatchstorePort, starter.BasicConfig.MatchesRetention(), starter.BasicConfig.LoglevelAPI)
 	if err != nil {
 		log.Fatalf("can't initialize grpc matchstore: %v", err)
 	}
@@ -76,6 +77,10 @@ func main() {
 		log.Fatalf("can't initialize grpc kvstore: %v", err)
 	}
 
+	if leaderElection, ok := matchStore.(mock.LeaderElection); ok {
+		starter.LeaderElection = leaderElection
+	}
+
 	starter.SetupRouter(variant, versionTag, config.info(), matchStore, kvStore)
 }
 
@@ -7,12 +7,15 @@ import (
 	"go.uber.org/zap"
 	"log"
 	"net"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/alitari/mockgo-server/mockgo/matches"
 	"github.com/google/uuid"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -28,10 +31,11 @@ type grpcMatchstore struct {
 }
 
 /*
-NewGrpcMatchstore creates a new distributed matches.Matchstore
+NewGrpcMatchstore creates a new distributed matches.Matchstore, which also implements mock.LeaderElection,
+each instance evicts its local matches and mismatches according to the retention
 */
-func NewGrpcMatchstore(addresses []string, serverPort int, capacity uint16, logLevel string) (matches.Matchstore, error) {
-	matchstore := &grpcMatchstore{id: uuid.New().String(), Matchstore: matches.NewInMemoryMatchstore(capacity), timeout: 1 * time.Second, transferLock: false, logger: util.CreateLogger(logLevel)}
+func NewGrpcMatchstore(addresses []string, serverPort int, retention *matches.Retention, logLevel string) (matches.Matchstore, error) {
+	matchstore := &grpcMatchstore{id: uuid.New().String(), Matchstore: matches.NewShardedMatchstore(matches.DefaultShards, retention), timeout: 1 * time.Second, transferLock: false, logger: util.CreateLogger(logLevel)}
 	for _, address := range addresses {
 		conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
@@ -109,6 +113,34 @@ func (g *grpcMatchstore) FetchMismatchesCount(context.Context, *MismatchRequest)
 	return &MismatchesCountResponse{MismatchesCount: mismatchesCount}, nil
 }
 
+func (g *grpcMatchstore) FilterMatches(ctx context.Context, queryRequest *QueryMatchesRequest) (*MatchesResponse, error) {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : filtering matches for endpointId: %s ...", g.id, queryRequest.EndpointId))
+	matches, err := g.Matchstore.QueryMatches(queryRequest.EndpointId, mapProtoQuery(queryRequest.Query))
+	if err != nil {
+		return nil, err
+	}
+	protoMatches := []*Match{}
+	for _, match := range matches {
+		protoMatches = append(protoMatches, mapMatch(match))
+	}
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : return %d matches", g.id, len(protoMatches)))
+	return &MatchesResponse{Matches: protoMatches}, nil
+}
+
+func (g *grpcMatchstore) FilterMismatches(ctx context.Context, queryRequest *QueryMismatchesRequest) (*MismatchesResponse, error) {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : filtering mismatches ...", g.id))
+	mismatches, err := g.Matchstore.QueryMismatches(mapProtoQuery(queryRequest.Query))
+	if err != nil {
+		return nil, err
+	}
+	protoMismatches := []*Mismatch{}
+	for _, mismatch := range mismatches {
+		protoMismatches = append(protoMismatches, mapMismatch(mismatch))
+	}
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : return %d mismatches", g.id, len(protoMismatches)))
+	return &MismatchesResponse{Mismatches: protoMismatches}, nil
+}
+
 func (g *grpcMatchstore) RemoveMatches(ctx context.Context, endpointRequest *EndPointRequest) (*RemoveResponse, error) {
 	g.logger.Debug(fmt.Sprintf("matchstore: %s : remove matches for endpoint %s ...", g.id, endpointRequest.Id))
 	if err := g.Matchstore.DeleteMatches(endpointRequest.Id); err != nil {
@@ -208,6 +240,64 @@ func (g *grpcMatchstore) GetMismatchesCount() (uint64, error) {
 	return mismatchesCount, nil
 }
 
+/*
+QueryMatches queries all instances, each instance filters its matches and returns at most offset+limit matches,
+which are merged in the order of the timestamps before offset and limit are applied
+*/
+func (g *grpcMatchstore) QueryMatches(endpointID string, query *matches.Query) ([]*matches.Match, error) {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : query matches for endpointId: %s ...", g.id, endpointID))
+	result := []*matches.Match{}
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	for _, client := range g.clients {
+		response, err := client.FilterMatches(ctx, &QueryMatchesRequest{EndpointId: endpointID, Query: mapInstanceQuery(query)})
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range response.GetMatches() {
+			result = append(result, mapProtoMatch(match))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return inOrder(result[i].Timestamp, result[j].Timestamp, query.Descending)
+	})
+	start, end := query.Page(len(result))
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : return %d matches as result for endpointId: %s ...", g.id, end-start, endpointID))
+	return result[start:end], nil
+}
+
+/*
+QueryMismatches queries all instances like QueryMatches
+*/
+func (g *grpcMatchstore) QueryMismatches(query *matches.Query) ([]*matches.Mismatch, error) {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : query mismatches ...", g.id))
+	result := []*matches.Mismatch{}
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	for _, client := range g.clients {
+		response, err := client.FilterMismatches(ctx, &QueryMismatchesRequest{Query: mapInstanceQuery(query)})
+		if err != nil {
+			return nil, err
+		}
+		for _, mismatch := range response.GetMismatches() {
+			result = append(result, mapProtoMismatch(mismatch))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return inOrder(result[i].Timestamp, result[j].Timestamp, query.Descending)
+	})
+	start, end := query.Page(len(result))
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : return %d mismatches as result", g.id, end-start))
+	return result[start:end], nil
+}
+
+func inOrder(timestamp, other time.Time, descending bool) bool {
+	if descending {
+		return timestamp.After(other)
+	}
+	return timestamp.Before(other)
+}
+
 func (g *grpcMatchstore) DeleteMatches(endpointID string) error {
 	g.logger.Debug(fmt.Sprintf("matchstore: %s : delete matches for endpointId: %s ...", g.id, endpointID))
 	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
@@ -236,6 +326,91 @@ func (g *grpcMatchstore) DeleteMismatches() error {
 	return nil
 }
 
+/*
+StreamEvents streams the events of the local matchstore, the header is sent as soon as the subscription is established
+*/
+func (g *grpcMatchstore) StreamEvents(request *EventsRequest, stream Matchstore_StreamEventsServer) error {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : streaming events ...", g.id))
+	events, err := g.Matchstore.Subscribe(stream.Context())
+	if err != nil {
+		return err
+	}
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for event := range events {
+		if err := stream.Send(mapEvent(event)); err != nil {
+			return err
+		}
+	}
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : events stream closed", g.id))
+	return nil
+}
+
+/*
+Subscribe aggregates the event streams of all instances, an instance which isn't reachable is skipped
+*/
+func (g *grpcMatchstore) Subscribe(ctx context.Context) (<-chan *matches.Event, error) {
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : subscribe to events ...", g.id))
+	events := make(chan *matches.Event)
+	var streams sync.WaitGroup
+	for _, client := range g.clients {
+		stream, err := client.StreamEvents(ctx, &EventsRequest{})
+		if err == nil {
+			_, err = stream.Header()
+		}
+		if err != nil {
+			g.logger.Debug(fmt.Sprintf("matchstore: %s : instance not reachable for events: %v", g.id, err))
+			continue
+		}
+		streams.Add(1)
+		go func() {
+			defer streams.Done()
+			for {
+				protoEvent, err := stream.Recv()
+				if err != nil {
+					return
+				}
+				select {
+				case events <- mapProtoEvent(protoEvent):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		streams.Wait()
+		close(events)
+	}()
+	return events, nil
+}
+
+func (g *grpcMatchstore) FetchInstanceId(context.Context, *InstanceIdRequest) (*InstanceIdResponse, error) {
+	return &InstanceIdResponse{Id: g.id}, nil
+}
+
+/*
+IsLeader implements mock.LeaderElection, the leader is the reachable instance of the cluster with the lowest id
+*/
+func (g *grpcMatchstore) IsLeader() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	leaderID := g.id
+	for _, client := range g.clients {
+		response, err := client.FetchInstanceId(ctx, &InstanceIdRequest{})
+		if err != nil {
+			g.logger.Debug(fmt.Sprintf("matchstore: %s : instance not reachable for leader election: %v", g.id, err))
+			continue
+		}
+		if response.Id < leaderID {
+			leaderID = response.Id
+		}
+	}
+	g.logger.Debug(fmt.Sprintf("matchstore: %s : leader is %s", g.id, leaderID))
+	return leaderID == g.id, nil
+}
+
 func (g *grpcMatchstore) Shutdown() error {
 	g.server.GracefulStop()
 	return nil
@@ -243,31 +418,154 @@ func (g *grpcMatchstore) Shutdown() error {
 
 func mapProtoMatch(protomatch *Match) *matches.Match {
 	match := &matches.Match{EndpointID: protomatch.EndpointId, Timestamp: protomatch.Timestamp.AsTime(),
-		ActualRequest:  &matches.ActualRequest{Method: protomatch.ActualRequest.Method, URL: protomatch.ActualRequest.Url, Header: mapProtoHeader(protomatch.ActualRequest.Header), Host: protomatch.ActualRequest.Host},
-		ActualResponse: &matches.ActualResponse{StatusCode: int(protomatch.ActualResponse.StatusCode), Header: mapProtoHeader(protomatch.ActualResponse.Header)}}
+		ActualRequest: mapProtoActualRequest(protomatch.ActualRequest), ActualResponse: mapProtoActualResponse(protomatch.ActualResponse)}
+	for _, protoMessage := range protomatch.ActualMessages {
+		match.ActualMessages = append(match.ActualMessages, &matches.ActualMessage{Timestamp: protoMessage.Timestamp.AsTime(), Binary: protoMessage.Binary, Data: string(protoMessage.Data)})
+	}
+	for _, protoAttempt := range protomatch.CallbackAttempts {
+		match.CallbackAttempts = append(match.CallbackAttempts, &matches.CallbackAttempt{Timestamp: protoAttempt.Timestamp.AsTime(), StatusCode: int(protoAttempt.StatusCode), Header: mapProtoHeader(protoAttempt.Header), Body: protoAttempt.Body, Error: protoAttempt.Error})
+	}
+	if protomatch.Mail != nil {
+		match.Mail = &matches.Mail{From: protomatch.Mail.From, To: protomatch.Mail.To, Subject: protomatch.Mail.Subject, Header: mapProtoHeader(protomatch.Mail.Header), Size: int(protomatch.Mail.Size)}
+		for _, protoPart := range protomatch.Mail.Parts {
+			match.Mail.Parts = append(match.Mail.Parts, &matches.MailPart{ContentType: protoPart.ContentType, Filename: protoPart.Filename, Binary: protoPart.Binary, Body: protoPart.Body})
+		}
+	}
 	return match
 }
 
 func mapMatch(match *matches.Match) *Match {
 	protoMatch := &Match{EndpointId: match.EndpointID, Timestamp: timestamppb.New(match.Timestamp),
-		ActualRequest:  &ActualRequest{Method: match.ActualRequest.Method, Url: match.ActualRequest.URL, Header: mapHeader(match.ActualRequest.Header), Host: match.ActualRequest.Host},
-		ActualResponse: &ActualResponse{StatusCode: int32(match.ActualResponse.StatusCode), Header: mapHeader(match.ActualResponse.Header)}}
+		ActualRequest: mapActualRequest(match.ActualRequest), ActualResponse: mapActualResponse(match.ActualResponse)}
+	for _, message := range match.ActualMessages {
+		protoMatch.ActualMessages = append(protoMatch.ActualMessages, &ActualMessage{Timestamp: timestamppb.New(message.Timestamp), Binary: message.Binary, Data: []byte(message.Data)})
+	}
+	for _, attempt := range match.CallbackAttempts {
+		protoMatch.CallbackAttempts = append(protoMatch.CallbackAttempts, &CallbackAttempt{Timestamp: timestamppb.New(attempt.Timestamp), StatusCode: int32(attempt.StatusCode), Header: mapHeader(attempt.Header), Body: attempt.Body, Error: attempt.Error})
+	}
+	if match.Mail != nil {
+		protoMatch.Mail = &Mail{From: match.Mail.From, To: match.Mail.To, Subject: match.Mail.Subject, Header: mapHeader(match.Mail.Header), Size: int64(match.Mail.Size)}
+		for _, part := range match.Mail.Parts {
+			protoMatch.Mail.Parts = append(protoMatch.Mail.Parts, &MailPart{ContentType: part.ContentType, Filename: part.Filename, Binary: part.Binary, Body: part.Body})
+		}
+	}
 	return protoMatch
 }
 
+func mapProtoEvent(protoEvent *Event) *matches.Event {
+	event := &matches.Event{Type: protoEvent.Type}
+	if protoEvent.Match != nil {
+		event.Match = mapProtoMatch(protoEvent.Match)
+	}
+	if protoEvent.Mismatch != nil {
+		event.Mismatch = mapProtoMismatch(protoEvent.Mismatch)
+	}
+	return event
+}
+
+func mapEvent(event *matches.Event) *Event {
+	protoEvent := &Event{Type: event.Type}
+	if event.Match != nil {
+		protoEvent.Match = mapMatch(event.Match)
+	}
+	if event.Mismatch != nil {
+		protoEvent.Mismatch = mapMismatch(event.Mismatch)
+	}
+	return protoEvent
+}
+
 func mapProtoMismatch(protomismatch *Mismatch) *matches.Mismatch {
 	mismatch := &matches.Mismatch{MismatchDetails: protomismatch.MismatchDetails, Timestamp: protomismatch.Timestamp.AsTime(),
-		ActualRequest: &matches.ActualRequest{Method: protomismatch.ActualRequest.Method, URL: protomismatch.ActualRequest.Url, Header: mapProtoHeader(protomismatch.ActualRequest.Header), Host: protomismatch.ActualRequest.Host}}
+		ActualRequest: mapProtoActualRequest(protomismatch.ActualRequest)}
+	for _, protoNearestEndpoint := range protomismatch.NearestEndpoints {
+		nearestEndpoint := &matches.NearestEndpoint{EndpointID: protoNearestEndpoint.EndpointId, Method: protoNearestEndpoint.Method, Path: protoNearestEndpoint.Path,
+			PathDistance: int(protoNearestEndpoint.PathDistance), Differences: []*matches.Difference{}}
+		for _, protoDifference := range protoNearestEndpoint.Differences {
+			nearestEndpoint.Differences = append(nearestEndpoint.Differences, &matches.Difference{Matcher: protoDifference.Matcher, Expected: protoDifference.Expected, Actual: protoDifference.Actual})
+		}
+		mismatch.NearestEndpoints = append(mismatch.NearestEndpoints, nearestEndpoint)
+	}
 	return mismatch
 }
 
 func mapMismatch(mismatch *matches.Mismatch) *Mismatch {
 	protoMismatch := &Mismatch{MismatchDetails: mismatch.MismatchDetails, Timestamp: timestamppb.New(mismatch.Timestamp),
-		ActualRequest: &ActualRequest{Method: mismatch.ActualRequest.Method, Url: mismatch.ActualRequest.URL, Header: mapHeader(mismatch.ActualRequest.Header), Host: mismatch.ActualRequest.Host},
+		ActualRequest: mapActualRequest(mismatch.ActualRequest),
+	}
+	for _, nearestEndpoint := range mismatch.NearestEndpoints {
+		protoNearestEndpoint := &NearestEndpoint{EndpointId: nearestEndpoint.EndpointID, Method: nearestEndpoint.Method, Path: nearestEndpoint.Path, PathDistance: int32(nearestEndpoint.PathDistance)}
+		for _, difference := range nearestEndpoint.Differences {
+			protoNearestEndpoint.Differences = append(protoNearestEndpoint.Differences, &Difference{Matcher: difference.Matcher, Expected: difference.Expected, Actual: difference.Actual})
+		}
+		protoMismatch.NearestEndpoints = append(protoMismatch.NearestEndpoints, protoNearestEndpoint)
 	}
 	return protoMismatch
 }
 
+/*
+mapInstanceQuery maps the query for a single instance, which can't apply the offset because the page is built of the results of all instances
+*/
+func mapInstanceQuery(query *matches.Query) *Query {
+	protoQuery := &Query{Method: query.Method, Path: query.Path, Header: query.Header, Body: query.Body, StatusCode: int32(query.StatusCode), Descending: query.Descending}
+	if query.Limit > 0 {
+		protoQuery.Limit = int32(query.Offset + query.Limit)
+	}
+	if !query.From.IsZero() {
+		protoQuery.From = timestamppb.New(query.From)
+	}
+	if !query.To.IsZero() {
+		protoQuery.To = timestamppb.New(query.To)
+	}
+	return protoQuery
+}
+
+func mapProtoQuery(protoQuery *Query) *matches.Query {
+	if protoQuery == nil {
+		return &matches.Query{}
+	}
+	query := &matches.Query{Method: protoQuery.Method, Path: protoQuery.Path, Header: protoQuery.Header, Body: protoQuery.Body, StatusCode: int(protoQuery.StatusCode),
+		Limit: int(protoQuery.Limit), Offset: int(protoQuery.Offset), Descending: protoQuery.Descending}
+	if protoQuery.From != nil {
+		query.From = protoQuery.From.AsTime()
+	}
+	if protoQuery.To != nil {
+		query.To = protoQuery.To.AsTime()
+	}
+	return query
+}
+
+func mapProtoActualRequest(protoRequest *ActualRequest) *matches.ActualRequest {
+	if protoRequest == nil {
+		return nil
+	}
+	return &matches.ActualRequest{Method: protoRequest.Method, URL: protoRequest.Url, Header: mapProtoHeader(protoRequest.Header), Host: protoRequest.Host,
+		RemoteAddr: protoRequest.RemoteAddr, Body: protoRequest.Body, Binary: protoRequest.Binary, BodyTruncated: protoRequest.BodyTruncated}
+}
+
+func mapActualRequest(request *matches.ActualRequest) *ActualRequest {
+	if request == nil {
+		return nil
+	}
+	return &ActualRequest{Method: request.Method, Url: request.URL, Header: mapHeader(request.Header), Host: request.Host,
+		RemoteAddr: request.RemoteAddr, Body: request.Body, Binary: request.Binary, BodyTruncated: request.BodyTruncated}
+}
+
+func mapProtoActualResponse(protoResponse *ActualResponse) *matches.ActualResponse {
+	if protoResponse == nil {
+		return nil
+	}
+	return &matches.ActualResponse{StatusCode: int(protoResponse.StatusCode), Header: mapProtoHeader(protoResponse.Header), StreamedEvents: int(protoResponse.StreamedEvents),
+		Body: protoResponse.Body, Binary: protoResponse.Binary, BodyTruncated: protoResponse.BodyTruncated, Duration: time.Duration(protoResponse.Duration)}
+}
+
+func mapActualResponse(response *matches.ActualResponse) *ActualResponse {
+	if response == nil {
+		return nil
+	}
+	return &ActualResponse{StatusCode: int32(response.StatusCode), Header: mapHeader(response.Header), StreamedEvents: int32(response.StreamedEvents),
+		Body: response.Body, Binary: response.Binary, BodyTruncated: response.BodyTruncated, Duration: int64(response.Duration)}
+}
+
 func mapProtoHeader(header map[string]*HeaderValue) map[string][]string {
 	res := map[string][]string{}
 	for k, headerValue := range header {
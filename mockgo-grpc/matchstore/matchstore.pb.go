@@ -106,6 +106,227 @@ func (*MismatchRequest) Descriptor() ([]byte, []int) {
 	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{1}
 }
 
+type Query struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Method     string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Path       string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Header     string                 `protobuf:"bytes,5,opt,name=header,proto3" json:"header,omitempty"`
+	Body       string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	StatusCode int32                  `protobuf:"varint,7,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Limit      int32                  `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset     int32                  `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	Descending bool                   `protobuf:"varint,10,opt,name=descending,proto3" json:"descending,omitempty"`
+}
+
+func (x *Query) Reset() {
+	*x = Query{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Query) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Query) ProtoMessage() {}
+
+func (x *Query) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Query.ProtoReflect.Descriptor instead.
+func (*Query) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Query) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Query) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Query) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Query) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Query) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *Query) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Query) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *Query) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Query) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Query) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+type QueryMatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointId string `protobuf:"bytes,1,opt,name=endpointId,proto3" json:"endpointId,omitempty"`
+	Query      *Query `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *QueryMatchesRequest) Reset() {
+	*x = QueryMatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryMatchesRequest) ProtoMessage() {}
+
+func (x *QueryMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryMatchesRequest.ProtoReflect.Descriptor instead.
+func (*QueryMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueryMatchesRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *QueryMatchesRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type QueryMismatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *QueryMismatchesRequest) Reset() {
+	*x = QueryMismatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryMismatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryMismatchesRequest) ProtoMessage() {}
+
+func (x *QueryMismatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryMismatchesRequest.ProtoReflect.Descriptor instead.
+func (*QueryMismatchesRequest) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryMismatchesRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
 type MatchesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -117,7 +338,7 @@ type MatchesResponse struct {
 func (x *MatchesResponse) Reset() {
 	*x = MatchesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[2]
+		mi := &file_matchstore_matchstore_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -130,7 +351,7 @@ func (x *MatchesResponse) String() string {
 func (*MatchesResponse) ProtoMessage() {}
 
 func (x *MatchesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[2]
+	mi := &file_matchstore_matchstore_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,7 +364,7 @@ func (x *MatchesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MatchesResponse.ProtoReflect.Descriptor instead.
 func (*MatchesResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{2}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *MatchesResponse) GetMatches() []*Match {
@@ -164,7 +385,7 @@ type MatchesCountResponse struct {
 func (x *MatchesCountResponse) Reset() {
 	*x = MatchesCountResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[3]
+		mi := &file_matchstore_matchstore_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -177,7 +398,7 @@ func (x *MatchesCountResponse) String() string {
 func (*MatchesCountResponse) ProtoMessage() {}
 
 func (x *MatchesCountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[3]
+	mi := &file_matchstore_matchstore_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -190,7 +411,7 @@ func (x *MatchesCountResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MatchesCountResponse.ProtoReflect.Descriptor instead.
 func (*MatchesCountResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{3}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MatchesCountResponse) GetMatchesCount() uint64 {
@@ -211,7 +432,7 @@ type MismatchesResponse struct {
 func (x *MismatchesResponse) Reset() {
 	*x = MismatchesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[4]
+		mi := &file_matchstore_matchstore_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -224,7 +445,7 @@ func (x *MismatchesResponse) String() string {
 func (*MismatchesResponse) ProtoMessage() {}
 
 func (x *MismatchesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[4]
+	mi := &file_matchstore_matchstore_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -237,7 +458,7 @@ func (x *MismatchesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MismatchesResponse.ProtoReflect.Descriptor instead.
 func (*MismatchesResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{4}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *MismatchesResponse) GetMismatches() []*Mismatch {
@@ -258,7 +479,7 @@ type MismatchesCountResponse struct {
 func (x *MismatchesCountResponse) Reset() {
 	*x = MismatchesCountResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[5]
+		mi := &file_matchstore_matchstore_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -271,7 +492,7 @@ func (x *MismatchesCountResponse) String() string {
 func (*MismatchesCountResponse) ProtoMessage() {}
 
 func (x *MismatchesCountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[5]
+	mi := &file_matchstore_matchstore_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,7 +505,7 @@ func (x *MismatchesCountResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MismatchesCountResponse.ProtoReflect.Descriptor instead.
 func (*MismatchesCountResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{5}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *MismatchesCountResponse) GetMismatchesCount() uint64 {
@@ -303,7 +524,7 @@ type RemoveResponse struct {
 func (x *RemoveResponse) Reset() {
 	*x = RemoveResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[6]
+		mi := &file_matchstore_matchstore_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -316,7 +537,7 @@ func (x *RemoveResponse) String() string {
 func (*RemoveResponse) ProtoMessage() {}
 
 func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[6]
+	mi := &file_matchstore_matchstore_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -329,34 +550,72 @@ func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveResponse.ProtoReflect.Descriptor instead.
 func (*RemoveResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{6}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{9}
 }
 
-type AddAllResponse struct {
+type InstanceIdRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	Locked bool `protobuf:"varint,1,opt,name=locked,proto3" json:"locked,omitempty"`
+func (x *InstanceIdRequest) Reset() {
+	*x = InstanceIdRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *AddAllResponse) Reset() {
-	*x = AddAllResponse{}
+func (x *InstanceIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceIdRequest) ProtoMessage() {}
+
+func (x *InstanceIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceIdRequest.ProtoReflect.Descriptor instead.
+func (*InstanceIdRequest) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{10}
+}
+
+type InstanceIdResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *InstanceIdResponse) Reset() {
+	*x = InstanceIdResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[7]
+		mi := &file_matchstore_matchstore_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AddAllResponse) String() string {
+func (x *InstanceIdResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddAllResponse) ProtoMessage() {}
+func (*InstanceIdResponse) ProtoMessage() {}
 
-func (x *AddAllResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[7]
+func (x *InstanceIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,46 +626,699 @@ func (x *AddAllResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddAllResponse.ProtoReflect.Descriptor instead.
-func (*AddAllResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use InstanceIdResponse.ProtoReflect.Descriptor instead.
+func (*InstanceIdResponse) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *AddAllResponse) GetLocked() bool {
+func (x *InstanceIdResponse) GetId() string {
 	if x != nil {
-		return x.Locked
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-type Match struct {
+type AddAllResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	EndpointId     string                 `protobuf:"bytes,1,opt,name=endpointId,proto3" json:"endpointId,omitempty"`
-	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	ActualRequest  *ActualRequest         `protobuf:"bytes,3,opt,name=actualRequest,proto3" json:"actualRequest,omitempty"`
-	ActualResponse *ActualResponse        `protobuf:"bytes,4,opt,name=actualResponse,proto3" json:"actualResponse,omitempty"`
+	Locked bool `protobuf:"varint,1,opt,name=locked,proto3" json:"locked,omitempty"`
 }
 
-func (x *Match) Reset() {
-	*x = Match{}
+func (x *AddAllResponse) Reset() {
+	*x = AddAllResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[8]
+		mi := &file_matchstore_matchstore_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Match) String() string {
+func (x *AddAllResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Match) ProtoMessage() {}
+func (*AddAllResponse) ProtoMessage() {}
+
+func (x *AddAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAllResponse.ProtoReflect.Descriptor instead.
+func (*AddAllResponse) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddAllResponse) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+type Match struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointId       string                 `protobuf:"bytes,1,opt,name=endpointId,proto3" json:"endpointId,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ActualRequest    *ActualRequest         `protobuf:"bytes,3,opt,name=actualRequest,proto3" json:"actualRequest,omitempty"`
+	ActualResponse   *ActualResponse        `protobuf:"bytes,4,opt,name=actualResponse,proto3" json:"actualResponse,omitempty"`
+	ActualMessages   []*ActualMessage       `protobuf:"bytes,5,rep,name=actualMessages,proto3" json:"actualMessages,omitempty"`
+	Mail             *Mail                  `protobuf:"bytes,6,opt,name=mail,proto3" json:"mail,omitempty"`
+	CallbackAttempts []*CallbackAttempt     `protobuf:"bytes,7,rep,name=callbackAttempts,proto3" json:"callbackAttempts,omitempty"`
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
 
 func (x *Match) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[8]
+	mi := &file_matchstore_matchstore_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Match) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *Match) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Match) GetActualRequest() *ActualRequest {
+	if x != nil {
+		return x.ActualRequest
+	}
+	return nil
+}
+
+func (x *Match) GetActualResponse() *ActualResponse {
+	if x != nil {
+		return x.ActualResponse
+	}
+	return nil
+}
+
+func (x *Match) GetActualMessages() []*ActualMessage {
+	if x != nil {
+		return x.ActualMessages
+	}
+	return nil
+}
+
+func (x *Match) GetMail() *Mail {
+	if x != nil {
+		return x.Mail
+	}
+	return nil
+}
+
+func (x *Match) GetCallbackAttempts() []*CallbackAttempt {
+	if x != nil {
+		return x.CallbackAttempts
+	}
+	return nil
+}
+
+type EventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *EventsRequest) Reset() {
+	*x = EventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventsRequest) ProtoMessage() {}
+
+func (x *EventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventsRequest.ProtoReflect.Descriptor instead.
+func (*EventsRequest) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{14}
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type     string    `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Match    *Match    `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
+	Mismatch *Mismatch `protobuf:"bytes,3,opt,name=mismatch,proto3" json:"mismatch,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetMatch() *Match {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *Event) GetMismatch() *Mismatch {
+	if x != nil {
+		return x.Mismatch
+	}
+	return nil
+}
+
+type Mismatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MismatchDetails  string                 `protobuf:"bytes,1,opt,name=mismatchDetails,proto3" json:"mismatchDetails,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ActualRequest    *ActualRequest         `protobuf:"bytes,3,opt,name=actualRequest,proto3" json:"actualRequest,omitempty"`
+	NearestEndpoints []*NearestEndpoint     `protobuf:"bytes,4,rep,name=nearestEndpoints,proto3" json:"nearestEndpoints,omitempty"`
+}
+
+func (x *Mismatch) Reset() {
+	*x = Mismatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Mismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mismatch) ProtoMessage() {}
+
+func (x *Mismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mismatch.ProtoReflect.Descriptor instead.
+func (*Mismatch) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Mismatch) GetMismatchDetails() string {
+	if x != nil {
+		return x.MismatchDetails
+	}
+	return ""
+}
+
+func (x *Mismatch) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Mismatch) GetActualRequest() *ActualRequest {
+	if x != nil {
+		return x.ActualRequest
+	}
+	return nil
+}
+
+func (x *Mismatch) GetNearestEndpoints() []*NearestEndpoint {
+	if x != nil {
+		return x.NearestEndpoints
+	}
+	return nil
+}
+
+type NearestEndpoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointId   string        `protobuf:"bytes,1,opt,name=endpointId,proto3" json:"endpointId,omitempty"`
+	Method       string        `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Path         string        `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	PathDistance int32         `protobuf:"varint,4,opt,name=pathDistance,proto3" json:"pathDistance,omitempty"`
+	Differences  []*Difference `protobuf:"bytes,5,rep,name=differences,proto3" json:"differences,omitempty"`
+}
+
+func (x *NearestEndpoint) Reset() {
+	*x = NearestEndpoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NearestEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestEndpoint) ProtoMessage() {}
+
+func (x *NearestEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestEndpoint.ProtoReflect.Descriptor instead.
+func (*NearestEndpoint) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *NearestEndpoint) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *NearestEndpoint) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *NearestEndpoint) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *NearestEndpoint) GetPathDistance() int32 {
+	if x != nil {
+		return x.PathDistance
+	}
+	return 0
+}
+
+func (x *NearestEndpoint) GetDifferences() []*Difference {
+	if x != nil {
+		return x.Differences
+	}
+	return nil
+}
+
+type Difference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matcher  string `protobuf:"bytes,1,opt,name=matcher,proto3" json:"matcher,omitempty"`
+	Expected string `protobuf:"bytes,2,opt,name=expected,proto3" json:"expected,omitempty"`
+	Actual   string `protobuf:"bytes,3,opt,name=actual,proto3" json:"actual,omitempty"`
+}
+
+func (x *Difference) Reset() {
+	*x = Difference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Difference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Difference) ProtoMessage() {}
+
+func (x *Difference) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Difference.ProtoReflect.Descriptor instead.
+func (*Difference) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Difference) GetMatcher() string {
+	if x != nil {
+		return x.Matcher
+	}
+	return ""
+}
+
+func (x *Difference) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *Difference) GetActual() string {
+	if x != nil {
+		return x.Actual
+	}
+	return ""
+}
+
+type ActualRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method        string                  `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Url           string                  `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Header        map[string]*HeaderValue `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Host          string                  `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	RemoteAddr    string                  `protobuf:"bytes,5,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
+	Body          string                  `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Binary        bool                    `protobuf:"varint,7,opt,name=binary,proto3" json:"binary,omitempty"`
+	BodyTruncated bool                    `protobuf:"varint,8,opt,name=bodyTruncated,proto3" json:"bodyTruncated,omitempty"`
+}
+
+func (x *ActualRequest) Reset() {
+	*x = ActualRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActualRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActualRequest) ProtoMessage() {}
+
+func (x *ActualRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActualRequest.ProtoReflect.Descriptor instead.
+func (*ActualRequest) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ActualRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ActualRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ActualRequest) GetHeader() map[string]*HeaderValue {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ActualRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ActualRequest) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *ActualRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ActualRequest) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *ActualRequest) GetBodyTruncated() bool {
+	if x != nil {
+		return x.BodyTruncated
+	}
+	return false
+}
+
+type ActualResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StatusCode     int32                   `protobuf:"varint,1,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Header         map[string]*HeaderValue `protobuf:"bytes,2,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StreamedEvents int32                   `protobuf:"varint,3,opt,name=streamedEvents,proto3" json:"streamedEvents,omitempty"`
+	Body           string                  `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Binary         bool                    `protobuf:"varint,5,opt,name=binary,proto3" json:"binary,omitempty"`
+	BodyTruncated  bool                    `protobuf:"varint,6,opt,name=bodyTruncated,proto3" json:"bodyTruncated,omitempty"`
+	Duration       int64                   `protobuf:"varint,7,opt,name=duration,proto3" json:"duration,omitempty"`
+}
+
+func (x *ActualResponse) Reset() {
+	*x = ActualResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActualResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActualResponse) ProtoMessage() {}
+
+func (x *ActualResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActualResponse.ProtoReflect.Descriptor instead.
+func (*ActualResponse) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ActualResponse) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *ActualResponse) GetHeader() map[string]*HeaderValue {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ActualResponse) GetStreamedEvents() int32 {
+	if x != nil {
+		return x.StreamedEvents
+	}
+	return 0
+}
+
+func (x *ActualResponse) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ActualResponse) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *ActualResponse) GetBodyTruncated() bool {
+	if x != nil {
+		return x.BodyTruncated
+	}
+	return false
+}
+
+func (x *ActualResponse) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+type ActualMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Binary    bool                   `protobuf:"varint,2,opt,name=binary,proto3" json:"binary,omitempty"`
+	Data      []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ActualMessage) Reset() {
+	*x = ActualMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_matchstore_matchstore_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActualMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActualMessage) ProtoMessage() {}
+
+func (x *ActualMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,66 +1329,62 @@ func (x *Match) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Match.ProtoReflect.Descriptor instead.
-func (*Match) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *Match) GetEndpointId() string {
-	if x != nil {
-		return x.EndpointId
-	}
-	return ""
+// Deprecated: Use ActualMessage.ProtoReflect.Descriptor instead.
+func (*ActualMessage) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *Match) GetTimestamp() *timestamppb.Timestamp {
+func (x *ActualMessage) GetTimestamp() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Timestamp
 	}
 	return nil
 }
 
-func (x *Match) GetActualRequest() *ActualRequest {
+func (x *ActualMessage) GetBinary() bool {
 	if x != nil {
-		return x.ActualRequest
+		return x.Binary
 	}
-	return nil
+	return false
 }
 
-func (x *Match) GetActualResponse() *ActualResponse {
+func (x *ActualMessage) GetData() []byte {
 	if x != nil {
-		return x.ActualResponse
+		return x.Data
 	}
 	return nil
 }
 
-type Mismatch struct {
+type Mail struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MismatchDetails string                 `protobuf:"bytes,1,opt,name=mismatchDetails,proto3" json:"mismatchDetails,omitempty"`
-	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	ActualRequest   *ActualRequest         `protobuf:"bytes,3,opt,name=actualRequest,proto3" json:"actualRequest,omitempty"`
+	From    string                  `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To      []string                `protobuf:"bytes,2,rep,name=to,proto3" json:"to,omitempty"`
+	Subject string                  `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Header  map[string]*HeaderValue `protobuf:"bytes,4,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Parts   []*MailPart             `protobuf:"bytes,5,rep,name=parts,proto3" json:"parts,omitempty"`
+	Size    int64                   `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
 }
 
-func (x *Mismatch) Reset() {
-	*x = Mismatch{}
+func (x *Mail) Reset() {
+	*x = Mail{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[9]
+		mi := &file_matchstore_matchstore_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Mismatch) String() string {
+func (x *Mail) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Mismatch) ProtoMessage() {}
+func (*Mail) ProtoMessage() {}
 
-func (x *Mismatch) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[9]
+func (x *Mail) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,60 +1395,81 @@ func (x *Mismatch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Mismatch.ProtoReflect.Descriptor instead.
-func (*Mismatch) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use Mail.ProtoReflect.Descriptor instead.
+func (*Mail) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *Mismatch) GetMismatchDetails() string {
+func (x *Mail) GetFrom() string {
 	if x != nil {
-		return x.MismatchDetails
+		return x.From
 	}
 	return ""
 }
 
-func (x *Mismatch) GetTimestamp() *timestamppb.Timestamp {
+func (x *Mail) GetTo() []string {
 	if x != nil {
-		return x.Timestamp
+		return x.To
 	}
 	return nil
 }
 
-func (x *Mismatch) GetActualRequest() *ActualRequest {
+func (x *Mail) GetSubject() string {
 	if x != nil {
-		return x.ActualRequest
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Mail) GetHeader() map[string]*HeaderValue {
+	if x != nil {
+		return x.Header
 	}
 	return nil
 }
 
-type ActualRequest struct {
+func (x *Mail) GetParts() []*MailPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+func (x *Mail) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type MailPart struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Method string                  `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
-	Url    string                  `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	Header map[string]*HeaderValue `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Host   string                  `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	ContentType string `protobuf:"bytes,1,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Filename    string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Binary      bool   `protobuf:"varint,3,opt,name=binary,proto3" json:"binary,omitempty"`
+	Body        string `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
 }
 
-func (x *ActualRequest) Reset() {
-	*x = ActualRequest{}
+func (x *MailPart) Reset() {
+	*x = MailPart{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[10]
+		mi := &file_matchstore_matchstore_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ActualRequest) String() string {
+func (x *MailPart) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ActualRequest) ProtoMessage() {}
+func (*MailPart) ProtoMessage() {}
 
-func (x *ActualRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[10]
+func (x *MailPart) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -551,65 +1480,68 @@ func (x *ActualRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ActualRequest.ProtoReflect.Descriptor instead.
-func (*ActualRequest) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use MailPart.ProtoReflect.Descriptor instead.
+func (*MailPart) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ActualRequest) GetMethod() string {
+func (x *MailPart) GetContentType() string {
 	if x != nil {
-		return x.Method
+		return x.ContentType
 	}
 	return ""
 }
 
-func (x *ActualRequest) GetUrl() string {
+func (x *MailPart) GetFilename() string {
 	if x != nil {
-		return x.Url
+		return x.Filename
 	}
 	return ""
 }
 
-func (x *ActualRequest) GetHeader() map[string]*HeaderValue {
+func (x *MailPart) GetBinary() bool {
 	if x != nil {
-		return x.Header
+		return x.Binary
 	}
-	return nil
+	return false
 }
 
-func (x *ActualRequest) GetHost() string {
+func (x *MailPart) GetBody() string {
 	if x != nil {
-		return x.Host
+		return x.Body
 	}
 	return ""
 }
 
-type ActualResponse struct {
+type CallbackAttempt struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	StatusCode int32                   `protobuf:"varint,1,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
-	Header     map[string]*HeaderValue `protobuf:"bytes,2,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Timestamp  *timestamppb.Timestamp  `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	StatusCode int32                   `protobuf:"varint,2,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Header     map[string]*HeaderValue `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body       string                  `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Error      string                  `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *ActualResponse) Reset() {
-	*x = ActualResponse{}
+func (x *CallbackAttempt) Reset() {
+	*x = CallbackAttempt{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[11]
+		mi := &file_matchstore_matchstore_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ActualResponse) String() string {
+func (x *CallbackAttempt) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ActualResponse) ProtoMessage() {}
+func (*CallbackAttempt) ProtoMessage() {}
 
-func (x *ActualResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[11]
+func (x *CallbackAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_matchstore_matchstore_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -620,25 +1552,46 @@ func (x *ActualResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ActualResponse.ProtoReflect.Descriptor instead.
-func (*ActualResponse) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CallbackAttempt.ProtoReflect.Descriptor instead.
+func (*CallbackAttempt) Descriptor() ([]byte, []int) {
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ActualResponse) GetStatusCode() int32 {
+func (x *CallbackAttempt) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *CallbackAttempt) GetStatusCode() int32 {
 	if x != nil {
 		return x.StatusCode
 	}
 	return 0
 }
 
-func (x *ActualResponse) GetHeader() map[string]*HeaderValue {
+func (x *CallbackAttempt) GetHeader() map[string]*HeaderValue {
 	if x != nil {
 		return x.Header
 	}
 	return nil
 }
 
+func (x *CallbackAttempt) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CallbackAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type HeaderValue struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -650,7 +1603,7 @@ type HeaderValue struct {
 func (x *HeaderValue) Reset() {
 	*x = HeaderValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_matchstore_matchstore_proto_msgTypes[12]
+		mi := &file_matchstore_matchstore_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -663,7 +1616,7 @@ func (x *HeaderValue) String() string {
 func (*HeaderValue) ProtoMessage() {}
 
 func (x *HeaderValue) ProtoReflect() protoreflect.Message {
-	mi := &file_matchstore_matchstore_proto_msgTypes[12]
+	mi := &file_matchstore_matchstore_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,7 +1629,7 @@ func (x *HeaderValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeaderValue.ProtoReflect.Descriptor instead.
 func (*HeaderValue) Descriptor() ([]byte, []int) {
-	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{12}
+	return file_matchstore_matchstore_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *HeaderValue) GetVal() []string {
@@ -697,118 +1650,280 @@ var file_matchstore_matchstore_proto_rawDesc = []byte{
 	0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
 	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x11, 0x0a,
 	0x0f, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x3e, 0x0a, 0x0f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72,
-	0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x22, 0x3a, 0x0a, 0x14, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x74, 0x63,
-	0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
-	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x4a, 0x0a, 0x12,
-	0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x34, 0x0a, 0x0a, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74,
-	0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0a, 0x6d, 0x69,
-	0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x17, 0x4d, 0x69, 0x73, 0x6d,
-	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65,
-	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6d, 0x69,
-	0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x10, 0x0a,
-	0x0e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x28, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x22, 0xe6, 0x01, 0x0a, 0x05, 0x4d, 0x61,
-	0x74, 0x63, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x22, 0xa9, 0x02, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x3f, 0x0a,
-	0x0d, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72,
-	0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
-	0x0d, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x42,
-	0x0a, 0x0e, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74,
-	0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x52, 0x0e, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0xaf, 0x01, 0x0a, 0x08, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12,
-	0x28, 0x0a, 0x0f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69,
-	0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x12, 0x3f, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0xe0, 0x01, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10,
-	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c,
-	0x12, 0x3d, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x25, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63,
-	0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
-	0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68,
-	0x6f, 0x73, 0x74, 0x1a, 0x52, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65,
-	0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc4, 0x01, 0x0a, 0x0e, 0x41, 0x63, 0x74, 0x75,
-	0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x68, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x1a, 0x52, 0x0a, 0x0b, 0x48, 0x65,
+	0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x1e,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x5e, 0x0a, 0x13,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0x41, 0x0a, 0x16,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22,
+	0x3e, 0x0a, 0x0f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22,
+	0x3a, 0x0a, 0x14, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x4a, 0x0a, 0x12, 0x4d,
+	0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x34, 0x0a, 0x0a, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0a, 0x6d, 0x69, 0x73,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x17, 0x4d, 0x69, 0x73, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6d, 0x69, 0x73,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x10, 0x0a, 0x0e,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13,
+	0x0a, 0x11, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x24, 0x0a, 0x12, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x28, 0x0a, 0x0e, 0x41, 0x64, 0x64,
+	0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c, 0x6f, 0x63,
+	0x6b, 0x65, 0x64, 0x22, 0x98, 0x03, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1e, 0x0a,
+	0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x38, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x3f, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x75, 0x61,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x75,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x75, 0x61,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x42, 0x0a, 0x0e, 0x61, 0x63, 0x74, 0x75,
+	0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63,
+	0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0e, 0x61, 0x63,
+	0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e,
+	0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x0e, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x24, 0x0a, 0x04, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x69, 0x6c, 0x52,
+	0x04, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x47, 0x0a, 0x10, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x52, 0x10, 0x63, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x22, 0x0f,
+	0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x76, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x30, 0x0a, 0x08, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x08, 0x6d,
+	0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x22, 0xf8, 0x01, 0x0a, 0x08, 0x4d, 0x69, 0x73, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d,
+	0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x38,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x3f, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x75,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74,
+	0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x75,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x47, 0x0a, 0x10, 0x6e, 0x65, 0x61,
+	0x72, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x10, 0x6e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x22, 0xbb, 0x01, 0x0a, 0x0f, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x61, 0x74, 0x68, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x61, 0x74, 0x68, 0x44, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x52, 0x0b, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x22, 0x5a, 0x0a, 0x0a, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x22, 0xd2, 0x02, 0x0a,
+	0x0d, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x3d, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x6f, 0x64, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x6f, 0x64, 0x79, 0x54,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d,
+	0x62, 0x6f, 0x64, 0x79, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x1a, 0x52, 0x0a,
+	0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xda, 0x02, 0x0a, 0x0e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x6f, 0x64, 0x79,
+	0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0d, 0x62, 0x6f, 0x64, 0x79, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x52, 0x0a, 0x0b, 0x48, 0x65,
 	0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76,
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x74,
 	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1f,
-	0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x76, 0x61, 0x6c, 0x32,
-	0xf7, 0x03, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x4a,
-	0x0a, 0x0c, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b,
-	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x64, 0x50,
-	0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6d, 0x61,
-	0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x0f, 0x46, 0x65,
-	0x74, 0x63, 0x68, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e,
-	0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68,
-	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x11,
-	0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45,
-	0x6e, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
-	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63,
-	0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x5a, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x69, 0x73, 0x6d, 0x61,
-	0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73,
-	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43,
-	0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4a,
-	0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12,
-	0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x64,
-	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d,
-	0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x10, 0x52, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b,
-	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61,
-	0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x74, 0x61, 0x72, 0x69, 0x2f,
-	0x6d, 0x6f, 0x63, 0x6b, 0x67, 0x6f, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x6d, 0x6f,
-	0x63, 0x6b, 0x67, 0x6f, 0x2d, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73,
-	0x74, 0x6f, 0x72, 0x65, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x75,
+	0x0a, 0x0d, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x69, 0x6e,
+	0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x8e, 0x02, 0x0a, 0x04, 0x4d, 0x61, 0x69, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x06,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x69, 0x6c, 0x2e, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x12, 0x2a, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d,
+	0x61, 0x69, 0x6c, 0x50, 0x61, 0x72, 0x74, 0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x1a, 0x52, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x74, 0x0a, 0x08, 0x4d, 0x61, 0x69, 0x6c, 0x50, 0x61,
+	0x72, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0xaa, 0x02, 0x0a,
+	0x0f, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74,
+	0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x6f, 0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x52, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1f, 0x0a, 0x0b, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x76, 0x61, 0x6c, 0x32, 0xb8, 0x06, 0x0a, 0x0a, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x4a, 0x0a, 0x0c, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x0f, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x69,
+	0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x11, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x64, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a,
+	0x14, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
+	0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0d, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x10, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d,
+	0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0d, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x10, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x22, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x4d,
+	0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x22, 0x00, 0x30, 0x01, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x69, 0x74, 0x61, 0x72, 0x69, 0x2f, 0x6d, 0x6f, 0x63, 0x6b,
+	0x67, 0x6f, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x6d, 0x6f, 0x63, 0x6b, 0x67, 0x6f,
+	0x2d, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -823,54 +1938,95 @@ func file_matchstore_matchstore_proto_rawDescGZIP() []byte {
 	return file_matchstore_matchstore_proto_rawDescData
 }
 
-var file_matchstore_matchstore_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_matchstore_matchstore_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_matchstore_matchstore_proto_goTypes = []interface{}{
 	(*EndPointRequest)(nil),         // 0: matchstore.EndPointRequest
 	(*MismatchRequest)(nil),         // 1: matchstore.MismatchRequest
-	(*MatchesResponse)(nil),         // 2: matchstore.MatchesResponse
-	(*MatchesCountResponse)(nil),    // 3: matchstore.MatchesCountResponse
-	(*MismatchesResponse)(nil),      // 4: matchstore.MismatchesResponse
-	(*MismatchesCountResponse)(nil), // 5: matchstore.MismatchesCountResponse
-	(*RemoveResponse)(nil),          // 6: matchstore.RemoveResponse
-	(*AddAllResponse)(nil),          // 7: matchstore.AddAllResponse
-	(*Match)(nil),                   // 8: matchstore.Match
-	(*Mismatch)(nil),                // 9: matchstore.Mismatch
-	(*ActualRequest)(nil),           // 10: matchstore.ActualRequest
-	(*ActualResponse)(nil),          // 11: matchstore.ActualResponse
-	(*HeaderValue)(nil),             // 12: matchstore.HeaderValue
-	nil,                             // 13: matchstore.ActualRequest.HeaderEntry
-	nil,                             // 14: matchstore.ActualResponse.HeaderEntry
-	(*timestamppb.Timestamp)(nil),   // 15: google.protobuf.Timestamp
+	(*Query)(nil),                   // 2: matchstore.Query
+	(*QueryMatchesRequest)(nil),     // 3: matchstore.QueryMatchesRequest
+	(*QueryMismatchesRequest)(nil),  // 4: matchstore.QueryMismatchesRequest
+	(*MatchesResponse)(nil),         // 5: matchstore.MatchesResponse
+	(*MatchesCountResponse)(nil),    // 6: matchstore.MatchesCountResponse
+	(*MismatchesResponse)(nil),      // 7: matchstore.MismatchesResponse
+	(*MismatchesCountResponse)(nil), // 8: matchstore.MismatchesCountResponse
+	(*RemoveResponse)(nil),          // 9: matchstore.RemoveResponse
+	(*InstanceIdRequest)(nil),       // 10: matchstore.InstanceIdRequest
+	(*InstanceIdResponse)(nil),      // 11: matchstore.InstanceIdResponse
+	(*AddAllResponse)(nil),          // 12: matchstore.AddAllResponse
+	(*Match)(nil),                   // 13: matchstore.Match
+	(*EventsRequest)(nil),           // 14: matchstore.EventsRequest
+	(*Event)(nil),                   // 15: matchstore.Event
+	(*Mismatch)(nil),                // 16: matchstore.Mismatch
+	(*NearestEndpoint)(nil),         // 17: matchstore.NearestEndpoint
+	(*Difference)(nil),              // 18: matchstore.Difference
+	(*ActualRequest)(nil),           // 19: matchstore.ActualRequest
+	(*ActualResponse)(nil),          // 20: matchstore.ActualResponse
+	(*ActualMessage)(nil),           // 21: matchstore.ActualMessage
+	(*Mail)(nil),                    // 22: matchstore.Mail
+	(*MailPart)(nil),                // 23: matchstore.MailPart
+	(*CallbackAttempt)(nil),         // 24: matchstore.CallbackAttempt
+	(*HeaderValue)(nil),             // 25: matchstore.HeaderValue
+	nil,                             // 26: matchstore.ActualRequest.HeaderEntry
+	nil,                             // 27: matchstore.ActualResponse.HeaderEntry
+	nil,                             // 28: matchstore.Mail.HeaderEntry
+	nil,                             // 29: matchstore.CallbackAttempt.HeaderEntry
+	(*timestamppb.Timestamp)(nil),   // 30: google.protobuf.Timestamp
 }
 var file_matchstore_matchstore_proto_depIdxs = []int32{
-	8,  // 0: matchstore.MatchesResponse.matches:type_name -> matchstore.Match
-	9,  // 1: matchstore.MismatchesResponse.mismatches:type_name -> matchstore.Mismatch
-	15, // 2: matchstore.Match.timestamp:type_name -> google.protobuf.Timestamp
-	10, // 3: matchstore.Match.actualRequest:type_name -> matchstore.ActualRequest
-	11, // 4: matchstore.Match.actualResponse:type_name -> matchstore.ActualResponse
-	15, // 5: matchstore.Mismatch.timestamp:type_name -> google.protobuf.Timestamp
-	10, // 6: matchstore.Mismatch.actualRequest:type_name -> matchstore.ActualRequest
-	13, // 7: matchstore.ActualRequest.header:type_name -> matchstore.ActualRequest.HeaderEntry
-	14, // 8: matchstore.ActualResponse.header:type_name -> matchstore.ActualResponse.HeaderEntry
-	12, // 9: matchstore.ActualRequest.HeaderEntry.value:type_name -> matchstore.HeaderValue
-	12, // 10: matchstore.ActualResponse.HeaderEntry.value:type_name -> matchstore.HeaderValue
-	0,  // 11: matchstore.Matchstore.FetchMatches:input_type -> matchstore.EndPointRequest
-	1,  // 12: matchstore.Matchstore.FetchMismatches:input_type -> matchstore.MismatchRequest
-	0,  // 13: matchstore.Matchstore.FetchMatchesCount:input_type -> matchstore.EndPointRequest
-	1,  // 14: matchstore.Matchstore.FetchMismatchesCount:input_type -> matchstore.MismatchRequest
-	0,  // 15: matchstore.Matchstore.RemoveMatches:input_type -> matchstore.EndPointRequest
-	1,  // 16: matchstore.Matchstore.RemoveMismatches:input_type -> matchstore.MismatchRequest
-	2,  // 17: matchstore.Matchstore.FetchMatches:output_type -> matchstore.MatchesResponse
-	4,  // 18: matchstore.Matchstore.FetchMismatches:output_type -> matchstore.MismatchesResponse
-	3,  // 19: matchstore.Matchstore.FetchMatchesCount:output_type -> matchstore.MatchesCountResponse
-	5,  // 20: matchstore.Matchstore.FetchMismatchesCount:output_type -> matchstore.MismatchesCountResponse
-	6,  // 21: matchstore.Matchstore.RemoveMatches:output_type -> matchstore.RemoveResponse
-	6,  // 22: matchstore.Matchstore.RemoveMismatches:output_type -> matchstore.RemoveResponse
-	17, // [17:23] is the sub-list for method output_type
-	11, // [11:17] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	30, // 0: matchstore.Query.from:type_name -> google.protobuf.Timestamp
+	30, // 1: matchstore.Query.to:type_name -> google.protobuf.Timestamp
+	2,  // 2: matchstore.QueryMatchesRequest.query:type_name -> matchstore.Query
+	2,  // 3: matchstore.QueryMismatchesRequest.query:type_name -> matchstore.Query
+	13, // 4: matchstore.MatchesResponse.matches:type_name -> matchstore.Match
+	16, // 5: matchstore.MismatchesResponse.mismatches:type_name -> matchstore.Mismatch
+	30, // 6: matchstore.Match.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 7: matchstore.Match.actualRequest:type_name -> matchstore.ActualRequest
+	20, // 8: matchstore.Match.actualResponse:type_name -> matchstore.ActualResponse
+	21, // 9: matchstore.Match.actualMessages:type_name -> matchstore.ActualMessage
+	22, // 10: matchstore.Match.mail:type_name -> matchstore.Mail
+	24, // 11: matchstore.Match.callbackAttempts:type_name -> matchstore.CallbackAttempt
+	13, // 12: matchstore.Event.match:type_name -> matchstore.Match
+	16, // 13: matchstore.Event.mismatch:type_name -> matchstore.Mismatch
+	30, // 14: matchstore.Mismatch.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 15: matchstore.Mismatch.actualRequest:type_name -> matchstore.ActualRequest
+	17, // 16: matchstore.Mismatch.nearestEndpoints:type_name -> matchstore.NearestEndpoint
+	18, // 17: matchstore.NearestEndpoint.differences:type_name -> matchstore.Difference
+	26, // 18: matchstore.ActualRequest.header:type_name -> matchstore.ActualRequest.HeaderEntry
+	27, // 19: matchstore.ActualResponse.header:type_name -> matchstore.ActualResponse.HeaderEntry
+	30, // 20: matchstore.ActualMessage.timestamp:type_name -> google.protobuf.Timestamp
+	28, // 21: matchstore.Mail.header:type_name -> matchstore.Mail.HeaderEntry
+	23, // 22: matchstore.Mail.parts:type_name -> matchstore.MailPart
+	30, // 23: matchstore.CallbackAttempt.timestamp:type_name -> google.protobuf.Timestamp
+	29, // 24: matchstore.CallbackAttempt.header:type_name -> matchstore.CallbackAttempt.HeaderEntry
+	25, // 25: matchstore.ActualRequest.HeaderEntry.value:type_name -> matchstore.HeaderValue
+	25, // 26: matchstore.ActualResponse.HeaderEntry.value:type_name -> matchstore.HeaderValue
+	25, // 27: matchstore.Mail.HeaderEntry.value:type_name -> matchstore.HeaderValue
+	25, // 28: matchstore.CallbackAttempt.HeaderEntry.value:type_name -> matchstore.HeaderValue
+	0,  // 29: matchstore.Matchstore.FetchMatches:input_type -> matchstore.EndPointRequest
+	1,  // 30: matchstore.Matchstore.FetchMismatches:input_type -> matchstore.MismatchRequest
+	0,  // 31: matchstore.Matchstore.FetchMatchesCount:input_type -> matchstore.EndPointRequest
+	1,  // 32: matchstore.Matchstore.FetchMismatchesCount:input_type -> matchstore.MismatchRequest
+	0,  // 33: matchstore.Matchstore.RemoveMatches:input_type -> matchstore.EndPointRequest
+	1,  // 34: matchstore.Matchstore.RemoveMismatches:input_type -> matchstore.MismatchRequest
+	10, // 35: matchstore.Matchstore.FetchInstanceId:input_type -> matchstore.InstanceIdRequest
+	3,  // 36: matchstore.Matchstore.FilterMatches:input_type -> matchstore.QueryMatchesRequest
+	4,  // 37: matchstore.Matchstore.FilterMismatches:input_type -> matchstore.QueryMismatchesRequest
+	14, // 38: matchstore.Matchstore.StreamEvents:input_type -> matchstore.EventsRequest
+	5,  // 39: matchstore.Matchstore.FetchMatches:output_type -> matchstore.MatchesResponse
+	7,  // 40: matchstore.Matchstore.FetchMismatches:output_type -> matchstore.MismatchesResponse
+	6,  // 41: matchstore.Matchstore.FetchMatchesCount:output_type -> matchstore.MatchesCountResponse
+	8,  // 42: matchstore.Matchstore.FetchMismatchesCount:output_type -> matchstore.MismatchesCountResponse
+	9,  // 43: matchstore.Matchstore.RemoveMatches:output_type -> matchstore.RemoveResponse
+	9,  // 44: matchstore.Matchstore.RemoveMismatches:output_type -> matchstore.RemoveResponse
+	11, // 45: matchstore.Matchstore.FetchInstanceId:output_type -> matchstore.InstanceIdResponse
+	5,  // 46: matchstore.Matchstore.FilterMatches:output_type -> matchstore.MatchesResponse
+	7,  // 47: matchstore.Matchstore.FilterMismatches:output_type -> matchstore.MismatchesResponse
+	15, // 48: matchstore.Matchstore.StreamEvents:output_type -> matchstore.Event
+	39, // [39:49] is the sub-list for method output_type
+	29, // [29:39] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_matchstore_matchstore_proto_init() }
@@ -904,7 +2060,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MatchesResponse); i {
+			switch v := v.(*Query); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -916,7 +2072,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MatchesCountResponse); i {
+			switch v := v.(*QueryMatchesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -928,7 +2084,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MismatchesResponse); i {
+			switch v := v.(*QueryMismatchesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -940,7 +2096,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MismatchesCountResponse); i {
+			switch v := v.(*MatchesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -952,7 +2108,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveResponse); i {
+			switch v := v.(*MatchesCountResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -964,7 +2120,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddAllResponse); i {
+			switch v := v.(*MismatchesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -976,7 +2132,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Match); i {
+			switch v := v.(*MismatchesCountResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -988,7 +2144,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Mismatch); i {
+			switch v := v.(*RemoveResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1000,7 +2156,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActualRequest); i {
+			switch v := v.(*InstanceIdRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1012,7 +2168,7 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActualResponse); i {
+			switch v := v.(*InstanceIdResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1024,6 +2180,162 @@ func file_matchstore_matchstore_proto_init() {
 			}
 		}
 		file_matchstore_matchstore_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddAllResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Match); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mismatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NearestEndpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Difference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActualRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActualResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActualMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MailPart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CallbackAttempt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_matchstore_matchstore_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*HeaderValue); i {
 			case 0:
 				return &v.state
@@ -1042,7 +2354,7 @@ func file_matchstore_matchstore_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_matchstore_matchstore_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
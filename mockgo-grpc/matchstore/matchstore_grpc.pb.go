@@ -28,6 +28,10 @@ type MatchstoreClient interface {
 	FetchMismatchesCount(ctx context.Context, in *MismatchRequest, opts ...grpc.CallOption) (*MismatchesCountResponse, error)
 	RemoveMatches(ctx context.Context, in *EndPointRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
 	RemoveMismatches(ctx context.Context, in *MismatchRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	FetchInstanceId(ctx context.Context, in *InstanceIdRequest, opts ...grpc.CallOption) (*InstanceIdResponse, error)
+	FilterMatches(ctx context.Context, in *QueryMatchesRequest, opts ...grpc.CallOption) (*MatchesResponse, error)
+	FilterMismatches(ctx context.Context, in *QueryMismatchesRequest, opts ...grpc.CallOption) (*MismatchesResponse, error)
+	StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Matchstore_StreamEventsClient, error)
 }
 
 type matchstoreClient struct {
@@ -92,6 +96,65 @@ func (c *matchstoreClient) RemoveMismatches(ctx context.Context, in *MismatchReq
 	return out, nil
 }
 
+func (c *matchstoreClient) FetchInstanceId(ctx context.Context, in *InstanceIdRequest, opts ...grpc.CallOption) (*InstanceIdResponse, error) {
+	out := new(InstanceIdResponse)
+	err := c.cc.Invoke(ctx, "/matchstore.Matchstore/FetchInstanceId", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchstoreClient) FilterMatches(ctx context.Context, in *QueryMatchesRequest, opts ...grpc.CallOption) (*MatchesResponse, error) {
+	out := new(MatchesResponse)
+	err := c.cc.Invoke(ctx, "/matchstore.Matchstore/FilterMatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchstoreClient) FilterMismatches(ctx context.Context, in *QueryMismatchesRequest, opts ...grpc.CallOption) (*MismatchesResponse, error) {
+	out := new(MismatchesResponse)
+	err := c.cc.Invoke(ctx, "/matchstore.Matchstore/FilterMismatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchstoreClient) StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Matchstore_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Matchstore_ServiceDesc.Streams[0], "/matchstore.Matchstore/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &matchstoreStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Matchstore_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type matchstoreStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *matchstoreStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MatchstoreServer is the server API for Matchstore service.
 // All implementations must embed UnimplementedMatchstoreServer
 // for forward compatibility
@@ -102,6 +165,10 @@ type MatchstoreServer interface {
 	FetchMismatchesCount(context.Context, *MismatchRequest) (*MismatchesCountResponse, error)
 	RemoveMatches(context.Context, *EndPointRequest) (*RemoveResponse, error)
 	RemoveMismatches(context.Context, *MismatchRequest) (*RemoveResponse, error)
+	FetchInstanceId(context.Context, *InstanceIdRequest) (*InstanceIdResponse, error)
+	FilterMatches(context.Context, *QueryMatchesRequest) (*MatchesResponse, error)
+	FilterMismatches(context.Context, *QueryMismatchesRequest) (*MismatchesResponse, error)
+	StreamEvents(*EventsRequest, Matchstore_StreamEventsServer) error
 	mustEmbedUnimplementedMatchstoreServer()
 }
 
@@ -127,6 +194,18 @@ func (UnimplementedMatchstoreServer) RemoveMatches(context.Context, *EndPointReq
 func (UnimplementedMatchstoreServer) RemoveMismatches(context.Context, *MismatchRequest) (*RemoveResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveMismatches not implemented")
 }
+func (UnimplementedMatchstoreServer) FetchInstanceId(context.Context, *InstanceIdRequest) (*InstanceIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchInstanceId not implemented")
+}
+func (UnimplementedMatchstoreServer) FilterMatches(context.Context, *QueryMatchesRequest) (*MatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterMatches not implemented")
+}
+func (UnimplementedMatchstoreServer) FilterMismatches(context.Context, *QueryMismatchesRequest) (*MismatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterMismatches not implemented")
+}
+func (UnimplementedMatchstoreServer) StreamEvents(*EventsRequest, Matchstore_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
 func (UnimplementedMatchstoreServer) mustEmbedUnimplementedMatchstoreServer() {}
 
 // UnsafeMatchstoreServer may be embedded to opt out of forward compatibility for this service.
@@ -248,6 +327,81 @@ func _Matchstore_RemoveMismatches_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Matchstore_FetchInstanceId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchstoreServer).FetchInstanceId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/matchstore.Matchstore/FetchInstanceId",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchstoreServer).FetchInstanceId(ctx, req.(*InstanceIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Matchstore_FilterMatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchstoreServer).FilterMatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/matchstore.Matchstore/FilterMatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchstoreServer).FilterMatches(ctx, req.(*QueryMatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Matchstore_FilterMismatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMismatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchstoreServer).FilterMismatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/matchstore.Matchstore/FilterMismatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchstoreServer).FilterMismatches(ctx, req.(*QueryMismatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Matchstore_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchstoreServer).StreamEvents(m, &matchstoreStreamEventsServer{stream})
+}
+
+type Matchstore_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type matchstoreStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *matchstoreStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Matchstore_ServiceDesc is the grpc.ServiceDesc for Matchstore service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -279,7 +433,25 @@ var Matchstore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveMismatches",
 			Handler:    _Matchstore_RemoveMismatches_Handler,
 		},
+		{
+			MethodName: "FetchInstanceId",
+			Handler:    _Matchstore_FetchInstanceId_Handler,
+		},
+		{
+			MethodName: "FilterMatches",
+			Handler:    _Matchstore_FilterMatches_Handler,
+		},
+		{
+			MethodName: "FilterMismatches",
+			Handler:    _Matchstore_FilterMismatches_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Matchstore_StreamEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "matchstore/matchstore.proto",
 }
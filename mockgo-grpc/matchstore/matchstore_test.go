@@ -1,6 +1,7 @@
 package matchstore
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"net/url"
@@ -37,7 +38,7 @@ func getClusterAddresses() []string {
 func startMatchstoreCluster() {
 	addresses := getClusterAddresses()
 	for i := 0; i < clusterSize; i++ {
-		matchStore, err := NewGrpcMatchstore(addresses, startPort+i, uint16(100), "DEBUG")
+		matchStore, err := NewGrpcMatchstore(addresses, startPort+i, &matches.Retention{Capacity: 100}, "DEBUG")
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -203,3 +204,168 @@ func TestMatchstore_DeleteMismatches(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, mismatches)
 }
+
+func TestMatchstore_GetMatches_actualMessages(t *testing.T) {
+	endpointID := "websocketEndpoint"
+	matchstores[0].DeleteMatches(endpointID)
+	match := createMatch(endpointID)
+	match.ActualMessages = []*matches.ActualMessage{{Timestamp: timeStamp, Data: `{ "type": "subscribe" }`}, {Timestamp: timeStamp, Binary: true, Data: "\x00\x01"}}
+	assert.NoError(t, matchstores[1].AddMatch(endpointID, match))
+	fetchedMatches, err := matchstores[0].GetMatches(endpointID)
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMatches, 1) {
+		assert.Equal(t, match.ActualMessages, fetchedMatches[0].ActualMessages)
+	}
+}
+
+func TestMatchstore_GetMatches_mail(t *testing.T) {
+	endpointID := "smtp"
+	matchstores[0].DeleteMatches(endpointID)
+	match := createMatch(endpointID)
+	match.Mail = &matches.Mail{From: "shop@example.com", To: []string{"alice@example.com"}, Subject: "invoice", Header: map[string][]string{"Subject": {"invoice"}}, Size: 42,
+		Parts: []*matches.MailPart{{ContentType: "text/plain", Body: "hello"}, {ContentType: "application/pdf", Filename: "invoice.pdf", Binary: true, Body: "JVBERi0xLjQ="}}}
+	assert.NoError(t, matchstores[1].AddMatch(endpointID, match))
+	fetchedMatches, err := matchstores[0].GetMatches(endpointID)
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMatches, 1) {
+		assert.Equal(t, match.Mail, fetchedMatches[0].Mail)
+	}
+}
+
+func TestMatchstore_GetMatches_callbackAttempts(t *testing.T) {
+	endpointID := "createPayment-callback-1"
+	matchstores[0].DeleteMatches(endpointID)
+	match := createMatch(endpointID)
+	match.CallbackAttempts = []*matches.CallbackAttempt{{Timestamp: timeStamp, Header: map[string][]string{}, Error: "connection refused"},
+		{Timestamp: timeStamp, StatusCode: 200, Header: map[string][]string{"Content-Type": {"text/plain"}}, Body: "ack"}}
+	assert.NoError(t, matchstores[1].AddMatch(endpointID, match))
+	fetchedMatches, err := matchstores[0].GetMatches(endpointID)
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMatches, 1) {
+		assert.Equal(t, match.CallbackAttempts, fetchedMatches[0].CallbackAttempts)
+	}
+}
+
+func TestMatchstore_GetMatches_bodies(t *testing.T) {
+	endpointID := "createOrder"
+	matchstores[0].DeleteMatches(endpointID)
+	match := createMatch(endpointID)
+	match.ActualRequest.Header = map[string][]string{"Content-Type": {"application/json"}}
+	match.ActualRequest.RemoteAddr = "127.0.0.1:53302"
+	match.ActualRequest.Body = `{ "amount": 42 }`
+	match.ActualResponse = &matches.ActualResponse{StatusCode: http.StatusCreated, Header: map[string][]string{"Content-Type": {"image/png"}}, StreamedEvents: 2,
+		Body: "iVBORw0KGgo=", Binary: true, BodyTruncated: true, Duration: 152 * time.Microsecond}
+	assert.NoError(t, matchstores[1].AddMatch(endpointID, match))
+	fetchedMatches, err := matchstores[0].GetMatches(endpointID)
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMatches, 1) {
+		assert.Equal(t, match.ActualRequest, fetchedMatches[0].ActualRequest)
+		assert.Equal(t, match.ActualResponse, fetchedMatches[0].ActualResponse)
+	}
+
+	match = createMatch(endpointID)
+	match.ActualResponse = nil
+	assert.NoError(t, matchstores[1].AddMatch(endpointID, match))
+	fetchedMatches, err = matchstores[0].GetMatches(endpointID)
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMatches, 2) {
+		assert.Nil(t, fetchedMatches[1].ActualResponse)
+	}
+}
+
+func TestMatchstore_GetMismatches_nearestEndpoints(t *testing.T) {
+	matchstores[0].DeleteMismatches()
+	mismatch := createMismatch()
+	mismatch.NearestEndpoints = []*matches.NearestEndpoint{
+		{EndpointID: "minimal", Method: http.MethodGet, Path: "/minimal", PathDistance: 1,
+			Differences: []*matches.Difference{{Matcher: "path", Expected: "/minimal", Actual: "/minimol"}}},
+		{EndpointID: "maximal", Method: http.MethodPost, Path: "/maximal", PathDistance: 3,
+			Differences: []*matches.Difference{{Matcher: "path", Expected: "/maximal", Actual: "/minimol"}, {Matcher: "method", Expected: http.MethodPost, Actual: http.MethodGet}}},
+	}
+	assert.NoError(t, matchstores[1].AddMismatch(mismatch))
+	fetchedMismatches, err := matchstores[0].GetMismatches()
+	assert.NoError(t, err)
+	if assert.Len(t, fetchedMismatches, 1) {
+		assert.Equal(t, mismatch.NearestEndpoints, fetchedMismatches[0].NearestEndpoints)
+	}
+}
+
+func TestMatchstore_QueryMatches(t *testing.T) {
+	endpointID := "queryEndpoint"
+	matchstores[0].DeleteMatches(endpointID)
+	for i := 0; i < 6; i++ {
+		match := createMatchForRequest(endpointID, &http.Request{Method: []string{http.MethodGet, http.MethodPost}[i%2], URL: &url.URL{Path: "/orders"}})
+		match.Timestamp = timeStamp.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, matchstores[i%len(matchstores)].AddMatch(endpointID, match))
+	}
+	queriedMatches, err := matchstores[0].QueryMatches(endpointID, &matches.Query{Method: http.MethodPost, Offset: 1, Limit: 1, Descending: true})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMatches, 1) {
+		assert.Equal(t, timeStamp.Add(3*time.Second), queriedMatches[0].Timestamp.UTC())
+	}
+	queriedMatches, err = matchstores[1].QueryMatches(endpointID, &matches.Query{From: timeStamp.Add(time.Second), To: timeStamp.Add(3 * time.Second)})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMatches, 3) {
+		for i, match := range queriedMatches {
+			assert.Equal(t, timeStamp.Add(time.Duration(i+1)*time.Second), match.Timestamp.UTC())
+		}
+	}
+}
+
+func TestMatchstore_QueryMismatches(t *testing.T) {
+	matchstores[0].DeleteMismatches()
+	for i := 0; i < 4; i++ {
+		mismatch := createMismatchForRequest(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/orders/" + strconv.Itoa(i)}})
+		mismatch.Timestamp = timeStamp.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, matchstores[i%len(matchstores)].AddMismatch(mismatch))
+	}
+	queriedMismatches, err := matchstores[0].QueryMismatches(&matches.Query{Path: "/orders/*", Limit: 3})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMismatches, 3) {
+		for i, mismatch := range queriedMismatches {
+			assert.Equal(t, "/orders/"+strconv.Itoa(i), mismatch.ActualRequest.URL)
+		}
+	}
+}
+
+func TestMatchstore_IsLeader(t *testing.T) {
+	leaders := 0
+	for _, matchstore := range matchstores {
+		leader, err := matchstore.IsLeader()
+		assert.NoError(t, err)
+		if leader {
+			leaders++
+		}
+	}
+	assert.Equal(t, 1, leaders, "exactly one instance of the cluster must be the leader")
+}
+
+func TestMatchstore_Subscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := matchstores[0].Subscribe(ctx)
+	assert.NoError(t, err)
+	match := createMatch("eventEndpoint")
+	assert.NoError(t, matchstores[1].AddMatch("eventEndpoint", match))
+	mismatch := createMismatch()
+	assert.NoError(t, matchstores[0].AddMismatch(mismatch))
+
+	received := map[string]*matches.Event{}
+	for len(received) < 2 {
+		select {
+		case event := <-events:
+			received[event.Type] = event
+		case <-time.After(2 * time.Second):
+			assert.Fail(t, "missing events", "received %d events", len(received))
+			cancel()
+			return
+		}
+	}
+	assert.Equal(t, "eventEndpoint", received[matches.EventTypeMatch].Match.EndpointID)
+	assert.Equal(t, match.Timestamp, received[matches.EventTypeMatch].Match.Timestamp.UTC())
+	assert.Nil(t, received[matches.EventTypeMatch].Mismatch)
+	assert.Equal(t, mismatch.ActualRequest.URL, received[matches.EventTypeMismatch].Mismatch.ActualRequest.URL)
+
+	cancel()
+	for range events { // the channel is closed after all streams are closed
+	}
+}
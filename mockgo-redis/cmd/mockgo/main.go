@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/alitari/mockgo-server/mockgo-redis/kvstore"
+	"github.com/alitari/mockgo-server/mockgo-redis/leader"
 	"github.com/alitari/mockgo-server/mockgo-redis/matchstore"
 	"github.com/alitari/mockgo-server/mockgo/starter"
 	"github.com/kelseyhightower/envconfig"
@@ -32,16 +34,21 @@ func initialization() {
 Configuration is the configuration model of the server which is defined via environment variables
 */
 type Configuration struct {
-	RedisAddress      string `default:"localhost:6379" split_words:"true"`
-	RedisPassword     string `default:"" split_words:"true"`
-	MatchstoreRedisDB int    `default:"0" split_words:"true"`
-	KvstoreRedisDB    int    `default:"1" split_words:"true"`
+	RedisAddress      string        `default:"localhost:6379" split_words:"true"`
+	RedisPassword     string        `default:"" split_words:"true"`
+	MatchstoreRedisDB int           `default:"0" split_words:"true"`
+	KvstoreRedisDB    int           `default:"1" split_words:"true"`
+	LeaderRedisDB     int           `default:"2" split_words:"true"`
+	LeaderLease       time.Duration `default:"15s" split_words:"true"`
 }
 
 func (c *Configuration) validate() error {
 	if c.MatchstoreRedisDB == c.KvstoreRedisDB {
 		return fmt.Errorf("redis db for matchstore and kvstore must be different")
 	}
+	if c.LeaderRedisDB == c.MatchstoreRedisDB || c.LeaderRedisDB == c.KvstoreRedisDB {
+		return fmt.Errorf("redis db for leader election must be different from the redis db for matchstore and kvstore")
+	}
 	return nil
 }
 
@@ -60,16 +67,18 @@ Redis:
   Password: '%s' ("REDIS_PASSWORD")
   Matchstore Database: %d ("MATCHSTORE_REDIS_DB")
   KVStore Database: %d ("KVSTORE_REDIS_DB")
+  Leader Database: %d ("LEADER_REDIS_DB")
+  Leader lease: %v ("LEADER_LEASE")
 
 Redis:
 `,
-		c.RedisAddress, passwordInfo(c.RedisPassword), c.MatchstoreRedisDB, c.KvstoreRedisDB)
+		c.RedisAddress, passwordInfo(c.RedisPassword), c.MatchstoreRedisDB, c.KvstoreRedisDB, c.LeaderRedisDB, c.LeaderLease)
 
 }
 
 func main() {
 	matchStore, err := matchstore.NewRedisMatchstore(config.RedisAddress, config.RedisPassword,
-		config.MatchstoreRedisDB, uint16(starter.BasicConfig.MatchesCapacity))
+		config.MatchstoreRedisDB, starter.BasicConfig.MatchesRetention())
 	if err != nil {
 		log.Fatalf("can't initialize redis matchstore: %v", err)
 	}
@@ -80,6 +89,13 @@ func main() {
 		log.Fatalf("can't initialize redis kvstore: %v", err)
 	}
 
+	leaderElection, err := leader.NewRedisLeaderElection(config.RedisAddress, config.RedisPassword,
+		config.LeaderRedisDB, config.LeaderLease)
+	if err != nil {
+		log.Fatalf("can't initialize redis leader election: %v", err)
+	}
+	starter.LeaderElection = leaderElection
+
 	starter.SetupRouter(variant, versionTag, config.info(), matchStore, kvStore)
 
 }
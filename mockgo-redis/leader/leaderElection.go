@@ -0,0 +1,68 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const leaderKey = "__leader__"
+
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// RedisLeaderElection is a mock.LeaderElection implementation using a lease in redis.
+// The instance which holds the lease is the leader, the lease is renewed with every call of IsLeader.
+type RedisLeaderElection struct {
+	client *redis.Client
+	id     string
+	lease  time.Duration
+}
+
+// NewRedisLeaderElection creates a new mock.LeaderElection using redis as backend.
+func NewRedisLeaderElection(address, password string, db int, lease time.Duration) (*RedisLeaderElection, error) {
+	leaderElection := &RedisLeaderElection{
+		client: redis.NewClient(&redis.Options{
+			Addr:     address,
+			Password: password,
+			DB:       db,
+		}),
+		id:    uuid.New().String(),
+		lease: lease,
+	}
+	if err := leaderElection.client.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+	return leaderElection, nil
+}
+
+// IsLeader acquires the lease when it is free or renews it when this instance holds it.
+func (r *RedisLeaderElection) IsLeader() (bool, error) {
+	ctx := context.Background()
+	acquired, err := r.client.SetNX(ctx, leaderKey, r.id, r.lease).Result()
+	if err != nil {
+		return false, err
+	}
+	if acquired {
+		return true, nil
+	}
+	renewed, err := renewScript.Run(ctx, r.client, []string{leaderKey}, r.id, r.lease.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+// Shutdown releases the lease when this instance holds it.
+func (r *RedisLeaderElection) Shutdown() error {
+	ctx := context.Background()
+	if leader, err := r.client.Get(ctx, leaderKey).Result(); err == nil && leader == r.id {
+		r.client.Del(ctx, leaderKey)
+	}
+	return r.client.Close()
+}
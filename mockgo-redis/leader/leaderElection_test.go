@@ -0,0 +1,48 @@
+package leader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisLeaderElection_IsLeader(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	first, err := NewRedisLeaderElection(redisServer.Addr(), "", 1, 10*time.Second)
+	assert.NoError(t, err)
+	second, err := NewRedisLeaderElection(redisServer.Addr(), "", 1, 10*time.Second)
+	assert.NoError(t, err)
+
+	leader, err := first.IsLeader()
+	assert.NoError(t, err)
+	assert.True(t, leader)
+	leader, err = second.IsLeader()
+	assert.NoError(t, err)
+	assert.False(t, leader)
+
+	redisServer.FastForward(8 * time.Second)
+	leader, err = first.IsLeader()
+	assert.NoError(t, err)
+	assert.True(t, leader, "lease must be renewed")
+	redisServer.FastForward(8 * time.Second)
+	leader, err = second.IsLeader()
+	assert.NoError(t, err)
+	assert.False(t, leader, "renewed lease must not expire")
+
+	redisServer.FastForward(11 * time.Second)
+	leader, err = second.IsLeader()
+	assert.NoError(t, err)
+	assert.True(t, leader, "expired lease must be acquired")
+
+	assert.NoError(t, second.Shutdown())
+	leader, err = first.IsLeader()
+	assert.NoError(t, err)
+	assert.True(t, leader, "released lease must be acquired")
+}
+
+func TestRedisLeaderElection_noRedis(t *testing.T) {
+	_, err := NewRedisLeaderElection("localhost:1", "", 1, time.Second)
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package matchstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/alitari/mockgo-server/mockgo/matches"
@@ -12,9 +13,28 @@ import (
 const mismatchesKey = "__mismatches__"
 const counterKey = "__counter__"
 
+// eventsChannel is the pub/sub channel for the events of the matches and mismatches of all instances
+const eventsChannel = "__events__"
+
+// indexKey is the sorted set of all stored matches and mismatches in the order of insertion, it is only maintained
+// when the retention has a ttl or a memory limit
+const indexKey = "__index__"
+
+// sequenceKey is the suffix of the sequences of the values of a list and of the index
+const sequenceKey = "__sequence__"
+
+// timestampsKey is the hash of the timestamps of the members of the index
+const timestampsKey = "__timestamps__"
+
+// sizeKey is the total size in bytes of all stored matches and mismatches
+const sizeKey = "__size__"
+
+// queryChunkSize is the count of list entries which are read at once from redis when a query is executed
+const queryChunkSize = 100
+
 type redisMatchstore struct {
-	client   *redis.Client
-	capacity uint16
+	client    *redis.Client
+	retention *matches.Retention
 }
 
 func (r *redisMatchstore) checkConnectivity() error {
@@ -25,6 +45,9 @@ func (r *redisMatchstore) checkConnectivity() error {
 
 func (r *redisMatchstore) GetMatches(endpointID string) ([]*matches.Match, error) {
 	ctx := context.Background()
+	if err := r.evictExpired(ctx); err != nil {
+		return nil, err
+	}
 	lrange := r.client.LRange(ctx, endpointID, 0, -1)
 	if lrange.Err() != nil {
 		return nil, lrange.Err()
@@ -52,6 +75,9 @@ func (r *redisMatchstore) GetMatchesCount(endpointID string) (uint64, error) {
 
 func (r *redisMatchstore) GetMismatches() ([]*matches.Mismatch, error) {
 	ctx := context.Background()
+	if err := r.evictExpired(ctx); err != nil {
+		return nil, err
+	}
 	lrange := r.client.LRange(ctx, mismatchesKey, 0, -1)
 	if lrange.Err() != nil {
 		return nil, lrange.Err()
@@ -68,27 +94,110 @@ func (r *redisMatchstore) GetMismatches() ([]*matches.Mismatch, error) {
 	return result, nil
 }
 
+/*
+QueryMatches reads the matches of an endpoint chunk by chunk in the order of the query until the page is complete
+*/
+func (r *redisMatchstore) QueryMatches(endpointID string, query *matches.Query) ([]*matches.Match, error) {
+	if err := r.evictExpired(context.Background()); err != nil {
+		return nil, err
+	}
+	result := []*matches.Match{}
+	skipped := 0
+	err := r.scanList(endpointID, query.Descending, func(value string) (bool, error) {
+		var match matches.Match
+		if err := json.Unmarshal([]byte(value), &match); err != nil {
+			return false, err
+		}
+		if !query.MatchesMatch(&match) {
+			return true, nil
+		}
+		if skipped < query.Offset {
+			skipped++
+			return true, nil
+		}
+		result = append(result, &match)
+		return len(result) != query.Limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+QueryMismatches reads the mismatches chunk by chunk in the order of the query until the page is complete
+*/
+func (r *redisMatchstore) QueryMismatches(query *matches.Query) ([]*matches.Mismatch, error) {
+	if err := r.evictExpired(context.Background()); err != nil {
+		return nil, err
+	}
+	result := []*matches.Mismatch{}
+	skipped := 0
+	err := r.scanList(mismatchesKey, query.Descending, func(value string) (bool, error) {
+		var mismatch matches.Mismatch
+		if err := json.Unmarshal([]byte(value), &mismatch); err != nil {
+			return false, err
+		}
+		if !query.MatchesMismatch(&mismatch) {
+			return true, nil
+		}
+		if skipped < query.Offset {
+			skipped++
+			return true, nil
+		}
+		result = append(result, &mismatch)
+		return len(result) != query.Limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+scanList visits the values of a list from the head or from the tail, until visit returns false
+*/
+func (r *redisMatchstore) scanList(key string, fromTail bool, visit func(value string) (bool, error)) error {
+	ctx := context.Background()
+	for chunk := int64(0); ; chunk++ {
+		start, stop := chunk*queryChunkSize, (chunk+1)*queryChunkSize-1
+		if fromTail {
+			start, stop = -stop-1, -start-1
+		}
+		lrange := r.client.LRange(ctx, key, start, stop)
+		if lrange.Err() != nil {
+			return lrange.Err()
+		}
+		values := lrange.Val()
+		for i := range values {
+			value := values[i]
+			if fromTail {
+				value = values[len(values)-1-i]
+			}
+			next, err := visit(value)
+			if err != nil || !next {
+				return err
+			}
+		}
+		if len(values) < queryChunkSize {
+			return nil
+		}
+	}
+}
+
 func (r *redisMatchstore) AddMatch(endpointID string, match *matches.Match) error {
 	ctx := context.Background()
 	mval, err := json.Marshal(match)
 	if err != nil {
 		return err
 	}
-	rpush := r.client.RPush(ctx, endpointID, mval)
-	if rpush.Err() != nil {
-		return rpush.Err()
-	}
-	if r.client.LLen(ctx, endpointID).Val() > int64(r.capacity) {
-		lpop := r.client.LPop(ctx, endpointID)
-		if lpop.Err() != nil {
-			return lpop.Err()
-		}
+	if err := r.push(ctx, endpointID, mval, match.Timestamp); err != nil {
+		return err
 	}
-	incr := r.client.Incr(ctx, endpointID+counterKey)
-	if incr.Err() != nil {
-		return incr.Err()
+	if err := r.evict(ctx); err != nil {
+		return err
 	}
-	return nil
+	return r.publish(ctx, &matches.Event{Type: matches.EventTypeMatch, Match: match})
 }
 
 func (r *redisMatchstore) AddMismatch(mismatch *matches.Mismatch) error {
@@ -97,23 +206,119 @@ func (r *redisMatchstore) AddMismatch(mismatch *matches.Mismatch) error {
 	if err != nil {
 		return err
 	}
-	lpush := r.client.RPush(ctx, mismatchesKey, mval)
-	if lpush.Err() != nil {
-		return lpush.Err()
+	if err := r.push(ctx, mismatchesKey, mval, mismatch.Timestamp); err != nil {
+		return err
+	}
+	if err := r.evict(ctx); err != nil {
+		return err
 	}
-	if r.client.LLen(ctx, mismatchesKey).Val() > int64(r.capacity) {
-		lpop := r.client.LPop(ctx, mismatchesKey)
-		if lpop.Err() != nil {
-			return lpop.Err()
-		}
+	return r.publish(ctx, &matches.Event{Type: matches.EventTypeMismatch, Mismatch: mismatch})
+}
+
+// indexed returns true when the retention needs the index of all stored matches and mismatches
+func (r *redisMatchstore) indexed() bool {
+	return r.retention.TTL > 0 || r.retention.MemoryLimit > 0
+}
+
+// push appends the value to the list, increments the counter and removes the head of the list, when the capacity is exceeded
+func (r *redisMatchstore) push(ctx context.Context, key string, value []byte, timestamp time.Time) error {
+	keys := []string{key, key + counterKey, key + sequenceKey, indexKey, indexKey + sequenceKey, timestampsKey, sizeKey}
+	evicted, err := pushScript.Run(ctx, r.client, keys, value, r.retention.Capacity, r.indexed(), unixMicro(timestamp)).Int()
+	if err != nil {
+		return err
 	}
-	incr := r.client.Incr(ctx, mismatchesKey+counterKey)
-	if incr.Err() != nil {
-		return incr.Err()
+	if evicted > 0 {
+		matches.RecordEvictions(eventType(key), matches.EvictionReasonCapacity, evicted)
 	}
 	return nil
 }
 
+// evictExpired evicts the expired matches and mismatches before they are read
+func (r *redisMatchstore) evictExpired(ctx context.Context) error {
+	if r.retention.TTL == 0 {
+		return nil
+	}
+	return r.evict(ctx)
+}
+
+// evict removes the oldest matches and mismatches of all lists as long as they are expired or the memory limit is exceeded
+func (r *redisMatchstore) evict(ctx context.Context) error {
+	if !r.indexed() {
+		return nil
+	}
+	keys := []string{indexKey, timestampsKey, sizeKey}
+	now := unixMicro(time.Now())
+	for {
+		evicted, err := evictScript.Run(ctx, r.client, keys, now, r.retention.TTL.Microseconds(), r.retention.MemoryLimit,
+			matches.EvictionReasonMemory, matches.EvictionReasonTTL).StringSlice()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		matches.RecordEvictions(eventType(evicted[1]), evicted[0], 1)
+	}
+}
+
+// unixMicro returns the microseconds of the timestamp since the epoch, 0 for a zero timestamp which never expires
+func unixMicro(timestamp time.Time) int64 {
+	if timestamp.IsZero() {
+		return 0
+	}
+	return timestamp.UnixMicro()
+}
+
+// eventType returns the type of the values of the list
+func eventType(key string) string {
+	if key == mismatchesKey {
+		return matches.EventTypeMismatch
+	}
+	return matches.EventTypeMatch
+}
+
+func (r *redisMatchstore) publish(ctx context.Context, event *matches.Event) error {
+	eval, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, eventsChannel, eval).Err()
+}
+
+// Subscribe receives the events of all instances through the redis pub/sub channel until the context is done
+func (r *redisMatchstore) Subscribe(ctx context.Context) (<-chan *matches.Event, error) {
+	pubsub := r.client.Subscribe(ctx, eventsChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	events := make(chan *matches.Event)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event matches.Event
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					continue
+				}
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
 func (r *redisMatchstore) GetMismatchesCount() (uint64, error) {
 	ctx := context.Background()
 	get := r.client.Get(ctx, mismatchesKey+counterKey)
@@ -124,37 +329,25 @@ func (r *redisMatchstore) GetMismatchesCount() (uint64, error) {
 }
 
 func (r *redisMatchstore) DeleteMatches(endpointID string) error {
-	ctx := context.Background()
-	del := r.client.Del(ctx, endpointID)
-	if del.Err() != nil {
-		return del.Err()
-	}
-	set := r.client.Set(ctx, endpointID+counterKey, 0, 0)
-	if set.Err() != nil {
-		return set.Err()
-	}
-	return nil
+	return r.delete(context.Background(), endpointID)
 }
 
 func (r *redisMatchstore) DeleteMismatches() error {
-	ctx := context.Background()
-	del := r.client.Del(ctx, mismatchesKey)
-	if del.Err() != nil {
-		return del.Err()
-	}
-	set := r.client.Set(ctx, mismatchesKey+counterKey, 0, 0)
-	if set.Err() != nil {
-		return set.Err()
-	}
-	return nil
+	return r.delete(context.Background(), mismatchesKey)
+}
+
+// delete removes the list with its entries in the index and resets the counter
+func (r *redisMatchstore) delete(ctx context.Context, key string) error {
+	keys := []string{key, key + counterKey, key + sequenceKey, indexKey, timestampsKey, sizeKey}
+	return deleteScript.Run(ctx, r.client, keys, r.indexed()).Err()
 }
 
 func (r *redisMatchstore) Shutdown() error {
 	return r.client.Close()
 }
 
-// NewRedisMatchstore creates a new redis matchstore
-func NewRedisMatchstore(address, password string, db int, capacity uint16) (matches.Matchstore, error) {
+// NewRedisMatchstore creates a new redis matchstore, which evicts matches and mismatches of all instances according to the retention
+func NewRedisMatchstore(address, password string, db int, retention *matches.Retention) (matches.Matchstore, error) {
 	matchstore := &redisMatchstore{
 		client: redis.NewClient(&redis.Options{
 			Addr:            address,
@@ -164,7 +357,7 @@ func NewRedisMatchstore(address, password string, db int, capacity uint16) (matc
 			MinRetryBackoff: 500 * time.Millisecond,
 			MaxRetryBackoff: 2 * time.Second,
 		}),
-		capacity: capacity,
+		retention: retention,
 	}
 	if err := matchstore.checkConnectivity(); err != nil {
 		return nil, err
@@ -1,9 +1,13 @@
 package matchstore
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -22,16 +26,21 @@ func createRedisMatchstore(capacity int) {
 	mock.ClearExpect()
 	clientmock = mock
 	matchstore = &redisMatchstore{
-		client: client,
+		client:    client,
+		retention: &matches.Retention{Capacity: capacity},
 	}
 }
 func createMiniRedisMatchstore(capacity int) {
+	createMiniRedisMatchstoreWithRetention(&matches.Retention{Capacity: capacity})
+}
+
+func createMiniRedisMatchstoreWithRetention(retention *matches.Retention) {
 	miniredis := miniredis.NewMiniRedis()
 	err := miniredis.Start()
 	if err != nil {
 		panic(err)
 	}
-	matchstore, err = NewRedisMatchstore(miniredis.Addr(), "", 0, uint16(capacity))
+	matchstore, err = NewRedisMatchstore(miniredis.Addr(), "", 0, retention)
 	if err != nil {
 		panic(err)
 	}
@@ -74,6 +83,14 @@ func createMismatchString(mismatch *matches.Mismatch) string {
 	return string(mismatchStr)
 }
 
+func createEventString(event *matches.Event) string {
+	eventStr, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+	return string(eventStr)
+}
+
 func createMatchForRequest(endpointID string, request *http.Request) *matches.Match {
 	actualRequest := &matches.ActualRequest{Method: request.Method, URL: request.URL.String(), Header: request.Header, Host: request.Host}
 	actualResponse := &matches.ActualResponse{StatusCode: http.StatusOK, Header: map[string][]string{"key1": {"val1"}}}
@@ -92,6 +109,16 @@ func createMismatchForRequest(request *http.Request) *matches.Mismatch {
 	return mismatch
 }
 
+func expectPush(key, value string, capacity int, evicted int64) {
+	keys := []string{key, key + counterKey, key + sequenceKey, indexKey, indexKey + sequenceKey, timestampsKey, sizeKey}
+	clientmock.ExpectEvalSha(pushScript.Hash(), keys, []byte(value), capacity, false, timeStamp.UnixMicro()).SetVal(evicted)
+}
+
+func expectDelete(key string) {
+	keys := []string{key, key + counterKey, key + sequenceKey, indexKey, timestampsKey, sizeKey}
+	clientmock.ExpectEvalSha(deleteScript.Hash(), keys, false).SetVal(int64(0))
+}
+
 func TestRedisMatchstore_GetMatches(t *testing.T) {
 	createRedisMatchstore(10)
 	endpoint := "myendpoint"
@@ -102,6 +129,34 @@ func TestRedisMatchstore_GetMatches(t *testing.T) {
 	assert.EqualValues(t, []*matches.Match{match}, getMatches)
 }
 
+func TestRedisMatchstore_GetMatches_mail(t *testing.T) {
+	createRedisMatchstore(10)
+	endpoint := "smtp"
+	match := createMatch(endpoint)
+	match.Mail = &matches.Mail{From: "shop@example.com", To: []string{"alice@example.com"}, Subject: "invoice", Header: map[string][]string{"Subject": {"invoice"}}, Size: 42,
+		Parts: []*matches.MailPart{{ContentType: "text/plain", Body: "hello"}, {ContentType: "application/pdf", Filename: "invoice.pdf", Binary: true, Body: "JVBERi0xLjQ="}}}
+	clientmock.ExpectLRange(endpoint, 0, -1).SetVal([]string{createMatchString(match)})
+	getMatches, err := matchstore.GetMatches(endpoint)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*matches.Match{match}, getMatches)
+}
+
+func TestRedisMatchstore_GetMatches_bodies(t *testing.T) {
+	createRedisMatchstore(10)
+	endpoint := "createOrder"
+	match := createMatch(endpoint)
+	match.ActualRequest.RemoteAddr = "127.0.0.1:53302"
+	match.ActualRequest.Body = `{ "amount": 42 }`
+	match.ActualResponse.Body = "iVBORw0KGgo="
+	match.ActualResponse.Binary = true
+	match.ActualResponse.BodyTruncated = true
+	match.ActualResponse.Duration = 152 * time.Microsecond
+	clientmock.ExpectLRange(endpoint, 0, -1).SetVal([]string{createMatchString(match)})
+	getMatches, err := matchstore.GetMatches(endpoint)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*matches.Match{match}, getMatches)
+}
+
 func TestRedisMatchstore_GetMatchesCount(t *testing.T) {
 	createRedisMatchstore(10)
 	endpoint := "myendpoint"
@@ -120,6 +175,17 @@ func TestRedisMatchstore_GetMismatches(t *testing.T) {
 	assert.EqualValues(t, []*matches.Mismatch{mismatch}, getMismatches)
 }
 
+func TestRedisMatchstore_GetMismatches_nearestEndpoints(t *testing.T) {
+	createRedisMatchstore(10)
+	mismatch := createMismatch()
+	mismatch.NearestEndpoints = []*matches.NearestEndpoint{{EndpointID: "minimal", Method: http.MethodGet, Path: "/minimal", PathDistance: 1,
+		Differences: []*matches.Difference{{Matcher: "path", Expected: "/minimal", Actual: "/minimol"}}}}
+	clientmock.ExpectLRange(mismatchesKey, 0, -1).SetVal([]string{createMismatchString(mismatch)})
+	getMismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*matches.Mismatch{mismatch}, getMismatches)
+}
+
 func TestRedisMatchstore_GetMisMatchesCount(t *testing.T) {
 	createRedisMatchstore(10)
 	clientmock.ExpectGet(mismatchesKey + counterKey).SetVal("81")
@@ -132,36 +198,60 @@ func TestRedisMatchstore_AddMatch(t *testing.T) {
 	createRedisMatchstore(10)
 	endpoint := "myendpoint"
 	match := createMatch(endpoint)
-	clientmock.ExpectRPush(endpoint, []byte(createMatchString(match))).SetVal(0)
-	clientmock.ExpectIncr(endpoint + counterKey).SetVal(1)
+	expectPush(endpoint, createMatchString(match), 10, 0)
+	clientmock.ExpectPublish(eventsChannel, []byte(createEventString(&matches.Event{Type: matches.EventTypeMatch, Match: match}))).SetVal(0)
 	err := matchstore.AddMatch(endpoint, match)
 	assert.NoError(t, err)
+	assert.NoError(t, clientmock.ExpectationsWereMet())
 }
 
 func TestRedisMatchstore_AddMismatch(t *testing.T) {
 	createRedisMatchstore(10)
 	mismatch := createMismatch()
-	clientmock.ExpectRPush(mismatchesKey, []byte(createMismatchString(mismatch))).SetVal(0)
-	clientmock.ExpectIncr(mismatchesKey + counterKey).SetVal(1)
+	expectPush(mismatchesKey, createMismatchString(mismatch), 10, 0)
+	clientmock.ExpectPublish(eventsChannel, []byte(createEventString(&matches.Event{Type: matches.EventTypeMismatch, Mismatch: mismatch}))).SetVal(0)
 	err := matchstore.AddMismatch(mismatch)
 	assert.NoError(t, err)
+	assert.NoError(t, clientmock.ExpectationsWereMet())
+}
+
+func TestRedisMatchstore_Subscribe(t *testing.T) {
+	createMiniRedisMatchstore(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := matchstore.Subscribe(ctx)
+	assert.NoError(t, err)
+	match := createMatch("eventEndpoint")
+	assert.NoError(t, matchstore.AddMatch("eventEndpoint", match))
+	mismatch := createMismatch()
+	assert.NoError(t, matchstore.AddMismatch(mismatch))
+	assert.Equal(t, &matches.Event{Type: matches.EventTypeMatch, Match: match}, <-events)
+	assert.Equal(t, &matches.Event{Type: matches.EventTypeMismatch, Mismatch: mismatch}, <-events)
+	cancel()
+	for range events { // the channel is closed when the context is done
+	}
+}
+
+func TestRedisMatchstore_Subscribe_Error(t *testing.T) {
+	createRedisMatchstore(10)
+	_, err := matchstore.Subscribe(context.Background())
+	assert.Error(t, err)
 }
 
 func TestRedisMatchstore_DeleteMatches(t *testing.T) {
 	createRedisMatchstore(10)
 	endpoint := "myendpoint"
-	clientmock.ExpectDel(endpoint).SetVal(0)
-	clientmock.ExpectSet(endpoint+counterKey, 0, 0).SetVal("0")
+	expectDelete(endpoint)
 	err := matchstore.DeleteMatches(endpoint)
 	assert.NoError(t, err)
+	assert.NoError(t, clientmock.ExpectationsWereMet())
 }
 
 func TestRedisMatchstore_DeleteMismatches(t *testing.T) {
 	createRedisMatchstore(10)
-	clientmock.ExpectDel(mismatchesKey).SetVal(0)
-	clientmock.ExpectSet(mismatchesKey+counterKey, 0, 0).SetVal("0")
+	expectDelete(mismatchesKey)
 	err := matchstore.DeleteMismatches()
 	assert.NoError(t, err)
+	assert.NoError(t, clientmock.ExpectationsWereMet())
 }
 
 func TestRedisMatchstore_LimitedCapacity(t *testing.T) {
@@ -170,19 +260,19 @@ func TestRedisMatchstore_LimitedCapacity(t *testing.T) {
 	match1 := createMatch(endpoint)
 	match2 := createMatch(endpoint)
 	match3 := createMatch(endpoint)
-	clientmock.ExpectRPush(endpoint, []byte(createMatchString(match1))).SetVal(0)
-	clientmock.ExpectIncr(endpoint + counterKey).SetVal(1)
-	clientmock.ExpectRPush(endpoint, []byte(createMatchString(match2))).SetVal(0)
-	clientmock.ExpectIncr(endpoint + counterKey).SetVal(2)
-	clientmock.ExpectRPush(endpoint, []byte(createMatchString(match3))).SetVal(0)
-	clientmock.ExpectIncr(endpoint + counterKey).SetVal(3)
-	clientmock.ExpectLPop(endpoint).SetVal(createMatchString(match1))
+	expectPush(endpoint, createMatchString(match1), 2, 0)
+	clientmock.ExpectPublish(eventsChannel, []byte(createEventString(&matches.Event{Type: matches.EventTypeMatch, Match: match1}))).SetVal(0)
+	expectPush(endpoint, createMatchString(match2), 2, 0)
+	clientmock.ExpectPublish(eventsChannel, []byte(createEventString(&matches.Event{Type: matches.EventTypeMatch, Match: match2}))).SetVal(0)
+	expectPush(endpoint, createMatchString(match3), 2, 1)
+	clientmock.ExpectPublish(eventsChannel, []byte(createEventString(&matches.Event{Type: matches.EventTypeMatch, Match: match3}))).SetVal(0)
 	err := matchstore.AddMatch(endpoint, match1)
 	assert.NoError(t, err)
 	err = matchstore.AddMatch(endpoint, match2)
 	assert.NoError(t, err)
 	err = matchstore.AddMatch(endpoint, match3)
 	assert.NoError(t, err)
+	assert.NoError(t, clientmock.ExpectationsWereMet())
 }
 
 func TestRedisMatchstore_GetMatchesSort(t *testing.T) {
@@ -319,3 +409,147 @@ func TestRedisMatchstore_MismatchesCapacity(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(3), mismatchesCount)
 }
+
+func TestRedisMatchstore_QueryMatches(t *testing.T) {
+	createMiniRedisMatchstore(1000)
+	endpoint := "queryEndpoint"
+	for i := 0; i < 250; i++ {
+		match := createMatchForRequest(endpoint, &http.Request{Method: []string{http.MethodGet, http.MethodPost}[i%2], URL: &url.URL{Path: "/orders/" + strconv.Itoa(i)}})
+		match.Timestamp = timeStamp.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, matchstore.AddMatch(endpoint, match))
+	}
+	queriedMatches, err := matchstore.QueryMatches(endpoint, &matches.Query{Method: http.MethodPost, Offset: 60, Limit: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMatches, 2) {
+		assert.Equal(t, "/orders/121", queriedMatches[0].ActualRequest.URL)
+		assert.Equal(t, "/orders/123", queriedMatches[1].ActualRequest.URL)
+	}
+	queriedMatches, err = matchstore.QueryMatches(endpoint, &matches.Query{To: timeStamp.Add(120 * time.Second), Descending: true, Limit: 150})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMatches, 121) {
+		assert.Equal(t, "/orders/120", queriedMatches[0].ActualRequest.URL)
+		assert.Equal(t, "/orders/0", queriedMatches[120].ActualRequest.URL)
+	}
+	queriedMatches, err = matchstore.QueryMatches(endpoint, &matches.Query{StatusCode: http.StatusNotFound})
+	assert.NoError(t, err)
+	assert.Empty(t, queriedMatches)
+}
+
+func TestRedisMatchstore_QueryMismatches(t *testing.T) {
+	createMiniRedisMatchstore(1000)
+	for i := 0; i < 150; i++ {
+		mismatch := createMismatchForRequest(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/orders/" + strconv.Itoa(i)}})
+		assert.NoError(t, matchstore.AddMismatch(mismatch))
+	}
+	queriedMismatches, err := matchstore.QueryMismatches(&matches.Query{Path: "/orders/1?", Descending: true})
+	assert.NoError(t, err)
+	if assert.Len(t, queriedMismatches, 10) {
+		assert.Equal(t, "/orders/19", queriedMismatches[0].ActualRequest.URL)
+		assert.Equal(t, "/orders/10", queriedMismatches[9].ActualRequest.URL)
+	}
+}
+
+func TestRedisMatchstore_QueryMatches_error(t *testing.T) {
+	createRedisMatchstore(10)
+	endpoint := "myendpoint"
+	clientmock.ExpectLRange(endpoint, 0, queryChunkSize-1).SetVal([]string{"no json"})
+	_, err := matchstore.QueryMatches(endpoint, &matches.Query{})
+	assert.Error(t, err)
+	clientmock.ExpectLRange(mismatchesKey, -queryChunkSize, -1).SetErr(fmt.Errorf("connection refused"))
+	_, err = matchstore.QueryMismatches(&matches.Query{Descending: true})
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestRedisMatchstore_Retention_TTL(t *testing.T) {
+	createMiniRedisMatchstoreWithRetention(&matches.Retention{TTL: time.Minute})
+	client := matchstore.(*redisMatchstore).client
+	endpoint := "ttlEndpoint"
+	recentMatch := createMatch(endpoint)
+	recentMatch.Timestamp = time.Now().UTC()
+	assert.NoError(t, matchstore.AddMatch(endpoint, createMatch(endpoint)))
+	assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+	assert.NoError(t, matchstore.AddMatch(endpoint, recentMatch))
+
+	storedMatches, err := matchstore.GetMatches(endpoint)
+	assert.NoError(t, err)
+	if assert.Len(t, storedMatches, 1) {
+		assert.True(t, recentMatch.Timestamp.Equal(storedMatches[0].Timestamp))
+	}
+	storedMismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	assert.Empty(t, storedMismatches)
+	count, err := matchstore.GetMatchesCount(endpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, []string{"2:" + endpoint}, client.ZRange(context.Background(), indexKey, 0, -1).Val())
+	assert.Equal(t, []string{"2:" + endpoint}, client.HKeys(context.Background(), timestampsKey).Val())
+}
+
+func TestRedisMatchstore_Retention_MemoryLimit(t *testing.T) {
+	matchSize := int64(len(createMatchString(createMatch("memoryEndpoint1"))))
+	createMiniRedisMatchstoreWithRetention(&matches.Retention{Capacity: 1, MemoryLimit: 2*matchSize + 1})
+	client := matchstore.(*redisMatchstore).client
+	addMatchesForEndpoint(t, "memoryEndpoint1", 1)
+	addMatchesForEndpoint(t, "memoryEndpoint2", 1)
+	assert.Equal(t, []string{"1:memoryEndpoint1", "1:memoryEndpoint2"}, client.ZRange(context.Background(), indexKey, 0, -1).Val())
+	addMatchesForEndpoint(t, "memoryEndpoint2", 1)
+	assert.Equal(t, []string{"1:memoryEndpoint1", "2:memoryEndpoint2"}, client.ZRange(context.Background(), indexKey, 0, -1).Val())
+	addMatchesForEndpoint(t, "memoryEndpoint3", 1)
+
+	assert.Equal(t, []string{"2:memoryEndpoint2", "1:memoryEndpoint3"}, client.ZRange(context.Background(), indexKey, 0, -1).Val())
+	assert.Equal(t, int64(0), client.LLen(context.Background(), "memoryEndpoint1").Val())
+	size, err := client.Get(context.Background(), sizeKey).Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*matchSize, size)
+
+	assert.NoError(t, matchstore.DeleteMatches("memoryEndpoint2"))
+	assert.Equal(t, []string{"1:memoryEndpoint3"}, client.ZRange(context.Background(), indexKey, 0, -1).Val())
+	size, err = client.Get(context.Background(), sizeKey).Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, matchSize, size)
+}
+
+func TestRedisMatchstore_Retention_concurrentInstances(t *testing.T) {
+	miniredis := miniredis.RunT(t)
+	retention := &matches.Retention{Capacity: 3, TTL: time.Hour, MemoryLimit: 20 * int64(len(createMatchString(createMatch("endpoint0"))))}
+	var instances []matches.Matchstore
+	for i := 0; i < 2; i++ {
+		instance, err := NewRedisMatchstore(miniredis.Addr(), "", 0, retention)
+		assert.NoError(t, err)
+		instances = append(instances, instance)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			instance := instances[g%len(instances)]
+			for i := 0; i < 50; i++ {
+				endpoint := "endpoint" + strconv.Itoa((g+i)%10)
+				assert.NoError(t, instance.AddMatch(endpoint, createMatch(endpoint)))
+				if i%20 == 0 {
+					assert.NoError(t, instance.DeleteMatches(endpoint))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	ctx := context.Background()
+	client := instances[0].(*redisMatchstore).client
+	listed, size := 0, int64(0)
+	for i := 0; i < 10; i++ {
+		values := client.LRange(ctx, "endpoint"+strconv.Itoa(i), 0, -1).Val()
+		assert.LessOrEqual(t, len(values), retention.Capacity)
+		listed += len(values)
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	assert.Equal(t, int64(listed), client.ZCard(ctx, indexKey).Val())
+	assert.Equal(t, int64(listed), client.HLen(ctx, timestampsKey).Val())
+	storedSize, err := client.Get(ctx, sizeKey).Int64()
+	assert.NoError(t, err)
+	assert.Equal(t, size, storedSize)
+	assert.LessOrEqual(t, storedSize, retention.MemoryLimit)
+}
@@ -0,0 +1,85 @@
+package matchstore
+
+import "github.com/redis/go-redis/v9"
+
+// The scripts keep the lists, the index, the timestamps and the size consistent, when several instances add, evict
+// and delete at the same time. A member of the index is '<sequence of the value in its list>:<key of the list>', its
+// score is the sequence of all values, so that the oldest value of all lists is the first member of the index.
+
+// pushScript appends a value to a list and removes the head of the list, when the capacity is exceeded.
+// KEYS: list, counter, list sequence, index, index sequence, timestamps, size
+// ARGV: value, capacity, indexed, timestamp in microseconds
+// returns the count of evicted values
+var pushScript = redis.NewScript(`
+local length = redis.call('RPUSH', KEYS[1], ARGV[1])
+redis.call('INCR', KEYS[2])
+local sequence = redis.call('INCR', KEYS[3])
+if ARGV[3] == '1' then
+	local member = sequence .. ':' .. KEYS[1]
+	redis.call('ZADD', KEYS[4], redis.call('INCR', KEYS[5]), member)
+	redis.call('HSET', KEYS[6], member, ARGV[4])
+	redis.call('INCRBY', KEYS[7], string.len(ARGV[1]))
+end
+local capacity = tonumber(ARGV[2])
+if capacity == 0 or length <= capacity then
+	return 0
+end
+local oldest = redis.call('LPOP', KEYS[1])
+local member = (sequence - length + 1) .. ':' .. KEYS[1]
+if redis.call('ZREM', KEYS[4], member) == 1 then
+	redis.call('HDEL', KEYS[6], member)
+	redis.call('DECRBY', KEYS[7], string.len(oldest))
+end
+return 1
+`)
+
+// evictScript removes the oldest value of all lists, when the memory limit is exceeded or the value is expired.
+// KEYS: index, timestamps, size
+// ARGV: now in microseconds, ttl in microseconds, memory limit, reason memory, reason ttl
+// returns the reason and the key of the list, nil when nothing is evicted
+var evictScript = redis.NewScript(`
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0)[1]
+if not oldest then
+	return false
+end
+local reason = ARGV[5]
+local memoryLimit = tonumber(ARGV[3])
+if memoryLimit > 0 and tonumber(redis.call('GET', KEYS[3]) or 0) > memoryLimit then
+	reason = ARGV[4]
+else
+	local ttl = tonumber(ARGV[2])
+	local timestamp = tonumber(redis.call('HGET', KEYS[2], oldest) or 0)
+	if ttl == 0 or timestamp == 0 or tonumber(ARGV[1]) - timestamp <= ttl then
+		return false
+	end
+end
+local key = string.match(oldest, '^%d+:(.*)$')
+redis.call('ZREM', KEYS[1], oldest)
+redis.call('HDEL', KEYS[2], oldest)
+local value = redis.call('LPOP', key)
+if value then
+	redis.call('DECRBY', KEYS[3], string.len(value))
+end
+return {reason, key}
+`)
+
+// deleteScript removes a list with its values in the index and resets its counter.
+// KEYS: list, counter, list sequence, index, timestamps, size
+// ARGV: indexed
+// returns the count of deleted values
+var deleteScript = redis.NewScript(`
+local count = redis.call('LLEN', KEYS[1])
+if ARGV[1] == '1' then
+	local sequence = tonumber(redis.call('GET', KEYS[3]) or 0)
+	for i, value in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+		local member = (sequence - count + i) .. ':' .. KEYS[1]
+		if redis.call('ZREM', KEYS[4], member) == 1 then
+			redis.call('HDEL', KEYS[5], member)
+			redis.call('DECRBY', KEYS[6], string.len(value))
+		end
+	end
+end
+redis.call('DEL', KEYS[1])
+redis.call('SET', KEYS[2], 0)
+return count
+`)
@@ -10,7 +10,7 @@ var versionTag = "latest"
 var variant = "standalone"
 
 func main() {
-	matchStore := matches.NewInMemoryMatchstore(uint16(starter.BasicConfig.MatchesCapacity))
+	matchStore := matches.NewShardedMatchstore(matches.DefaultShards, starter.BasicConfig.MatchesRetention())
 	kvstore := kvstore.NewInmemoryStorage()
 	starter.SetupRouter(variant, versionTag, "", matchStore, kvstore)
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bufio"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"syscall"
 	"testing"
 
@@ -243,3 +247,79 @@ func TestMain_templateFunctionsPeople(t *testing.T) {
 	}))
 	testutil.StopServing()
 }
+
+func TestMain_apiPortAndMockListeners(t *testing.T) {
+	t.Setenv("API_PORT", "9000")
+	t.Setenv("MOCK_LISTENERS", "9001=../../../test/mockdirs/payments;shipping.local=../../../test/mockdirs/shipping")
+	apiServers, listenerServers := make(chan *httptest.Server, 1), make(chan *httptest.Server, 1)
+	var listenerPort int
+	starter.ServingAPI = func(router *mux.Router) error {
+		apiServers <- httptest.NewServer(router)
+		return nil
+	}
+	starter.ServingListener = func(port int, router *mux.Router) error {
+		listenerPort = port
+		listenerServers <- httptest.NewServer(router)
+		return nil
+	}
+	t.Cleanup(func() {
+		starter.ServingAPI = nil
+		starter.ServingListener = nil
+	})
+	setupMain(t)
+	apiServer, listenerServer := <-apiServers, <-listenerServers
+	defer apiServer.Close()
+	defer listenerServer.Close()
+	assert.Equal(t, 9001, listenerPort)
+
+	assert.NoError(t, testutil.AssertResponseStatusOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/__/health", testutil.CreateHeader(), ""), http.StatusNotFound))
+	response, err := http.Get(apiServer.URL + "/__/health")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	assertBody := func(request *http.Request, expectedStatus int, expectedBody string) {
+		response, err := http.DefaultClient.Do(request)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedStatus, response.StatusCode, request.URL.String())
+		if expectedStatus == http.StatusOK {
+			assert.Equal(t, expectedBody, string(body))
+		}
+	}
+	listenerRequest, _ := http.NewRequest(http.MethodGet, listenerServer.URL+"/status", nil)
+	assertBody(listenerRequest, http.StatusOK, `{ "service": "payments" }`)
+	listenerRequest, _ = http.NewRequest(http.MethodGet, listenerServer.URL+"/hello", nil)
+	assertBody(listenerRequest, http.StatusNotFound, "")
+
+	virtualHostRequest := testutil.CreateOutgoingRequest(t, http.MethodGet, "/status", testutil.CreateHeader(), "")
+	virtualHostRequest.Host = "shipping.local"
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t, virtualHostRequest, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "shipping", responseBody)
+	}))
+	virtualHostRequest = testutil.CreateOutgoingRequest(t, http.MethodGet, "/hello", testutil.CreateHeader(), "")
+	virtualHostRequest.Host = "shipping.local"
+	assert.NoError(t, testutil.AssertResponseStatusOfRequestCall(t, virtualHostRequest, http.StatusNotFound))
+	assert.NoError(t, testutil.AssertResponseStatusOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/hello", testutil.CreateHeader(), ""), http.StatusOK))
+
+	socketConn, err := net.Dial("tcp", "127.0.0.1:19002")
+	if assert.NoError(t, err, "socket endpoint of mock listener must be served") {
+		_, err = socketConn.Write([]byte("PING\n"))
+		assert.NoError(t, err)
+		reply, err := bufio.NewReader(socketConn).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "PONG shipping\r\n", reply)
+		socketConn.Close()
+	}
+
+	reloadRequest, _ := http.NewRequest(http.MethodPost, apiServer.URL+"/__/reload", nil)
+	reloadRequest.SetBasicAuth("mockgo", apiPassword)
+	assertBody(reloadRequest, http.StatusOK, "")
+	stopServer()
+}
@@ -1,6 +1,11 @@
 package kvstore
 
-import "text/template"
+import (
+	"sync"
+	"text/template"
+
+	"github.com/alitari/mockgo-server/mockgo/util"
+)
 
 /*
 Storage is *the* interface for the key value store
@@ -18,28 +23,55 @@ type Storage interface {
 }
 
 /*
-InmemoryStorage is an implementation of Storage using local in-memory storage
+inmemoryShards is the count of shards of an InmemoryStorage
+*/
+const inmemoryShards = 32
+
+/*
+InmemoryStorage is an implementation of Storage using local in-memory storage, it is safe for concurrent use.
+The keys are distributed to shards with an own lock.
 */
 type InmemoryStorage struct {
+	shards []*inmemoryShard
+}
+
+type inmemoryShard struct {
+	lock  sync.RWMutex
 	store map[string]map[string]interface{}
 }
 
+func (s *InmemoryStorage) shard(store, key string) *inmemoryShard {
+	return s.shards[util.ShardIndex(len(s.shards), store, key)]
+}
+
 /*
-GetAll returns all values for a given store
+GetAll returns a copy of all values for a given store
 */
 func (s *InmemoryStorage) GetAll(store string) (map[string]interface{}, error) {
-	st := s.store[store]
-	if st == nil {
-		return nil, nil
+	var all map[string]interface{}
+	for _, shard := range s.shards {
+		shard.lock.RLock()
+		if st := shard.store[store]; st != nil {
+			if all == nil {
+				all = map[string]interface{}{}
+			}
+			for key, val := range st {
+				all[key] = val
+			}
+		}
+		shard.lock.RUnlock()
 	}
-	return st, nil
+	return all, nil
 }
 
 /*
 Get returns a value for a given key
 */
 func (s *InmemoryStorage) Get(store, key string) (interface{}, error) {
-	st := s.store[store]
+	shard := s.shard(store, key)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+	st := shard.store[store]
 	if st == nil {
 		return nil, nil
 	}
@@ -50,10 +82,13 @@ func (s *InmemoryStorage) Get(store, key string) (interface{}, error) {
 Put stores a value for a given key
 */
 func (s *InmemoryStorage) Put(store, key string, val interface{}) error {
-	st := s.store[store]
+	shard := s.shard(store, key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	st := shard.store[store]
 	if st == nil {
-		s.store[store] = map[string]interface{}{}
-		st = s.store[store]
+		shard.store[store] = map[string]interface{}{}
+		st = shard.store[store]
 	}
 	st[key] = val
 	return nil
@@ -70,8 +105,11 @@ func (s *InmemoryStorage) Shutdown() error {
 NewInmemoryStorage creates a new instance of InmemoryStorage
 */
 func NewInmemoryStorage() *InmemoryStorage {
-	st := map[string]map[string]interface{}{}
-	return &InmemoryStorage{store: st}
+	storage := &InmemoryStorage{}
+	for i := 0; i < inmemoryShards; i++ {
+		storage.shards = append(storage.shards, &inmemoryShard{store: map[string]map[string]interface{}{}})
+	}
+	return storage
 }
 
 /*
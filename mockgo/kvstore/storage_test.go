@@ -1,9 +1,13 @@
 package kvstore
 
 import (
-	"github.com/stretchr/testify/assert"
 	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStorage_PutGet(t *testing.T) {
@@ -43,6 +47,53 @@ func TestStorage_PutGetAll(t *testing.T) {
 	assert.Equal(t, map[string]interface{}{key1: val1, key2: val2}, getAll)
 }
 
+func TestStorage_concurrent(t *testing.T) {
+	kvstore := NewInmemoryStorage()
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(g) + "-" + strconv.Itoa(i)
+				assert.NoError(t, kvstore.Put("store", key, i))
+				val, err := kvstore.Get("store", key)
+				assert.NoError(t, err)
+				assert.Equal(t, i, val)
+				_, err = kvstore.GetAll("store")
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+	all, err := kvstore.GetAll("store")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2000)
+	all["added"] = true
+	val, err := kvstore.Get("store", "added")
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+}
+
+func BenchmarkStorage_PutGet(b *testing.B) {
+	kvstore := NewInmemoryStorage()
+	var goroutines uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		prefix := strconv.Itoa(int(atomic.AddUint64(&goroutines, 1))) + "-"
+		for i := 0; pb.Next(); i++ {
+			key := prefix + strconv.Itoa(i%1000)
+			if err := kvstore.Put("store", key, i); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := kvstore.Get("store", key); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
 func randString(n int) string {
@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+)
+
+/*
+ParseMail parses the data of a mail received with the envelope sender and recipients, the mime parts are decoded
+*/
+func ParseMail(from string, to []string, data []byte) (*matches.Mail, error) {
+	message, err := netmail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing mail: %v", err)
+	}
+	subject := message.Header.Get("Subject")
+	if decodedSubject, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+		subject = decodedSubject
+	}
+	parts, err := parseParts(textproto.MIMEHeader(message.Header), message.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &matches.Mail{From: from, To: to, Subject: subject, Header: message.Header, Parts: parts, Size: len(data)}, nil
+}
+
+func parseParts(header textproto.MIMEHeader, body io.Reader) ([]*matches.MailPart, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var parts []*matches.MailPart
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return parts, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error parsing mime part: %v", err)
+			}
+			subParts, err := parseParts(part.Header, part)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, subParts...)
+		}
+	}
+	content, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding mime part of type '%s': %v", mediaType, err)
+	}
+	part := &matches.MailPart{ContentType: mediaType, Filename: params["name"], Body: string(content)}
+	if _, dispositionParams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && len(dispositionParams["filename"]) > 0 {
+		part.Filename = dispositionParams["filename"]
+	}
+	if !strings.HasPrefix(mediaType, "text/") && !strings.HasPrefix(mediaType, "message/") {
+		part.Binary = true
+		part.Body = base64.StdEncoding.EncodeToString(content)
+	}
+	return []*matches.MailPart{part}, nil
+}
+
+func decodeTransferEncoding(transferEncoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
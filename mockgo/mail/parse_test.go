@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMail_multipart(t *testing.T) {
+	data := "From: shop@example.com\r\n" +
+		"Subject: invoice\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=outer\r\n" +
+		"\r\n" +
+		"--outer\r\n" +
+		"Content-Type: multipart/alternative; boundary=inner\r\n" +
+		"\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Gr=C3=BC=C3=9Fe\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<p>hello</p>\r\n" +
+		"--inner--\r\n" +
+		"--outer\r\n" +
+		"Content-Type: application/pdf; name=\"ignored.pdf\"\r\n" +
+		"Content-Disposition: attachment; filename=\"invoice.pdf\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"JVBERi0xLjQ=\r\n" +
+		"--outer--\r\n"
+	mail, err := ParseMail("shop@example.com", []string{"alice@example.com"}, []byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "invoice", mail.Subject)
+	assert.Equal(t, len(data), mail.Size)
+	assert.Len(t, mail.Parts, 3)
+	assert.Equal(t, "text/plain", mail.Parts[0].ContentType)
+	assert.Equal(t, "Grüße", mail.Parts[0].Body)
+	assert.Equal(t, "text/html", mail.Parts[1].ContentType)
+	assert.Equal(t, "<p>hello</p>", mail.Parts[1].Body)
+	assert.Equal(t, "application/pdf", mail.Parts[2].ContentType)
+	assert.Equal(t, "invoice.pdf", mail.Parts[2].Filename)
+	assert.True(t, mail.Parts[2].Binary)
+	assert.Equal(t, "JVBERi0xLjQ=", mail.Parts[2].Body)
+}
+
+func TestParseMail_invalid(t *testing.T) {
+	_, err := ParseMail("", nil, []byte("no header\r\n"))
+	assert.ErrorContains(t, err, "error parsing mail")
+}
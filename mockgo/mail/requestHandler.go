@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/util"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+/*
+RequestHandler implements an http API to access the mails received by the SMTPServer
+*/
+type RequestHandler struct {
+	pathPrefix string
+	mailbox    string
+	matchStore matches.Matchstore
+	logger     *zap.Logger
+}
+
+type mailResponse struct {
+	Timestamp time.Time `json:"timestamp"`
+	*matches.Mail
+}
+
+/*
+NewRequestHandler creates an instance of RequestHandler
+*/
+func NewRequestHandler(pathPrefix, mailbox string, matchStore matches.Matchstore, logLevel string) *RequestHandler {
+	return &RequestHandler{
+		pathPrefix: pathPrefix,
+		mailbox:    mailbox,
+		matchStore: matchStore,
+		logger:     util.CreateLogger(logLevel),
+	}
+}
+
+/*
+AddRoutes adds mux.Routes for the http API to a given mux.Router
+*/
+func (r *RequestHandler) AddRoutes(router *mux.Router) {
+	router.NewRoute().Name("getMails").Path(r.pathPrefix + "/mail").Methods(http.MethodGet).
+		HandlerFunc(util.JSONAcceptRequest(r.handleGetMails))
+	router.NewRoute().Name("deleteMails").Path(r.pathPrefix + "/mail").Methods(http.MethodDelete).
+		HandlerFunc(r.handleDeleteMails)
+}
+
+/*
+handleGetMails returns the received mails, the query parameters 'from', 'to', 'subject' and 'text' filter
+the mails by a case insensitive substring
+*/
+func (r *RequestHandler) handleGetMails(writer http.ResponseWriter, request *http.Request) {
+	mailMatches, err := r.matchStore.GetMatches(r.mailbox)
+	if err != nil {
+		r.logger.Error("Error getting mails", zap.Error(err))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	query := request.URL.Query()
+	mails := []*mailResponse{}
+	for _, match := range mailMatches {
+		if match.Mail == nil {
+			continue
+		}
+		if containsFold([]string{match.Mail.From}, query.Get("from")) &&
+			containsFold(match.Mail.To, query.Get("to")) &&
+			containsFold([]string{match.Mail.Subject}, query.Get("subject")) &&
+			containsFold(textBodies(match.Mail), query.Get("text")) {
+			mails = append(mails, &mailResponse{Timestamp: match.Timestamp, Mail: match.Mail})
+		}
+	}
+	util.WriteEntity(writer, mails)
+}
+
+func (r *RequestHandler) handleDeleteMails(writer http.ResponseWriter, request *http.Request) {
+	if err := r.matchStore.DeleteMatches(r.mailbox); err != nil {
+		r.logger.Error("Error deleting mails", zap.Error(err))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	} else {
+		writer.WriteHeader(http.StatusOK)
+	}
+}
+
+func textBodies(mail *matches.Mail) []string {
+	var bodies []string
+	for _, part := range mail.Parts {
+		if !part.Binary {
+			bodies = append(bodies, part.Body)
+		}
+	}
+	return bodies
+}
+
+func containsFold(values []string, filter string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), filter) {
+			return true
+		}
+	}
+	return false
+}
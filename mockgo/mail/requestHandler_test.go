@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHandler_getAndDeleteMails(t *testing.T) {
+	matchStore := matches.NewInMemoryMatchstore(uint16(100))
+	for _, mail := range []*matches.Mail{
+		{From: "shop@example.com", To: []string{"alice@example.com"}, Subject: "Order confirmed", Parts: []*matches.MailPart{{ContentType: "text/plain", Body: "order 4711"}}},
+		{From: "billing@example.com", To: []string{"bob@example.com"}, Subject: "Invoice", Parts: []*matches.MailPart{{ContentType: "application/pdf", Binary: true, Body: "b3JkZXI="}}},
+	} {
+		assert.NoError(t, matchStore.AddMatch("smtp", &matches.Match{EndpointID: "smtp", Timestamp: time.Now(), Mail: mail}))
+	}
+	router := mux.NewRouter()
+	NewRequestHandler("/__", "smtp", matchStore, "DEBUG").AddRoutes(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	getMails := func(query string) []*mailResponse {
+		request, err := http.NewRequest(http.MethodGet, server.URL+"/__/mail"+query, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Accept", "application/json")
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		var mails []*mailResponse
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&mails))
+		return mails
+	}
+	assert.Len(t, getMails(""), 2)
+	mails := getMails("?to=ALICE&subject=order")
+	assert.Len(t, mails, 1)
+	assert.Equal(t, "shop@example.com", mails[0].From)
+	assert.Len(t, getMails("?text=4711"), 1)
+	assert.Len(t, getMails("?text=order&from=billing"), 0)
+
+	request, err := http.NewRequest(http.MethodDelete, server.URL+"/__/mail", nil)
+	assert.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, getMails(""), 0)
+}
@@ -0,0 +1,293 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/util"
+	"go.uber.org/zap"
+)
+
+const maxMailSize = 10 * 1024 * 1024
+
+/*
+SMTPServer accepts mails from any sender for any recipient and stores them in the matchstore under a mailbox id.
+Authentication with AUTH PLAIN or AUTH LOGIN is accepted with any credentials.
+*/
+type SMTPServer struct {
+	mailbox    string
+	hostname   string
+	matchstore matches.Matchstore
+	logger     *zap.Logger
+	lock       sync.Mutex
+	listener   net.Listener
+	conns      map[net.Conn]bool
+	shutdown   bool
+	serving    sync.WaitGroup
+}
+
+type smtpSession struct {
+	conn *textproto.Conn
+	mail bool
+	from string
+	to   []string
+}
+
+/*
+NewSMTPServer creates an instance of SMTPServer
+*/
+func NewSMTPServer(mailbox string, matchstore matches.Matchstore, logLevel string) *SMTPServer {
+	return &SMTPServer{
+		mailbox:    mailbox,
+		hostname:   "mockgo-server",
+		matchstore: matchstore,
+		logger:     util.CreateLogger(logLevel),
+		conns:      map[net.Conn]bool{},
+	}
+}
+
+/*
+Serve accepts smtp connections on the listener
+*/
+func (s *SMTPServer) Serve(listener net.Listener) error {
+	s.lock.Lock()
+	if s.shutdown {
+		s.lock.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.serving.Add(1)
+	s.lock.Unlock()
+	defer s.serving.Done()
+	s.logger.Info(fmt.Sprintf("serving smtp at %v", listener.Addr()))
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isShutdown() {
+				return nil
+			}
+			return err
+		}
+		s.lock.Lock()
+		if s.shutdown {
+			s.lock.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = true
+		s.serving.Add(1)
+		s.lock.Unlock()
+		go s.handleConn(conn)
+	}
+}
+
+func (s *SMTPServer) isShutdown() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.shutdown
+}
+
+/*
+Shutdown closes the listener and all connections and waits until received mails are stored
+*/
+func (s *SMTPServer) Shutdown() {
+	s.lock.Lock()
+	s.shutdown = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.lock.Unlock()
+	s.serving.Wait()
+}
+
+func (s *SMTPServer) handleConn(conn net.Conn) {
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		conn.Close()
+		s.serving.Done()
+	}()
+	session := &smtpSession{conn: textproto.NewConn(conn)}
+	session.reply(220, s.hostname+" ESMTP mockgo-server")
+	for {
+		line, err := session.conn.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				s.logger.Debug(fmt.Sprintf("smtp connection from %v closed: %v", conn.RemoteAddr(), err))
+			}
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			session.reset()
+			session.reply(250, s.hostname)
+		case "EHLO":
+			session.reset()
+			session.reply(250, s.hostname, "8BITMIME", "PIPELINING", fmt.Sprintf("SIZE %d", maxMailSize), "AUTH PLAIN LOGIN")
+		case "AUTH":
+			session.auth(arg)
+		case "MAIL":
+			session.mailFrom(arg)
+		case "RCPT":
+			session.rcptTo(arg)
+		case "DATA":
+			if err := s.data(session, conn.RemoteAddr()); err != nil {
+				return
+			}
+		case "RSET":
+			session.reset()
+			session.reply(250, "OK")
+		case "NOOP":
+			session.reply(250, "OK")
+		case "VRFY":
+			session.reply(252, "cannot verify user, but will accept message")
+		case "QUIT":
+			session.reply(221, "bye")
+			return
+		default:
+			session.reply(502, "command not implemented")
+		}
+	}
+}
+
+func (s *smtpSession) mailFrom(arg string) {
+	from, ok := parseSMTPPath(arg, "FROM:")
+	if !ok {
+		s.reply(501, "syntax: MAIL FROM:<address>")
+		return
+	}
+	s.reset()
+	s.mail = true
+	s.from = from
+	s.reply(250, "OK")
+}
+
+func (s *smtpSession) rcptTo(arg string) {
+	if !s.mail {
+		s.reply(503, "Bad sequence of commands")
+		return
+	}
+	to, ok := parseSMTPPath(arg, "TO:")
+	if !ok || len(to) == 0 {
+		s.reply(501, "syntax: RCPT TO:<address>")
+		return
+	}
+	s.to = append(s.to, to)
+	s.reply(250, "OK")
+}
+
+/*
+data receives the content of a mail and stores it, an error is returned when the connection can't be read
+*/
+func (s *SMTPServer) data(session *smtpSession, remoteAddr net.Addr) error {
+	if !session.mail || len(session.to) == 0 {
+		session.reply(503, "Bad sequence of commands")
+		return nil
+	}
+	session.reply(354, "end data with <CR><LF>.<CR><LF>")
+	data, err := io.ReadAll(io.LimitReader(session.conn.DotReader(), maxMailSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxMailSize {
+		io.Copy(io.Discard, session.conn.DotReader())
+		session.reply(552, "message exceeds fixed maximum message size")
+	} else if err := s.storeMail(session, data, remoteAddr); err != nil {
+		s.logger.Info("can't store mail", zap.Error(err))
+		session.reply(554, err.Error())
+	} else {
+		session.reply(250, "OK queued")
+	}
+	session.reset()
+	return nil
+}
+
+func (s *smtpSession) reply(code int, lines ...string) {
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		s.conn.PrintfLine("%d%s%s", code, separator, line)
+	}
+}
+
+func (s *smtpSession) reset() {
+	s.mail = false
+	s.from = ""
+	s.to = nil
+}
+
+/*
+auth accepts any credentials, for AUTH LOGIN and AUTH PLAIN without initial response the credentials are requested
+*/
+func (s *smtpSession) auth(arg string) {
+	mechanism, initialResponse, _ := strings.Cut(arg, " ")
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		if len(initialResponse) == 0 {
+			s.reply(334, "")
+			if _, err := s.conn.ReadLine(); err != nil {
+				return
+			}
+		}
+	case "LOGIN":
+		for _, prompt := range []string{"VXNlcm5hbWU6", "UGFzc3dvcmQ6"} { // base64 of 'Username:' and 'Password:'
+			s.reply(334, prompt)
+			if _, err := s.conn.ReadLine(); err != nil {
+				return
+			}
+		}
+	default:
+		s.reply(504, "unrecognized authentication type")
+		return
+	}
+	s.reply(235, "authentication successful")
+}
+
+/*
+parseSMTPPath parses the address of a MAIL or RCPT command, parameters after the address are ignored
+*/
+func parseSMTPPath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	path := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(path, "<") {
+		address, _, _ := strings.Cut(path, " ")
+		return address, len(address) > 0
+	}
+	end := strings.Index(path, ">")
+	if end < 0 {
+		return "", false
+	}
+	return path[1:end], true
+}
+
+func (s *SMTPServer) storeMail(session *smtpSession, data []byte, remoteAddr net.Addr) error {
+	mail, err := ParseMail(session.from, session.to, data)
+	if err != nil {
+		return err
+	}
+	match := &matches.Match{
+		EndpointID:     s.mailbox,
+		Timestamp:      time.Now(),
+		ActualRequest:  &matches.ActualRequest{Method: "SMTP", URL: "smtp://" + s.hostname, Header: mail.Header, Host: remoteAddr.String()},
+		ActualResponse: &matches.ActualResponse{StatusCode: 250, Header: map[string][]string{}},
+		Mail:           mail,
+	}
+	s.logger.Debug(fmt.Sprintf("received mail from '%s' to %v with subject '%s'", mail.From, mail.To, mail.Subject))
+	return s.matchstore.AddMatch(s.mailbox, match)
+}
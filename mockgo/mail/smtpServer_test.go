@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+func startSMTPServer(t *testing.T, matchStore matches.Matchstore) (*SMTPServer, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewSMTPServer("smtp", matchStore, "DEBUG")
+	go server.Serve(listener)
+	return server, listener.Addr().String()
+}
+
+func TestSMTPServer_sendMail(t *testing.T) {
+	matchStore := matches.NewInMemoryMatchstore(uint16(100))
+	server, addr := startSMTPServer(t, matchStore)
+	message := "From: shop@example.com\r\nTo: alice@example.com\r\nSubject: =?UTF-8?Q?Bestellbest=C3=A4tigung?=\r\n\r\nyour order 4711 is confirmed\r\n"
+	err := smtp.SendMail(addr, smtp.PlainAuth("", "user", "secret", "127.0.0.1"), "shop@example.com", []string{"alice@example.com", "bob@example.com"}, []byte(message))
+	assert.NoError(t, err)
+	server.Shutdown()
+
+	mailMatches, err := matchStore.GetMatches("smtp")
+	assert.NoError(t, err)
+	assert.Len(t, mailMatches, 1)
+	match := mailMatches[0]
+	assert.Equal(t, "smtp", match.EndpointID)
+	assert.Equal(t, "SMTP", match.ActualRequest.Method)
+	assert.Equal(t, 250, match.ActualResponse.StatusCode)
+	assert.Equal(t, "shop@example.com", match.Mail.From)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, match.Mail.To)
+	assert.Equal(t, "Bestellbestätigung", match.Mail.Subject)
+	assert.Equal(t, []string{"alice@example.com"}, match.Mail.Header["To"])
+	assert.Len(t, match.Mail.Parts, 1)
+	assert.Equal(t, "text/plain", match.Mail.Parts[0].ContentType)
+	assert.Equal(t, "your order 4711 is confirmed\n", match.Mail.Parts[0].Body)
+}
+
+func TestSMTPServer_commands(t *testing.T) {
+	matchStore := matches.NewInMemoryMatchstore(uint16(100))
+	server, addr := startSMTPServer(t, matchStore)
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	send := func(line string) string {
+		if len(line) > 0 {
+			_, err := conn.Write([]byte(line + "\r\n"))
+			assert.NoError(t, err)
+		}
+		reply, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		return strings.TrimSpace(reply)
+	}
+	assert.Equal(t, "220 mockgo-server ESMTP mockgo-server", send(""))
+	assert.Equal(t, "250 mockgo-server", send("HELO client"))
+	assert.Equal(t, "503 Bad sequence of commands", send("DATA"))
+	assert.Equal(t, "503 Bad sequence of commands", send("RCPT TO:<alice@example.com>"))
+	assert.Equal(t, "250 OK", send("MAIL FROM:<> SIZE=100"))
+	assert.Equal(t, "503 Bad sequence of commands", send("DATA"))
+	assert.Equal(t, "501 syntax: RCPT TO:<address>", send("RCPT TO:<>"))
+	assert.Equal(t, "250 OK", send("RCPT TO:<alice@example.com>"))
+	assert.Equal(t, "334 VXNlcm5hbWU6", send("AUTH LOGIN"))
+	assert.Equal(t, "334 UGFzc3dvcmQ6", send("dXNlcg=="))
+	assert.Equal(t, "235 authentication successful", send("c2VjcmV0"))
+	assert.Equal(t, "504 unrecognized authentication type", send("AUTH CRAM-MD5"))
+	assert.Equal(t, "252 cannot verify user, but will accept message", send("VRFY alice"))
+	assert.Equal(t, "502 command not implemented", send("TURN"))
+	assert.Equal(t, "250 OK", send("RSET"))
+	assert.Equal(t, "503 Bad sequence of commands", send("DATA"))
+	assert.Equal(t, "221 bye", send("QUIT"))
+	conn.Close()
+	server.Shutdown()
+	count, err := matchStore.GetMatchesCount("smtp")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+}
@@ -0,0 +1,47 @@
+package matches
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+DefaultBodyLimit is the default maximum number of bytes of a request or response body which is stored in a match
+*/
+const DefaultBodyLimit = 64 * 1024
+
+/*
+EncodeBody encodes a body for storing it in a match, the body is cut after limit bytes.
+A body which isn't valid utf-8 is base64 encoded and binary is set.
+*/
+func EncodeBody(data []byte, limit int) (body string, binary bool, truncated bool) {
+	if len(data) > limit {
+		data = data[:limit]
+		truncated = true
+		for start := len(data) - 1; start >= 0 && start >= len(data)-utf8.UTFMax; start-- { // don't cut a multi-byte rune of a text
+			if utf8.RuneStart(data[start]) {
+				if !utf8.FullRune(data[start:]) && utf8.Valid(data[:start]) {
+					data = data[:start]
+				}
+				break
+			}
+		}
+	}
+	if utf8.Valid(data) {
+		return string(data), false, truncated
+	}
+	return base64.StdEncoding.EncodeToString(data), true, truncated
+}
+
+/*
+bodyContains returns true when the body of the request contains the substring, a binary body is decoded before
+*/
+func (a *ActualRequest) bodyContains(substring string) bool {
+	if !a.Binary {
+		return strings.Contains(a.Body, substring)
+	}
+	body, err := base64.StdEncoding.DecodeString(a.Body)
+	return err == nil && bytes.Contains(body, []byte(substring))
+}
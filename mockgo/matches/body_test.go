@@ -0,0 +1,32 @@
+package matches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBody(t *testing.T) {
+	for _, testCase := range []struct {
+		name              string
+		data              []byte
+		limit             int
+		expectedBody      string
+		expectedBinary    bool
+		expectedTruncated bool
+	}{
+		{name: "empty", data: nil, limit: 10, expectedBody: ""},
+		{name: "text", data: []byte(`{ "id": 1 }`), limit: 100, expectedBody: `{ "id": 1 }`},
+		{name: "text truncated", data: []byte("abcdef"), limit: 3, expectedBody: "abc", expectedTruncated: true},
+		{name: "multi-byte rune not cut", data: []byte("aä"), limit: 2, expectedBody: "a", expectedTruncated: true},
+		{name: "binary", data: []byte{0xff, 0x00, 0x01}, limit: 10, expectedBody: "/wAB", expectedBinary: true},
+		{name: "binary truncated", data: []byte{0xff, 0x00, 0x01}, limit: 2, expectedBody: "/wA=", expectedBinary: true, expectedTruncated: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			body, binary, truncated := EncodeBody(testCase.data, testCase.limit)
+			assert.Equal(t, testCase.expectedBody, body)
+			assert.Equal(t, testCase.expectedBinary, binary)
+			assert.Equal(t, testCase.expectedTruncated, truncated)
+		})
+	}
+}
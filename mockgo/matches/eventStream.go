@@ -0,0 +1,92 @@
+package matches
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+var eventsUpgrader = websocket.Upgrader{}
+
+/*
+handleEvents streams the events of the matchstore, filtered by the query parameters 'type' and 'endpointId'.
+A websocket upgrade request receives each event as json text message, otherwise the events are sent as server-sent events.
+*/
+func (r *RequestHandler) handleEvents(writer http.ResponseWriter, request *http.Request) {
+	filter, err := ParseEventFilter(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithCancel(request.Context())
+	defer cancel()
+	events, err := r.matchStore.Subscribe(ctx)
+	if err != nil {
+		r.logger.Error("Error subscribing to events", zap.Error(err))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if websocket.IsWebSocketUpgrade(request) {
+		r.streamWebSocketEvents(writer, request, events, filter, cancel)
+	} else {
+		r.streamServerSentEvents(writer, events, filter)
+	}
+}
+
+func (r *RequestHandler) streamServerSentEvents(writer http.ResponseWriter, events <-chan *Event, filter *EventFilter) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for event := range events {
+		if !filter.Matches(event) {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			r.logger.Error("Error marshalling event", zap.Error(err))
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			r.logger.Debug(fmt.Sprintf("event stream closed: %v", err))
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (r *RequestHandler) streamWebSocketEvents(writer http.ResponseWriter, request *http.Request, events <-chan *Event, filter *EventFilter, cancel func()) {
+	conn, err := eventsUpgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		r.logger.Debug(fmt.Sprintf("can't upgrade event stream to websocket: %v", err))
+		return
+	}
+	defer conn.Close()
+	go func() { // the client doesn't send messages, reading detects the close of the connection
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	for event := range events {
+		if !filter.Matches(event) {
+			continue
+		}
+		if err := conn.WriteJSON(event); err != nil {
+			r.logger.Debug(fmt.Sprintf("event websocket closed: %v", err))
+			return
+		}
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
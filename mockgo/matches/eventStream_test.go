@@ -0,0 +1,93 @@
+package matches
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func startEventsServer() (Matchstore, *httptest.Server) {
+	matchstore := NewInMemoryMatchstore(uint16(10))
+	router := mux.NewRouter()
+	NewRequestHandler("/__", matchstore, "DEBUG").AddRoutes(router)
+	return matchstore, httptest.NewServer(router)
+}
+
+func TestMatchesRequestHandler_serving_events_sse(t *testing.T) {
+	matchstore, server := startEventsServer()
+	defer server.Close()
+	response, err := http.Get(server.URL + "/__/events?endpointId=a")
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	assert.NoError(t, matchstore.AddMismatch(&Mismatch{MismatchDetails: "details"}))
+	assert.NoError(t, matchstore.AddMatch("b", createMatch("b")))
+	assert.NoError(t, matchstore.AddMatch("a", createMatch("a")))
+
+	reader := bufio.NewReader(response.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: match\n", line)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	event := &Event{}
+	if assert.True(t, strings.HasPrefix(line, "data: ")) && assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), event)) {
+		assert.Equal(t, EventTypeMatch, event.Type)
+		assert.Equal(t, "a", event.Match.EndpointID)
+		assert.Nil(t, event.Mismatch)
+	}
+}
+
+func TestMatchesRequestHandler_serving_events_websocket(t *testing.T) {
+	matchstore, server := startEventsServer()
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/__/events?type=mismatch", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	assert.NoError(t, matchstore.AddMatch("a", createMatch("a")))
+	assert.NoError(t, matchstore.AddMismatch(&Mismatch{MismatchDetails: "details"}))
+
+	event := &Event{}
+	assert.NoError(t, conn.ReadJSON(event))
+	assert.Equal(t, EventTypeMismatch, event.Type)
+	assert.Equal(t, "details", event.Mismatch.MismatchDetails)
+	assert.Nil(t, event.Match)
+}
+
+func TestMatchesRequestHandler_serving_events_websocketCrossOrigin(t *testing.T) {
+	_, server := startEventsServer()
+	defer server.Close()
+	_, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/__/events", http.Header{"Origin": {"http://evil.example.com"}})
+	assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	if assert.NotNil(t, response) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+}
+
+func TestMatchesRequestHandler_serving_events_badRequest(t *testing.T) {
+	_, server := startEventsServer()
+	defer server.Close()
+	response, err := http.Get(server.URL + "/__/events?type=unknown")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestMatchesRequestHandler_events_Error(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	matchesRequestHandlerErroneous.handleEvents(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "error in subscribe\n", recorder.Body.String())
+}
@@ -0,0 +1,121 @@
+package matches
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+/*
+EventTypeMatch is the type of an Event for a recorded match
+*/
+const EventTypeMatch = "match"
+
+/*
+EventTypeMismatch is the type of an Event for a recorded mismatch
+*/
+const EventTypeMismatch = "mismatch"
+
+const eventsBufferSize = 100
+
+/*
+Event datamodel for a match or mismatch which was recorded by a Matchstore
+*/
+type Event struct {
+	Type     string    `json:"type"`
+	Match    *Match    `json:"match,omitempty"`
+	Mismatch *Mismatch `json:"mismatch,omitempty"`
+}
+
+/*
+EventFilter filters events by type and endpoint ids, zero values don't filter
+*/
+type EventFilter struct {
+	Type        string
+	EndpointIDs []string
+}
+
+/*
+ParseEventFilter creates an EventFilter from the query parameters 'type' and 'endpointId', which can be repeated
+*/
+func ParseEventFilter(values url.Values) (*EventFilter, error) {
+	filter := &EventFilter{Type: values.Get("type"), EndpointIDs: values["endpointId"]}
+	switch filter.Type {
+	case "", EventTypeMatch, EventTypeMismatch:
+	default:
+		return nil, fmt.Errorf("error parsing query parameter 'type' , must be '%s' or '%s'", EventTypeMatch, EventTypeMismatch)
+	}
+	if len(filter.EndpointIDs) > 0 && filter.Type == EventTypeMismatch {
+		return nil, fmt.Errorf("error parsing query parameter 'endpointId' , mismatches don't have an endpoint")
+	}
+	return filter, nil
+}
+
+/*
+Matches returns true when the event passes the filter, a mismatch doesn't pass an endpoint filter
+*/
+func (f *EventFilter) Matches(event *Event) bool {
+	if len(f.Type) > 0 && event.Type != f.Type {
+		return false
+	}
+	if len(f.EndpointIDs) == 0 {
+		return true
+	}
+	if event.Match == nil {
+		return false
+	}
+	for _, endpointID := range f.EndpointIDs {
+		if event.Match.EndpointID == endpointID {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Broadcaster distributes events to its subscribers, a subscriber which doesn't keep up loses events
+*/
+type Broadcaster struct {
+	lock        sync.Mutex
+	subscribers map[chan *Event]bool
+}
+
+/*
+NewBroadcaster creates an instance of Broadcaster
+*/
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: map[chan *Event]bool{}}
+}
+
+/*
+Subscribe returns a channel which receives the published events until the context is done, then it is closed
+*/
+func (b *Broadcaster) Subscribe(ctx context.Context) <-chan *Event {
+	events := make(chan *Event, eventsBufferSize)
+	b.lock.Lock()
+	b.subscribers[events] = true
+	b.lock.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.lock.Lock()
+		delete(b.subscribers, events)
+		close(events)
+		b.lock.Unlock()
+	}()
+	return events
+}
+
+/*
+Publish sends the event to all subscribers without blocking
+*/
+func (b *Broadcaster) Publish(event *Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package matches
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	filter, err := ParseEventFilter(url.Values{"type": {"match"}, "endpointId": {"a", "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &EventFilter{Type: EventTypeMatch, EndpointIDs: []string{"a", "b"}}, filter)
+	_, err = ParseEventFilter(url.Values{"type": {"matches"}})
+	assert.EqualError(t, err, "error parsing query parameter 'type' , must be 'match' or 'mismatch'")
+	_, err = ParseEventFilter(url.Values{"type": {"mismatch"}, "endpointId": {"a"}})
+	assert.EqualError(t, err, "error parsing query parameter 'endpointId' , mismatches don't have an endpoint")
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	matchEvent := &Event{Type: EventTypeMatch, Match: &Match{EndpointID: "a"}}
+	mismatchEvent := &Event{Type: EventTypeMismatch, Mismatch: &Mismatch{}}
+	assert.True(t, (&EventFilter{}).Matches(matchEvent))
+	assert.True(t, (&EventFilter{}).Matches(mismatchEvent))
+	assert.True(t, (&EventFilter{Type: EventTypeMismatch}).Matches(mismatchEvent))
+	assert.False(t, (&EventFilter{Type: EventTypeMismatch}).Matches(matchEvent))
+	assert.True(t, (&EventFilter{EndpointIDs: []string{"b", "a"}}).Matches(matchEvent))
+	assert.False(t, (&EventFilter{EndpointIDs: []string{"b"}}).Matches(matchEvent))
+	assert.False(t, (&EventFilter{EndpointIDs: []string{"a"}}).Matches(mismatchEvent))
+}
+
+func TestInMemoryMatchstore_Subscribe(t *testing.T) {
+	matchstore := NewInMemoryMatchstore(uint16(10))
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := matchstore.Subscribe(ctx)
+	assert.NoError(t, err)
+	match := createMatch("a")
+	assert.NoError(t, matchstore.AddMatch("a", match))
+	mismatch := &Mismatch{MismatchDetails: "details"}
+	assert.NoError(t, matchstore.AddMismatch(mismatch))
+	assert.Equal(t, &Event{Type: EventTypeMatch, Match: match}, <-events)
+	assert.Equal(t, &Event{Type: EventTypeMismatch, Mismatch: mismatch}, <-events)
+	cancel()
+	_, open := <-events
+	assert.False(t, open)
+	assert.NoError(t, matchstore.AddMatch("a", match))
+}
+
+func TestBroadcaster_Publish_slowSubscriber(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := broadcaster.Subscribe(ctx)
+	for i := 0; i < eventsBufferSize+10; i++ {
+		broadcaster.Publish(&Event{Type: EventTypeMatch})
+	}
+	assert.Len(t, events, eventsBufferSize)
+}
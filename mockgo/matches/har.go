@@ -0,0 +1,222 @@
+package matches
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const harVersion = "1.2"
+const harHTTPVersion = "HTTP/1.1"
+
+/*
+HAR datamodel for an http archive in the format HAR 1.2
+*/
+type HAR struct {
+	Log *HARLog `json:"log"`
+}
+
+/*
+HARLog datamodel for the log of an http archive
+*/
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator *HARCreator `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+/*
+HARCreator datamodel for the application which created an http archive
+*/
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+/*
+HAREntry datamodel for a request and its response in an http archive, Time is the duration in milliseconds
+*/
+type HAREntry struct {
+	StartedDateTime time.Time    `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *HARRequest  `json:"request"`
+	Response        *HARResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *HARTimings  `json:"timings"`
+	Comment         string       `json:"comment,omitempty"`
+}
+
+/*
+HARRequest datamodel for a request in an http archive
+*/
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	QueryString []*HARNameValue `json:"queryString"`
+	PostData    *HARPostData    `json:"postData,omitempty"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+/*
+HARResponse datamodel for a response in an http archive
+*/
+type HARResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	Content     *HARContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+	Comment     string          `json:"comment,omitempty"`
+}
+
+/*
+HARNameValue datamodel for a header, query parameter or cookie in an http archive
+*/
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+/*
+HARPostData datamodel for the body of a request in an http archive
+*/
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+/*
+HARContent datamodel for the body of a response in an http archive, Encoding is 'base64' for a binary body
+*/
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+/*
+HARTimings datamodel for the timings of an entry in an http archive, the durations are in milliseconds
+*/
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+/*
+NewHAR creates an http archive with the entries of the matches and mismatches, the entries are ordered by their timestamps.
+The version is the version of the mockgo-server which created the archive.
+*/
+func NewHAR(version string, matches []*Match, mismatches []*Mismatch) *HAR {
+	entries := []*HAREntry{}
+	for _, match := range matches {
+		entry := newHAREntry(match.Timestamp, match.ActualRequest)
+		entry.Comment = fmt.Sprintf("endpointId: %s", match.EndpointID)
+		if actualResponse := match.ActualResponse; actualResponse != nil {
+			entry.Response = newHARResponse(actualResponse)
+			entry.Time = float64(actualResponse.Duration) / float64(time.Millisecond)
+			entry.Timings.Wait = entry.Time
+		}
+		entries = append(entries, entry)
+	}
+	for _, mismatch := range mismatches {
+		entry := newHAREntry(mismatch.Timestamp, mismatch.ActualRequest)
+		entry.Comment = fmt.Sprintf("mismatch: %s", mismatch.MismatchDetails)
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return &HAR{Log: &HARLog{Version: harVersion, Creator: &HARCreator{Name: "mockgo-server", Version: version}, Entries: entries}}
+}
+
+/*
+newHAREntry creates an entry without response, the response of a mismatch or of a match whose response wasn't recorded has status 0
+*/
+func newHAREntry(timestamp time.Time, actualRequest *ActualRequest) *HAREntry {
+	entry := &HAREntry{StartedDateTime: timestamp, Timings: &HARTimings{},
+		Response: &HARResponse{HTTPVersion: harHTTPVersion, Cookies: []*HARNameValue{}, Headers: []*HARNameValue{},
+			Content: &HARContent{}, HeadersSize: -1, BodySize: -1, Comment: "response not recorded"}}
+	entry.Request = &HARRequest{HTTPVersion: harHTTPVersion, Cookies: []*HARNameValue{}, Headers: []*HARNameValue{}, QueryString: []*HARNameValue{}, HeadersSize: -1}
+	if actualRequest == nil {
+		return entry
+	}
+	entry.Request.Method = actualRequest.Method
+	entry.Request.URL = actualRequest.URL
+	if requestURL, err := url.Parse(actualRequest.URL); err == nil {
+		if !requestURL.IsAbs() {
+			requestURL.Scheme, requestURL.Host = "http", actualRequest.Host
+		}
+		entry.Request.URL = requestURL.String()
+		entry.Request.QueryString = harNameValues(requestURL.Query())
+	}
+	entry.Request.Headers = harNameValues(actualRequest.Header)
+	entry.Request.BodySize = bodySize(actualRequest.Body, actualRequest.Binary)
+	if len(actualRequest.Body) > 0 {
+		entry.Request.PostData = &HARPostData{MimeType: http.Header(actualRequest.Header).Get("Content-Type"), Text: actualRequest.Body,
+			Comment: bodyComment(actualRequest.Binary, actualRequest.BodyTruncated)}
+	}
+	return entry
+}
+
+func newHARResponse(actualResponse *ActualResponse) *HARResponse {
+	response := &HARResponse{Status: actualResponse.StatusCode, StatusText: http.StatusText(actualResponse.StatusCode), HTTPVersion: harHTTPVersion,
+		Cookies: []*HARNameValue{}, Headers: harNameValues(actualResponse.Header), HeadersSize: -1,
+		BodySize: bodySize(actualResponse.Body, actualResponse.Binary)}
+	response.Content = &HARContent{Size: response.BodySize, MimeType: http.Header(actualResponse.Header).Get("Content-Type"), Text: actualResponse.Body,
+		Comment: bodyComment(false, actualResponse.BodyTruncated)}
+	if actualResponse.Binary {
+		response.Content.Encoding = "base64"
+	}
+	return response
+}
+
+func harNameValues(values map[string][]string) []*HARNameValue {
+	nameValues := []*HARNameValue{}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range values[name] {
+			nameValues = append(nameValues, &HARNameValue{Name: name, Value: value})
+		}
+	}
+	return nameValues
+}
+
+func bodySize(body string, binary bool) int {
+	if binary {
+		if data, err := base64.StdEncoding.DecodeString(body); err == nil {
+			return len(data)
+		}
+	}
+	return len(body)
+}
+
+func bodyComment(base64Encoded, truncated bool) string {
+	var comments []string
+	if base64Encoded {
+		comments = append(comments, "base64 encoded")
+	}
+	if truncated {
+		comments = append(comments, "truncated")
+	}
+	return strings.Join(comments, ", ")
+}
@@ -0,0 +1,59 @@
+package matches
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHAR(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	match := &Match{EndpointID: "createPayment", Timestamp: timestamp.Add(time.Second),
+		ActualRequest: &ActualRequest{Method: http.MethodPost, URL: "/payments?currency=EUR", Host: "localhost:8081",
+			Header: map[string][]string{"Content-Type": {"application/json"}, "Accept": {"text/plain", "application/json"}}, Body: `{ "amount": 42 }`},
+		ActualResponse: &ActualResponse{StatusCode: http.StatusAccepted, Header: map[string][]string{"Content-Type": {"image/png"}},
+			Body: "iVBORw0KGgo=", Binary: true, BodyTruncated: true, Duration: 1500 * time.Microsecond}}
+	mismatch := &Mismatch{MismatchDetails: "path '/unknown' not matched", Timestamp: timestamp,
+		ActualRequest: &ActualRequest{Method: http.MethodGet, URL: "/unknown", Host: "localhost:8081"}}
+
+	har := NewHAR("v1.4.0", []*Match{match}, []*Mismatch{mismatch})
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, &HARCreator{Name: "mockgo-server", Version: "v1.4.0"}, har.Log.Creator)
+	if !assert.Len(t, har.Log.Entries, 2) {
+		return
+	}
+	mismatchEntry := har.Log.Entries[0]
+	assert.Equal(t, "mismatch: path '/unknown' not matched", mismatchEntry.Comment)
+	assert.Equal(t, "http://localhost:8081/unknown", mismatchEntry.Request.URL)
+	assert.Equal(t, 0, mismatchEntry.Response.Status)
+	assert.Nil(t, mismatchEntry.Request.PostData)
+
+	matchEntry := har.Log.Entries[1]
+	assert.Equal(t, "endpointId: createPayment", matchEntry.Comment)
+	assert.Equal(t, match.Timestamp, matchEntry.StartedDateTime)
+	assert.Equal(t, 1.5, matchEntry.Time)
+	assert.Equal(t, &HARTimings{Wait: 1.5}, matchEntry.Timings)
+	assert.Equal(t, "http://localhost:8081/payments?currency=EUR", matchEntry.Request.URL)
+	assert.Equal(t, []*HARNameValue{{Name: "currency", Value: "EUR"}}, matchEntry.Request.QueryString)
+	assert.Equal(t, []*HARNameValue{{Name: "Accept", Value: "text/plain"}, {Name: "Accept", Value: "application/json"},
+		{Name: "Content-Type", Value: "application/json"}}, matchEntry.Request.Headers)
+	assert.Equal(t, &HARPostData{MimeType: "application/json", Text: `{ "amount": 42 }`}, matchEntry.Request.PostData)
+	assert.Equal(t, 16, matchEntry.Request.BodySize)
+	assert.Equal(t, http.StatusAccepted, matchEntry.Response.Status)
+	assert.Equal(t, "Accepted", matchEntry.Response.StatusText)
+	assert.Equal(t, &HARContent{Size: 8, MimeType: "image/png", Text: "iVBORw0KGgo=", Encoding: "base64", Comment: "truncated"}, matchEntry.Response.Content)
+
+	data, err := json.Marshal(har)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"cache":{}`)
+	assert.Contains(t, string(data), `"cookies":[]`)
+}
+
+func TestNewHAR_empty(t *testing.T) {
+	data, err := json.Marshal(NewHAR("v1.4.0", nil, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"log":{"version":"1.2","creator":{"name":"mockgo-server","version":"v1.4.0"},"entries":[]}}`, string(data))
+}
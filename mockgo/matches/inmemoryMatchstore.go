@@ -2,34 +2,83 @@ package matches
 
 import (
 	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 /*
-InMemoryMatchstore implements a Matchstore using local memory
+storedSequence numbers the stored entries of all InMemoryMatchstores, so that the oldest entry of several stores can be found
+*/
+var storedSequence uint64
+
+/*
+InMemoryMatchstore implements a Matchstore using local memory, it is safe for concurrent use
 */
 type InMemoryMatchstore struct {
-	size            uint16
+	lock            sync.Mutex
+	retention       *Retention
+	sized           bool
 	matches         map[string]*list.List
 	mismatches      *list.List
+	stored          *list.List
+	storedEntries   map[*list.Element]*list.Element
+	storedSize      int64
 	matchesCount    map[string]uint64
 	mismatchesCount uint64
+	events          *Broadcaster
 }
 
 /*
-NewInMemoryMatchstore creates a new instance of InMemoryMatchstore
+storedEntry refers to an element of the list of an endpoint or of the mismatches, the stored entries are ordered by insertion,
+so that the oldest element of all lists can be evicted
+*/
+type storedEntry struct {
+	sequence  uint64
+	eventType string
+	timestamp time.Time
+	size      int64
+	list      *list.List
+	element   *list.Element
+}
+
+/*
+NewInMemoryMatchstore creates a new instance of InMemoryMatchstore, which keeps size matches per endpoint and size mismatches
 */
 func NewInMemoryMatchstore(size uint16) Matchstore {
+	return NewInMemoryMatchstoreWithRetention(&Retention{Capacity: int(size)})
+}
+
+/*
+NewInMemoryMatchstoreWithRetention creates a new instance of InMemoryMatchstore, which evicts matches and mismatches according to the retention
+*/
+func NewInMemoryMatchstoreWithRetention(retention *Retention) Matchstore {
+	return newInMemoryMatchstore(retention, retention.MemoryLimit > 0, NewBroadcaster())
+}
+
+/*
+newInMemoryMatchstore creates a new instance of InMemoryMatchstore, the size of the entries is tracked when sized is true
+*/
+func newInMemoryMatchstore(retention *Retention, sized bool, events *Broadcaster) *InMemoryMatchstore {
 	return &InMemoryMatchstore{
-		matches:      map[string]*list.List{},
-		mismatches:   list.New(),
-		matchesCount: map[string]uint64{},
-		size:         size}
+		retention:     retention,
+		sized:         sized,
+		matches:       map[string]*list.List{},
+		mismatches:    list.New(),
+		stored:        list.New(),
+		storedEntries: map[*list.Element]*list.Element{},
+		matchesCount:  map[string]uint64{},
+		events:        events}
 }
 
 /*
 GetMatches returns all matches of http requests which hit an endpoint
 */
 func (s *InMemoryMatchstore) GetMatches(endpointID string) ([]*Match, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evict()
 	matchesResult := []*Match{}
 	matchesList := s.matches[endpointID]
 	if matchesList != nil {
@@ -44,6 +93,8 @@ func (s *InMemoryMatchstore) GetMatches(endpointID string) ([]*Match, error) {
 GetMatchesCount returns the count of all matches of http requests which hit an endpoint
 */
 func (s *InMemoryMatchstore) GetMatchesCount(endpointID string) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	return s.matchesCount[endpointID], nil
 }
 
@@ -51,6 +102,9 @@ func (s *InMemoryMatchstore) GetMatchesCount(endpointID string) (uint64, error)
 GetMismatches returns all mismatches of http requests
 */
 func (s *InMemoryMatchstore) GetMismatches() ([]*Mismatch, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evict()
 	mismatchesResult := []*Mismatch{}
 	for mismatch := s.mismatches.Front(); mismatch != nil; mismatch = mismatch.Next() {
 		mismatchesResult = append(mismatchesResult, mismatch.Value.(*Mismatch))
@@ -58,15 +112,85 @@ func (s *InMemoryMatchstore) GetMismatches() ([]*Mismatch, error) {
 	return mismatchesResult, nil
 }
 
+/*
+QueryMatches returns the matches of an endpoint which pass the filters of the query
+*/
+func (s *InMemoryMatchstore) QueryMatches(endpointID string, query *Query) ([]*Match, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evict()
+	matchesResult := []*Match{}
+	matchesList := s.matches[endpointID]
+	if matchesList == nil {
+		return matchesResult, nil
+	}
+	skipped := 0
+	for element := front(matchesList, query.Descending); element != nil; element = next(element, query.Descending) {
+		match := element.Value.(*Match)
+		if !query.MatchesMatch(match) {
+			continue
+		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		matchesResult = append(matchesResult, match)
+		if len(matchesResult) == query.Limit {
+			break
+		}
+	}
+	return matchesResult, nil
+}
+
+/*
+QueryMismatches returns the mismatches which pass the filters of the query
+*/
+func (s *InMemoryMatchstore) QueryMismatches(query *Query) ([]*Mismatch, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evict()
+	mismatchesResult := []*Mismatch{}
+	skipped := 0
+	for element := front(s.mismatches, query.Descending); element != nil; element = next(element, query.Descending) {
+		mismatch := element.Value.(*Mismatch)
+		if !query.MatchesMismatch(mismatch) {
+			continue
+		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		mismatchesResult = append(mismatchesResult, mismatch)
+		if len(mismatchesResult) == query.Limit {
+			break
+		}
+	}
+	return mismatchesResult, nil
+}
+
+func front(l *list.List, descending bool) *list.Element {
+	if descending {
+		return l.Back()
+	}
+	return l.Front()
+}
+
+func next(element *list.Element, descending bool) *list.Element {
+	if descending {
+		return element.Prev()
+	}
+	return element.Next()
+}
+
 /*
 AddMismatch registers a mismatch
 */
 func (s *InMemoryMatchstore) AddMismatch(mismatch *Mismatch) error {
-	s.mismatches.PushBack(mismatch)
-	if uint16(s.mismatches.Len()) > s.size {
-		s.mismatches.Remove(s.mismatches.Front())
-	}
+	s.lock.Lock()
+	s.add(s.mismatches, EventTypeMismatch, mismatch, mismatch.Timestamp)
 	s.mismatchesCount++
+	s.lock.Unlock()
+	s.events.Publish(&Event{Type: EventTypeMismatch, Mismatch: mismatch})
 	return nil
 }
 
@@ -74,22 +198,73 @@ func (s *InMemoryMatchstore) AddMismatch(mismatch *Mismatch) error {
 AddMatch registers a match for an endpoint
 */
 func (s *InMemoryMatchstore) AddMatch(endpointID string, match *Match) error {
+	s.lock.Lock()
 	if s.matches[endpointID] == nil {
 		s.matches[endpointID] = list.New()
 	}
-	matches := s.matches[endpointID]
-	matches.PushBack(match)
-	if uint16(matches.Len()) > s.size {
-		matches.Remove(matches.Front())
-	}
+	s.add(s.matches[endpointID], EventTypeMatch, match, match.Timestamp)
 	s.matchesCount[endpointID]++
+	s.lock.Unlock()
+	s.events.Publish(&Event{Type: EventTypeMatch, Match: match})
 	return nil
 }
 
+/*
+add appends a match or mismatch to the list and evicts according to the retention
+*/
+func (s *InMemoryMatchstore) add(l *list.List, eventType string, value interface{}, timestamp time.Time) {
+	entry := &storedEntry{sequence: atomic.AddUint64(&storedSequence, 1), eventType: eventType, timestamp: timestamp, list: l, element: l.PushBack(value)}
+	if s.sized {
+		entry.size = jsonSize(value)
+	}
+	s.storedEntries[entry.element] = s.stored.PushBack(entry)
+	atomic.AddInt64(&s.storedSize, entry.size)
+	if s.retention.ExceedsCapacity(int64(l.Len())) {
+		s.remove(l, l.Front(), EvictionReasonCapacity)
+	}
+	s.evict()
+}
+
+/*
+evict removes the oldest entries of all lists as long as they are expired or the memory limit is exceeded
+*/
+func (s *InMemoryMatchstore) evict() {
+	now := time.Now()
+	for oldest := s.stored.Front(); oldest != nil; oldest = s.stored.Front() {
+		entry := oldest.Value.(*storedEntry)
+		if s.retention.Expired(entry.timestamp, now) {
+			s.remove(entry.list, entry.element, EvictionReasonTTL)
+		} else if s.retention.ExceedsMemoryLimit(atomic.LoadInt64(&s.storedSize)) {
+			s.remove(entry.list, entry.element, EvictionReasonMemory)
+		} else {
+			return
+		}
+	}
+}
+
+/*
+remove unlinks the element from its list and from the stored entries, an eviction is recorded when a reason is given
+*/
+func (s *InMemoryMatchstore) remove(l *list.List, element *list.Element, reason string) {
+	l.Remove(element)
+	stored := s.storedEntries[element]
+	if stored == nil {
+		return
+	}
+	delete(s.storedEntries, element)
+	entry := s.stored.Remove(stored).(*storedEntry)
+	atomic.AddInt64(&s.storedSize, -entry.size)
+	if len(reason) > 0 {
+		RecordEvictions(entry.eventType, reason, 1)
+	}
+}
+
 /*
 GetMismatchesCount returns count of all mismatches
 */
 func (s *InMemoryMatchstore) GetMismatchesCount() (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	return s.mismatchesCount, nil
 }
 
@@ -97,10 +272,12 @@ func (s *InMemoryMatchstore) GetMismatchesCount() (uint64, error) {
 DeleteMatches unregisters all matches for an endpoint
 */
 func (s *InMemoryMatchstore) DeleteMatches(endpointID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	matchesList := s.matches[endpointID]
 	if matchesList != nil {
 		for match := matchesList.Front(); match != nil; match = matchesList.Front() {
-			matchesList.Remove(match)
+			s.remove(matchesList, match, "")
 		}
 	}
 	s.matchesCount[endpointID] = uint64(0)
@@ -111,13 +288,53 @@ func (s *InMemoryMatchstore) DeleteMatches(endpointID string) error {
 DeleteMismatches unregisters all mismatches
 */
 func (s *InMemoryMatchstore) DeleteMismatches() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	for mismatch := s.mismatches.Front(); mismatch != nil; mismatch = s.mismatches.Front() {
-		s.mismatches.Remove(mismatch)
+		s.remove(s.mismatches, mismatch, "")
 	}
 	s.mismatchesCount = uint64(0)
 	return nil
 }
 
+/*
+storedSizeBytes returns the size in bytes of all stored entries, when the size is tracked
+*/
+func (s *InMemoryMatchstore) storedSizeBytes() int64 {
+	return atomic.LoadInt64(&s.storedSize)
+}
+
+/*
+oldest returns the sequence of the oldest stored entry, false when nothing is stored
+*/
+func (s *InMemoryMatchstore) oldest() (uint64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if front := s.stored.Front(); front != nil {
+		return front.Value.(*storedEntry).sequence, true
+	}
+	return 0, false
+}
+
+/*
+evictOldest removes the oldest stored entry and records the eviction with the reason
+*/
+func (s *InMemoryMatchstore) evictOldest(reason string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if front := s.stored.Front(); front != nil {
+		entry := front.Value.(*storedEntry)
+		s.remove(entry.list, entry.element, reason)
+	}
+}
+
+/*
+Subscribe returns the events of the added matches and mismatches until the context is done
+*/
+func (s *InMemoryMatchstore) Subscribe(ctx context.Context) (<-chan *Event, error) {
+	return s.events.Subscribe(ctx), nil
+}
+
 /*
 Shutdown is a no-op for InMemoryMatchstore
 */
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -180,3 +182,102 @@ func TestInMemoryMatchstore_DeleteMismatches(t *testing.T) {
 	assert.Equal(t, 0, matchstore.mismatches.Len())
 	assert.Equal(t, uint64(0), matchstore.mismatchesCount)
 }
+
+func TestInMemoryMatchstore_QueryMatches(t *testing.T) {
+	matchstore := NewInMemoryMatchstore(10)
+	for i := 0; i < 6; i++ {
+		match := createMatch(endpointID1)
+		match.Timestamp = timeStamp.Add(time.Duration(i) * time.Second)
+		match.ActualRequest.Method = []string{http.MethodGet, http.MethodPost}[i%2]
+		assert.NoError(t, matchstore.AddMatch(endpointID1, match))
+	}
+	matches, err := matchstore.QueryMatches(endpointID1, &Query{Method: http.MethodPost, Offset: 1, Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, timeStamp.Add(3*time.Second), matches[0].Timestamp)
+	}
+	matches, err = matchstore.QueryMatches(endpointID1, &Query{From: timeStamp.Add(time.Second), To: timeStamp.Add(4 * time.Second), Descending: true})
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 4) {
+		assert.Equal(t, timeStamp.Add(4*time.Second), matches[0].Timestamp)
+		assert.Equal(t, timeStamp.Add(time.Second), matches[3].Timestamp)
+	}
+	matches, err = matchstore.QueryMatches(endpointID2, &Query{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Match{}, matches)
+}
+
+func TestInMemoryMatchstore_QueryMismatches(t *testing.T) {
+	matchstore := NewInMemoryMatchstore(10)
+	for i := 0; i < 4; i++ {
+		mismatch := createMismatch()
+		mismatch.Timestamp = timeStamp.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, matchstore.AddMismatch(mismatch))
+	}
+	mismatches, err := matchstore.QueryMismatches(&Query{Limit: 2, Descending: true})
+	assert.NoError(t, err)
+	if assert.Len(t, mismatches, 2) {
+		assert.Equal(t, timeStamp.Add(3*time.Second), mismatches[0].Timestamp)
+		assert.Equal(t, timeStamp.Add(2*time.Second), mismatches[1].Timestamp)
+	}
+	mismatches, err = matchstore.QueryMismatches(&Query{Path: "/other/*"})
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestInMemoryMatchstore_Retention_TTL(t *testing.T) {
+	matchstore := NewInMemoryMatchstoreWithRetention(&Retention{TTL: time.Minute}).(*InMemoryMatchstore)
+	evictedMatches := promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMatch, "reason": EvictionReasonTTL}))
+	expiredMatch, recentMatch := createMatch(endpointID1), createMatch(endpointID1)
+	recentMatch.Timestamp = time.Now()
+	assert.NoError(t, matchstore.AddMatch(endpointID1, expiredMatch))
+	assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+	assert.NoError(t, matchstore.AddMatch(endpointID1, recentMatch))
+
+	matches, err := matchstore.GetMatches(endpointID1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Match{recentMatch}, matches)
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+	matchesCount, err := matchstore.GetMatchesCount(endpointID1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), matchesCount)
+	assert.Equal(t, 1, matchstore.stored.Len())
+	assert.Equal(t, evictedMatches+1, promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMatch, "reason": EvictionReasonTTL})))
+}
+
+func TestInMemoryMatchstore_Retention_MemoryLimit(t *testing.T) {
+	matchSize := jsonSize(createMatch(endpointID1))
+	matchstore := NewInMemoryMatchstoreWithRetention(&Retention{MemoryLimit: 2*matchSize + 1}).(*InMemoryMatchstore)
+	evictedMatches := promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMatch, "reason": EvictionReasonMemory}))
+	oldestMatch, match2, match1 := createMatch(endpointID1), createMatch(endpointID2), createMatch(endpointID1)
+	for _, match := range []*Match{oldestMatch, match2, match1} {
+		assert.NoError(t, matchstore.AddMatch(match.EndpointID, match))
+	}
+	matches, err := matchstore.GetMatches(endpointID1)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Same(t, match1, matches[0])
+	matches, err = matchstore.GetMatches(endpointID2)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, 2*matchSize, matchstore.storedSize)
+	assert.Equal(t, evictedMatches+1, promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMatch, "reason": EvictionReasonMemory})))
+
+	assert.NoError(t, matchstore.DeleteMatches(endpointID1))
+	assert.Equal(t, matchSize, matchstore.storedSize)
+	assert.Equal(t, 1, matchstore.stored.Len())
+	assert.Len(t, matchstore.storedEntries, 1)
+}
+
+func TestInMemoryMatchstore_Retention_Capacity(t *testing.T) {
+	matchstore := NewInMemoryMatchstoreWithRetention(&Retention{Capacity: 70000}).(*InMemoryMatchstore)
+	evictedMismatches := promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMismatch, "reason": EvictionReasonCapacity}))
+	for i := 0; i < 70001; i++ {
+		assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+	}
+	assert.Equal(t, 70000, matchstore.mismatches.Len())
+	assert.Equal(t, 70000, matchstore.stored.Len())
+	assert.Equal(t, evictedMismatches+1, promtestutil.ToFloat64(evictionsMetric.With(prometheus.Labels{"type": EventTypeMismatch, "reason": EvictionReasonCapacity})))
+}
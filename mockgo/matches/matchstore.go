@@ -1,55 +1,139 @@
 package matches
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 /*
 Match datamodel for a http request which hit an endpoint
 */
 type Match struct {
-	EndpointID     string          `json:"endpointId"`
-	Timestamp      time.Time       `json:"timestamp"`
-	ActualRequest  *ActualRequest  `json:"actualRequest"`
-	ActualResponse *ActualResponse `json:"actualResponse"`
+	EndpointID       string             `json:"endpointId"`
+	Timestamp        time.Time          `json:"timestamp"`
+	ActualRequest    *ActualRequest     `json:"actualRequest"`
+	ActualResponse   *ActualResponse    `json:"actualResponse"`
+	ActualMessages   []*ActualMessage   `json:"actualMessages,omitempty"`
+	Mail             *Mail              `json:"mail,omitempty"`
+	CallbackAttempts []*CallbackAttempt `json:"callbackAttempts,omitempty"`
 }
 
 /*
-Mismatch datamodel for a http request which missed an endpoint
+Mismatch datamodel for a http request which missed an endpoint, NearestEndpoints are the endpoints which came closest to the request
 */
 type Mismatch struct {
-	MismatchDetails string         `json:"MismatchDetails"`
-	Timestamp       time.Time      `json:"timestamp"`
-	ActualRequest   *ActualRequest `json:"actualRequest"`
+	MismatchDetails  string             `json:"MismatchDetails"`
+	Timestamp        time.Time          `json:"timestamp"`
+	ActualRequest    *ActualRequest     `json:"actualRequest"`
+	NearestEndpoints []*NearestEndpoint `json:"nearestEndpoints,omitempty"`
 }
 
 /*
-ActualRequest datamodel for an incoming http request which is stored for a match or mismatch
+NearestEndpoint datamodel for an endpoint which didn't match a request, PathDistance is the edit distance between
+the endpoint path and the request path
+*/
+type NearestEndpoint struct {
+	EndpointID   string        `json:"endpointId"`
+	Method       string        `json:"method"`
+	Path         string        `json:"path"`
+	PathDistance int           `json:"pathDistance"`
+	Differences  []*Difference `json:"differences"`
+}
+
+/*
+Difference datamodel for a matcher of an endpoint which failed for a request
+*/
+type Difference struct {
+	Matcher  string `json:"matcher"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+/*
+ActualRequest datamodel for an incoming http request which is stored for a match or mismatch, the body is encoded with EncodeBody
 */
 type ActualRequest struct {
-	Method string              `json:"method" `
-	URL    string              `json:"url" `
-	Header map[string][]string `json:"header" `
-	Host   string              `json:"host" `
+	Method        string              `json:"method" `
+	URL           string              `json:"url" `
+	Header        map[string][]string `json:"header" `
+	Host          string              `json:"host" `
+	RemoteAddr    string              `json:"remoteAddr,omitempty"`
+	Body          string              `json:"body,omitempty"`
+	Binary        bool                `json:"binary,omitempty"`
+	BodyTruncated bool                `json:"bodyTruncated,omitempty"`
 }
 
 /*
-ActualResponse datamodel for an outgoing http response from a request which is stored for a match
+ActualResponse datamodel for an outgoing http response from a request which is stored for a match, the body is encoded with EncodeBody
+and Duration is the time for rendering the response
 */
 type ActualResponse struct {
+	StatusCode     int                 `json:"statusCode"`
+	Header         map[string][]string `json:"header"`
+	StreamedEvents int                 `json:"streamedEvents"`
+	Body           string              `json:"body,omitempty"`
+	Binary         bool                `json:"binary,omitempty"`
+	BodyTruncated  bool                `json:"bodyTruncated,omitempty"`
+	Duration       time.Duration       `json:"duration"`
+}
+
+/*
+ActualMessage datamodel for an inbound websocket, tcp or udp message which is stored for a match
+*/
+type ActualMessage struct {
+	Timestamp time.Time `json:"timestamp"`
+	Binary    bool      `json:"binary"`
+	Data      string    `json:"data"`
+}
+
+/*
+Mail datamodel for a mail received by the smtp server which is stored for a match
+*/
+type Mail struct {
+	From    string              `json:"from"`
+	To      []string            `json:"to"`
+	Subject string              `json:"subject"`
+	Header  map[string][]string `json:"header"`
+	Parts   []*MailPart         `json:"parts"`
+	Size    int                 `json:"size"`
+}
+
+/*
+MailPart datamodel for a decoded mime part of a mail, the body of a binary part is base64 encoded
+*/
+type MailPart struct {
+	ContentType string `json:"contentType"`
+	Filename    string `json:"filename,omitempty"`
+	Binary      bool   `json:"binary"`
+	Body        string `json:"body"`
+}
+
+/*
+CallbackAttempt datamodel for an attempt to deliver a callback, Error is set when no response was received
+*/
+type CallbackAttempt struct {
+	Timestamp  time.Time           `json:"timestamp"`
 	StatusCode int                 `json:"statusCode"`
 	Header     map[string][]string `json:"header"`
+	Body       string              `json:"body"`
+	Error      string              `json:"error,omitempty"`
 }
 
 /*
 Matchstore is the interface for a storage which holds the http requests which matches mock endpoints.
+Subscribe returns the events of the matches and mismatches which are added from now on until the context is done.
 */
 type Matchstore interface {
 	GetMatches(endpointID string) ([]*Match, error)
 	GetMatchesCount(endpointID string) (uint64, error)
 	GetMismatches() ([]*Mismatch, error)
+	QueryMatches(endpointID string, query *Query) ([]*Match, error)
+	QueryMismatches(query *Query) ([]*Mismatch, error)
 	AddMatch(endpointID string, match *Match) error
 	AddMismatch(*Mismatch) error
 	GetMismatchesCount() (uint64, error)
 	DeleteMatches(endpointID string) error
 	DeleteMismatches() error
+	Subscribe(ctx context.Context) (<-chan *Event, error)
 	Shutdown() error
 }
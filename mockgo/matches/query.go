@@ -0,0 +1,164 @@
+package matches
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Query filters, orders and pages the matches or mismatches of a Matchstore, zero values don't filter
+*/
+type Query struct {
+	From       time.Time
+	To         time.Time
+	Method     string
+	Path       string
+	Header     string
+	Body       string
+	StatusCode int
+	Limit      int
+	Offset     int
+	Descending bool
+}
+
+/*
+ParseQuery creates a Query from the query parameters 'from', 'to', 'method', 'path', 'header', 'body', 'status', 'limit', 'offset' and 'order'
+*/
+func ParseQuery(values url.Values) (*Query, error) {
+	query := &Query{Method: values.Get("method"), Path: values.Get("path"), Header: values.Get("header"), Body: values.Get("body")}
+	var err error
+	timestamps := []*time.Time{&query.From, &query.To}
+	for i, param := range []string{"from", "to"} {
+		if value := values.Get(param); len(value) > 0 {
+			if *timestamps[i], err = time.Parse(time.RFC3339Nano, value); err != nil {
+				return nil, fmt.Errorf("error parsing query parameter '%s' , must be a RFC3339 timestamp: %v", param, err)
+			}
+		}
+	}
+	numbers := []*int{&query.StatusCode, &query.Limit, &query.Offset}
+	for i, param := range []string{"status", "limit", "offset"} {
+		if value := values.Get(param); len(value) > 0 {
+			if *numbers[i], err = strconv.Atoi(value); err != nil || *numbers[i] < 0 {
+				return nil, fmt.Errorf("error parsing query parameter '%s' , must be a positive number", param)
+			}
+		}
+	}
+	if len(query.Path) > 0 {
+		if _, err := path.Match(query.Path, ""); err != nil {
+			return nil, fmt.Errorf("error parsing query parameter 'path' , invalid glob '%s': %v", query.Path, err)
+		}
+	}
+	switch strings.ToLower(values.Get("order")) {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, fmt.Errorf("error parsing query parameter 'order' , must be 'asc' or 'desc'")
+	}
+	return query, nil
+}
+
+/*
+IsEmpty returns true when the query neither filters nor orders nor pages
+*/
+func (q *Query) IsEmpty() bool {
+	return *q == Query{}
+}
+
+/*
+MatchesMatch returns true when the match passes the filters of the query
+*/
+func (q *Query) MatchesMatch(match *Match) bool {
+	if q.StatusCode > 0 && (match.ActualResponse == nil || match.ActualResponse.StatusCode != q.StatusCode) {
+		return false
+	}
+	return q.matchesRequest(match.Timestamp, match.ActualRequest)
+}
+
+/*
+MatchesMismatch returns true when the mismatch passes the filters of the query, a mismatch never passes a status filter
+*/
+func (q *Query) MatchesMismatch(mismatch *Mismatch) bool {
+	if q.StatusCode > 0 {
+		return false
+	}
+	return q.matchesRequest(mismatch.Timestamp, mismatch.ActualRequest)
+}
+
+func (q *Query) matchesRequest(timestamp time.Time, actualRequest *ActualRequest) bool {
+	if !q.inTimeRange(timestamp) {
+		return false
+	}
+	if len(q.Method) == 0 && len(q.Path) == 0 && len(q.Header) == 0 && len(q.Body) == 0 {
+		return true
+	}
+	if actualRequest == nil {
+		return false
+	}
+	if len(q.Method) > 0 && !strings.EqualFold(actualRequest.Method, q.Method) {
+		return false
+	}
+	if len(q.Path) > 0 && !pathMatches(q.Path, actualRequest.URL) {
+		return false
+	}
+	if len(q.Header) > 0 && !headerContains(actualRequest.Header, q.Header) {
+		return false
+	}
+	return len(q.Body) == 0 || actualRequest.bodyContains(q.Body)
+}
+
+/*
+inTimeRange returns true when the timestamp is within the bounds from and to of the query
+*/
+func (q *Query) inTimeRange(timestamp time.Time) bool {
+	if !q.From.IsZero() && timestamp.Before(q.From) {
+		return false
+	}
+	return q.To.IsZero() || !timestamp.After(q.To)
+}
+
+/*
+pathMatches returns true when the path of the request url matches the glob pattern
+*/
+func pathMatches(pattern, requestURL string) bool {
+	requestPath := requestURL
+	if parsedURL, err := url.Parse(requestURL); err == nil {
+		requestPath = parsedURL.Path
+	}
+	matched, _ := path.Match(pattern, requestPath)
+	return matched
+}
+
+/*
+headerContains returns true when a header line 'Key: value' contains the substring, the comparison is case insensitive
+*/
+func headerContains(header map[string][]string, substring string) bool {
+	substring = strings.ToLower(substring)
+	for key, values := range header {
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(key+": "+value), substring) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+Page returns the bounds of the page defined by offset and limit for a result of count entries
+*/
+func (q *Query) Page(count int) (int, int) {
+	start := q.Offset
+	if start > count {
+		start = count
+	}
+	end := count
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+	return start, end
+}
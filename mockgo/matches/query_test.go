@@ -0,0 +1,83 @@
+package matches
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	query, err := ParseQuery(url.Values{"from": {"2009-11-17T20:00:00Z"}, "to": {"2009-11-17T21:00:00.5+01:00"}, "method": {"post"}, "path": {"/orders/*"},
+		"header": {"content-type: application/json"}, "body": {"amount"}, "status": {"201"}, "limit": {"10"}, "offset": {"5"}, "order": {"DESC"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &Query{From: time.Date(2009, 11, 17, 20, 0, 0, 0, time.UTC), To: time.Date(2009, 11, 17, 20, 0, 0, 500000000, time.UTC).In(query.To.Location()),
+		Method: "post", Path: "/orders/*", Header: "content-type: application/json", Body: "amount", StatusCode: 201, Limit: 10, Offset: 5, Descending: true}, query)
+
+	query, err = ParseQuery(url.Values{})
+	assert.NoError(t, err)
+	assert.True(t, query.IsEmpty())
+
+	for _, values := range []url.Values{{"from": {"yesterday"}}, {"to": {"2009-11-17"}}, {"status": {"ok"}}, {"limit": {"-1"}}, {"offset": {"x"}}, {"path": {"/orders/["}}, {"order": {"random"}}} {
+		_, err := ParseQuery(values)
+		assert.Error(t, err, "query parameters %v", values)
+	}
+}
+
+func TestQuery_MatchesMatch(t *testing.T) {
+	match := &Match{EndpointID: "createOrder", Timestamp: timeStamp,
+		ActualRequest:  &ActualRequest{Method: "POST", URL: "/orders/42?dryRun=true", Header: map[string][]string{"Content-Type": {"application/json"}}, Body: `{ "amount": 42 }`},
+		ActualResponse: &ActualResponse{StatusCode: 201}}
+	testCases := []struct {
+		name     string
+		query    *Query
+		expected bool
+	}{
+		{name: "empty", query: &Query{}, expected: true},
+		{name: "from", query: &Query{From: timeStamp.Add(-time.Second)}, expected: true},
+		{name: "from later", query: &Query{From: timeStamp.Add(time.Second)}, expected: false},
+		{name: "to", query: &Query{To: timeStamp}, expected: true},
+		{name: "to earlier", query: &Query{To: timeStamp.Add(-time.Second)}, expected: false},
+		{name: "method", query: &Query{Method: "post"}, expected: true},
+		{name: "wrong method", query: &Query{Method: "GET"}, expected: false},
+		{name: "path glob", query: &Query{Path: "/orders/*"}, expected: true},
+		{name: "wrong path glob", query: &Query{Path: "/orders"}, expected: false},
+		{name: "header", query: &Query{Header: "content-type: application/j"}, expected: true},
+		{name: "wrong header", query: &Query{Header: "Accept"}, expected: false},
+		{name: "body", query: &Query{Body: `"amount": 42`}, expected: true},
+		{name: "wrong body", query: &Query{Body: "price"}, expected: false},
+		{name: "status", query: &Query{StatusCode: 201}, expected: true},
+		{name: "wrong status", query: &Query{StatusCode: 200}, expected: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.query.MatchesMatch(match))
+		})
+	}
+	assert.False(t, (&Query{StatusCode: 200}).MatchesMatch(&Match{Timestamp: timeStamp, ActualRequest: match.ActualRequest}))
+	assert.True(t, (&Query{Method: "POST"}).MatchesMismatch(&Mismatch{Timestamp: timeStamp, ActualRequest: match.ActualRequest}))
+	assert.False(t, (&Query{StatusCode: 201}).MatchesMismatch(&Mismatch{Timestamp: timeStamp, ActualRequest: match.ActualRequest}))
+
+	body, binary, _ := EncodeBody([]byte("\xff\xfeamount=42"), DefaultBodyLimit)
+	binaryMatch := &Match{Timestamp: timeStamp, ActualRequest: &ActualRequest{Body: body, Binary: binary}}
+	assert.True(t, (&Query{Body: "amount=42"}).MatchesMatch(binaryMatch), "binary body must be decoded")
+	assert.False(t, (&Query{Body: body}).MatchesMatch(binaryMatch))
+}
+
+func TestQuery_Page(t *testing.T) {
+	for _, testCase := range []struct {
+		query              *Query
+		count, start, stop int
+	}{
+		{query: &Query{}, count: 5, start: 0, stop: 5},
+		{query: &Query{Limit: 2}, count: 5, start: 0, stop: 2},
+		{query: &Query{Offset: 2, Limit: 2}, count: 5, start: 2, stop: 4},
+		{query: &Query{Offset: 4, Limit: 2}, count: 5, start: 4, stop: 5},
+		{query: &Query{Offset: 7}, count: 5, start: 5, stop: 5},
+	} {
+		start, stop := testCase.query.Page(testCase.count)
+		assert.Equal(t, testCase.start, start, "start of %+v", testCase.query)
+		assert.Equal(t, testCase.stop, stop, "stop of %+v", testCase.query)
+	}
+}
@@ -1,6 +1,8 @@
 package matches
 
 import (
+	"encoding/json"
+	"fmt"
 	"go.uber.org/zap"
 	"net/http"
 
@@ -15,6 +17,9 @@ RequestHandler implements an http API to access the Matchstore
 type RequestHandler struct {
 	pathPrefix        string
 	matchStore        Matchstore
+	verifier          *Verifier
+	endpointIDs       func() []string
+	version           string
 	logger            *zap.Logger
 	basicAuthUsername string
 	basicAuthPassword string
@@ -27,11 +32,28 @@ func NewRequestHandler(pathPrefix string, matchStore Matchstore, logLevel string
 	configRouter := &RequestHandler{
 		pathPrefix: pathPrefix,
 		matchStore: matchStore,
+		verifier:   NewVerifier(matchStore, nil),
 		logger:     util.CreateLogger(logLevel),
 	}
 	return configRouter
 }
 
+/*
+SetEndpointIDs defines the provider of all endpoint ids, which are verified for expectations without endpoint id
+and exported to the http archive of the matches without endpoint id
+*/
+func (r *RequestHandler) SetEndpointIDs(endpointIDs func() []string) {
+	r.endpointIDs = endpointIDs
+	r.verifier.endpointIDs = endpointIDs
+}
+
+/*
+SetVersion defines the version of the mockgo-server, which is the creator version of the exported http archives
+*/
+func (r *RequestHandler) SetVersion(version string) {
+	r.version = version
+}
+
 /*
 AddRoutes adds mux.Routes for the http API to a given mux.Router
 */
@@ -49,15 +71,37 @@ func (r *RequestHandler) AddRoutes(router *mux.Router) {
 		HandlerFunc(util.PathParamRequest([]string{"endpointId"}, r.handleDeleteMatches))
 	router.NewRoute().Name("deleteMismatches").Path(r.pathPrefix + "/mismatches").Methods(http.MethodDelete).
 		HandlerFunc(r.handleDeleteMismatches)
+	router.NewRoute().Name("verify").Path(r.pathPrefix + "/verify").Methods(http.MethodPost).
+		HandlerFunc(util.JSONContentTypeRequest(r.handleVerify))
+	router.NewRoute().Name("getMatchesHAR").Path(r.pathPrefix + "/matches.har").Methods(http.MethodGet).
+		HandlerFunc(r.handleGetMatchesHAR)
+	router.NewRoute().Name("getMismatchesHAR").Path(r.pathPrefix + "/mismatches.har").Methods(http.MethodGet).
+		HandlerFunc(r.handleGetMismatchesHAR)
+	router.NewRoute().Name("events").Path(r.pathPrefix + "/events").Methods(http.MethodGet).
+		HandlerFunc(r.handleEvents)
 }
 
 func (r *RequestHandler) handleHealth(writer http.ResponseWriter, request *http.Request) {
 	writer.WriteHeader(http.StatusOK)
 }
 
+/*
+handleGetMatches returns the matches of an endpoint, query parameters filter, order and page the matches, see ParseQuery
+*/
 func (r *RequestHandler) handleGetMatches(writer http.ResponseWriter, request *http.Request) {
 	endpointID := mux.Vars(request)["endpointId"]
-	if matches, err := r.matchStore.GetMatches(endpointID); err != nil {
+	query, err := ParseQuery(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var matches []*Match
+	if query.IsEmpty() {
+		matches, err = r.matchStore.GetMatches(endpointID)
+	} else {
+		matches, err = r.matchStore.QueryMatches(endpointID, query)
+	}
+	if err != nil {
 		r.logger.Error("Error getting matches", zap.Error(err))
 		http.Error(writer, err.Error(), http.StatusInternalServerError)
 	} else {
@@ -75,8 +119,22 @@ func (r *RequestHandler) handleGetMatchesCount(writer http.ResponseWriter, reque
 	}
 }
 
+/*
+handleGetMismatches returns the mismatches, query parameters filter, order and page the mismatches, see ParseQuery
+*/
 func (r *RequestHandler) handleGetMismatches(writer http.ResponseWriter, request *http.Request) {
-	if mismatches, err := r.matchStore.GetMismatches(); err != nil {
+	query, err := ParseQuery(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var mismatches []*Mismatch
+	if query.IsEmpty() {
+		mismatches, err = r.matchStore.GetMismatches()
+	} else {
+		mismatches, err = r.matchStore.QueryMismatches(query)
+	}
+	if err != nil {
 		r.logger.Error("Error getting mismatches", zap.Error(err))
 		http.Error(writer, err.Error(), http.StatusInternalServerError)
 	} else {
@@ -111,3 +169,84 @@ func (r *RequestHandler) handleDeleteMismatches(writer http.ResponseWriter, requ
 		writer.WriteHeader(http.StatusOK)
 	}
 }
+
+/*
+handleGetMatchesHAR exports the matches of the endpoints of the query parameter 'endpointId', which can be repeated, as http archive.
+Without endpoint id the matches of all endpoints are exported, the other query parameters are applied to the matches of each endpoint.
+*/
+func (r *RequestHandler) handleGetMatchesHAR(writer http.ResponseWriter, request *http.Request) {
+	query, err := ParseQuery(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	endpointIDs := request.URL.Query()["endpointId"]
+	if len(endpointIDs) == 0 {
+		if r.endpointIDs == nil {
+			http.Error(writer, "error parsing query parameter 'endpointId' , must be defined", http.StatusBadRequest)
+			return
+		}
+		endpointIDs = r.endpointIDs()
+	}
+	var harMatches []*Match
+	for _, endpointID := range endpointIDs {
+		matches, err := r.matchStore.QueryMatches(endpointID, query)
+		if err != nil {
+			r.logger.Error("Error getting matches", zap.Error(err))
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		harMatches = append(harMatches, matches...)
+	}
+	writeHAR(writer, "matches.har", NewHAR(r.version, harMatches, nil))
+}
+
+/*
+handleGetMismatchesHAR exports the mismatches as http archive, query parameters filter, order and page the mismatches, see ParseQuery
+*/
+func (r *RequestHandler) handleGetMismatchesHAR(writer http.ResponseWriter, request *http.Request) {
+	query, err := ParseQuery(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mismatches, err := r.matchStore.QueryMismatches(query)
+	if err != nil {
+		r.logger.Error("Error getting mismatches", zap.Error(err))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeHAR(writer, "mismatches.har", NewHAR(r.version, nil, mismatches))
+}
+
+func writeHAR(writer http.ResponseWriter, filename string, har *HAR) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	util.WriteEntity(writer, har)
+}
+
+/*
+handleVerify verifies the expectations of the request body, the report is returned with status 200 when all expectations
+passed and with status 417 otherwise
+*/
+func (r *RequestHandler) handleVerify(writer http.ResponseWriter, request *http.Request) {
+	verification := &Verification{}
+	if err := json.NewDecoder(request.Body).Decode(verification); err != nil {
+		http.Error(writer, fmt.Sprintf("error parsing verification: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := r.verifier.Validate(verification); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := r.verifier.Verify(verification)
+	if err != nil {
+		r.logger.Error("Error verifying expectations", zap.Error(err))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !report.Passed {
+		writer.WriteHeader(http.StatusExpectationFailed)
+	}
+	util.WriteEntity(writer, report)
+}
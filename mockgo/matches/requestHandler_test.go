@@ -1,8 +1,12 @@
 package matches
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 
@@ -25,6 +29,15 @@ func (s *ErrorMatchstore) GetMatches(endpointID string) ([]*Match, error) {
 func (s *ErrorMatchstore) GetMismatches() ([]*Mismatch, error) {
 	return nil, fmt.Errorf("error in get mismatches")
 }
+func (s *ErrorMatchstore) QueryMatches(endpointID string, query *Query) ([]*Match, error) {
+	return nil, fmt.Errorf("error in query matches")
+}
+func (s *ErrorMatchstore) QueryMismatches(query *Query) ([]*Mismatch, error) {
+	return nil, fmt.Errorf("error in query mismatches")
+}
+func (s *ErrorMatchstore) Subscribe(ctx context.Context) (<-chan *Event, error) {
+	return nil, fmt.Errorf("error in subscribe")
+}
 func (s *ErrorMatchstore) GetMatchesCount(endpointID string) (uint64, error) {
 	return 0, fmt.Errorf("error in get matches count")
 }
@@ -82,6 +95,37 @@ func TestMatchesRequestHandler_getMatches_Error(t *testing.T) {
 	))
 }
 
+func TestMatchesRequestHandler_serving_queryMatches(t *testing.T) {
+	endpointID := "myQueryEndpointId"
+	err := matchesRequestHandler.matchStore.DeleteMatches(endpointID)
+	assert.NoError(t, err)
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPost} {
+		err = matchesRequestHandler.matchStore.AddMatch(endpointID, createMatchForRequest(endpointID, &http.Request{Method: method, URL: &url.URL{Path: "/orders/" + method}}))
+		assert.NoError(t, err)
+	}
+	request := testutil.CreateOutgoingRequest(t, http.MethodGet, "/matches/"+endpointID+"?method=POST&path=/orders/*&limit=1",
+		testutil.CreateHeader().WithAuth(username, password).WithJSONAccept(), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `[{"endpointId":"myQueryEndpointId","timestamp":"2009-11-17T20:34:58.651387237Z","actualRequest":{"method":"POST","url":"/orders/POST","header":null,"host":""},"actualResponse":null}]`, responseBody)
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodGet, "/matches/"+endpointID+"?limit=many",
+		testutil.CreateHeader().WithAuth(username, password).WithJSONAccept(), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, "error parsing query parameter 'limit' , must be a positive number\n", responseBody)
+	})
+}
+
+func TestMatchesRequestHandler_queryMatches_Error(t *testing.T) {
+	request := testutil.CreateIncomingRequest(http.MethodGet, "/matches?order=desc", testutil.CreateHeader(), "")
+	assert.NoError(t, testutil.AssertHandlerFunc(t, request, matchesRequestHandlerErroneous.handleGetMatches, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Equal(t, "error in query matches\n", responseBody)
+	},
+	))
+}
+
 func TestMatchesRequestHandler_serving_getMatchesCount(t *testing.T) {
 	endpointID := "myEndpointId"
 	err := matchesRequestHandler.matchStore.DeleteMatches(endpointID)
@@ -127,6 +171,28 @@ func TestMatchesRequestHandler_getMisMatches_Error(t *testing.T) {
 	))
 }
 
+func TestMatchesRequestHandler_serving_queryMismatches(t *testing.T) {
+	err := matchesRequestHandler.matchStore.DeleteMismatches()
+	assert.NoError(t, err)
+	err = matchesRequestHandler.matchStore.AddMismatch(createMismatch())
+	assert.NoError(t, err)
+	request := testutil.CreateOutgoingRequest(t, http.MethodGet, "/mismatches?method=DELETE",
+		testutil.CreateHeader().WithAuth(username, password).WithJSONAccept(), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `[]`, responseBody)
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodGet, "/mismatches?order=random",
+		testutil.CreateHeader().WithAuth(username, password).WithJSONAccept(), "")
+	testutil.AssertResponseStatusOfRequestCall(t, request, http.StatusBadRequest)
+	request = testutil.CreateIncomingRequest(http.MethodGet, "/mismatches?offset=1", testutil.CreateHeader(), "")
+	assert.NoError(t, testutil.AssertHandlerFunc(t, request, matchesRequestHandlerErroneous.handleGetMismatches, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Equal(t, "error in query mismatches\n", responseBody)
+	},
+	))
+}
+
 func TestMatchesRequestHandler_serving_getMismatchesCount(t *testing.T) {
 	err := matchesRequestHandler.matchStore.DeleteMismatches()
 	assert.NoError(t, err)
@@ -189,3 +255,109 @@ func TestMatchesRequestHandler_deleteMismatches_Error(t *testing.T) {
 	},
 	))
 }
+
+func TestMatchesRequestHandler_serving_verify(t *testing.T) {
+	endpointID := "myVerifyEndpointId"
+	err := matchesRequestHandler.matchStore.DeleteMatches(endpointID)
+	assert.NoError(t, err)
+	err = matchesRequestHandler.matchStore.AddMatch(endpointID, createMatch(endpointID))
+	assert.NoError(t, err)
+	request := testutil.CreateOutgoingRequest(t, http.MethodPost, "/verify", testutil.CreateHeader().WithAuth(username, password).WithJSONContentType(),
+		`{ "expectations": [ { "endpointId": "myVerifyEndpointId", "times": { "exactly": 1 } } ] }`)
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `{"passed":true,"expectations":[{"id":"expectation1","passed":true,"expected":"exactly 1","count":1}]}`, responseBody)
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodPost, "/verify", testutil.CreateHeader().WithAuth(username, password).WithJSONContentType(),
+		`{ "expectations": [ { "endpointId": "myVerifyEndpointId", "request": { "method": "POST" } } ] }`)
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusExpectationFailed, response.StatusCode)
+		assert.Contains(t, responseBody, `"passed":false`)
+		assert.Contains(t, responseBody, `"differences":["method doesn't match 'POST'"]`)
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodPost, "/verify", testutil.CreateHeader().WithAuth(username, password).WithJSONContentType(),
+		`{ "expectations": [ { "times": { "never": true } } ] }`)
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, "error parsing expectation 'expectation1' , endpointId or request must be defined\n", responseBody)
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodPost, "/verify", testutil.CreateHeader().WithAuth(username, password).WithJSONContentType(), `no json`)
+	testutil.AssertResponseStatusOfRequestCall(t, request, http.StatusBadRequest)
+}
+
+func TestMatchesRequestHandler_verify_Error(t *testing.T) {
+	request := testutil.CreateIncomingRequest(http.MethodPost, "/verify", testutil.CreateHeader().WithJSONContentType(), `{ "expectations": [ { "endpointId": "e" } ] }`)
+	assert.NoError(t, testutil.AssertHandlerFunc(t, request, matchesRequestHandlerErroneous.handleVerify, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Equal(t, "error in query matches\n", responseBody)
+	},
+	))
+}
+
+func TestMatchesRequestHandler_serving_getMatchesHAR(t *testing.T) {
+	endpointID := "myHAREndpointId"
+	err := matchesRequestHandler.matchStore.DeleteMatches(endpointID)
+	assert.NoError(t, err)
+	err = matchesRequestHandler.matchStore.AddMatch(endpointID, createMatch(endpointID))
+	assert.NoError(t, err)
+	request := testutil.CreateOutgoingRequest(t, http.MethodGet, "/matches.har?endpointId="+endpointID, testutil.CreateHeader().WithAuth(username, password), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `attachment; filename="matches.har"`, response.Header.Get("Content-Disposition"))
+		har := &HAR{}
+		if assert.NoError(t, json.Unmarshal([]byte(responseBody), har)) && assert.Len(t, har.Log.Entries, 1) {
+			assert.Equal(t, "endpointId: "+endpointID, har.Log.Entries[0].Comment)
+		}
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodGet, "/matches.har", testutil.CreateHeader().WithAuth(username, password), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, "error parsing query parameter 'endpointId' , must be defined\n", responseBody)
+	})
+}
+
+func TestMatchesRequestHandler_getMatchesHAR_allEndpoints(t *testing.T) {
+	matchstore := NewInMemoryMatchstore(uint16(10))
+	requestHandler := NewRequestHandler("", matchstore, "DEBUG")
+	requestHandler.SetEndpointIDs(func() []string { return []string{"a", "b"} })
+	requestHandler.SetVersion("v1.4.0")
+	assert.NoError(t, matchstore.AddMatch("a", createMatch("a")))
+	assert.NoError(t, matchstore.AddMatch("b", createMatch("b")))
+	assert.NoError(t, matchstore.AddMatch("c", createMatch("c")))
+	recorder := httptest.NewRecorder()
+	requestHandler.handleGetMatchesHAR(recorder, httptest.NewRequest(http.MethodGet, "/matches.har", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	har := &HAR{}
+	if assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), har)) {
+		assert.Equal(t, "v1.4.0", har.Log.Creator.Version)
+		assert.Len(t, har.Log.Entries, 2)
+	}
+}
+
+func TestMatchesRequestHandler_serving_getMismatchesHAR(t *testing.T) {
+	err := matchesRequestHandler.matchStore.DeleteMismatches()
+	assert.NoError(t, err)
+	err = matchesRequestHandler.matchStore.AddMismatch(createMismatch())
+	assert.NoError(t, err)
+	request := testutil.CreateOutgoingRequest(t, http.MethodGet, "/mismatches.har?method=GET", testutil.CreateHeader().WithAuth(username, password), "")
+	testutil.AssertResponseOfRequestCall(t, request, func(response *http.Response, responseBody string) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		har := &HAR{}
+		if assert.NoError(t, json.Unmarshal([]byte(responseBody), har)) && assert.Len(t, har.Log.Entries, 1) {
+			assert.Equal(t, 0, har.Log.Entries[0].Response.Status)
+		}
+	})
+	request = testutil.CreateOutgoingRequest(t, http.MethodGet, "/mismatches.har?limit=x", testutil.CreateHeader().WithAuth(username, password), "")
+	testutil.AssertResponseStatusOfRequestCall(t, request, http.StatusBadRequest)
+}
+
+func TestMatchesRequestHandler_HAR_Error(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	matchesRequestHandlerErroneous.handleGetMatchesHAR(recorder, httptest.NewRequest(http.MethodGet, "/matches.har?endpointId=e", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "error in query matches\n", recorder.Body.String())
+	recorder = httptest.NewRecorder()
+	matchesRequestHandlerErroneous.handleGetMismatchesHAR(recorder, httptest.NewRequest(http.MethodGet, "/mismatches.har", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "error in query mismatches\n", recorder.Body.String())
+}
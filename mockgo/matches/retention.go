@@ -0,0 +1,85 @@
+package matches
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+/*
+EvictionReasonCapacity is the reason of an eviction, when the list of an endpoint or the mismatches exceed the capacity
+*/
+const EvictionReasonCapacity = "capacity"
+
+/*
+EvictionReasonTTL is the reason of an eviction, when a match or mismatch is older than the ttl
+*/
+const EvictionReasonTTL = "ttl"
+
+/*
+EvictionReasonMemory is the reason of an eviction, when all matches and mismatches together exceed the memory limit
+*/
+const EvictionReasonMemory = "memory"
+
+/*
+Retention defines which matches and mismatches a Matchstore keeps, a zero value doesn't limit.
+Capacity is the maximum count of matches per endpoint and of mismatches, TTL the maximum age of a match or mismatch and
+MemoryLimit the maximum total size in bytes of the json representations of all matches and mismatches, the oldest are evicted first.
+*/
+type Retention struct {
+	Capacity    int
+	TTL         time.Duration
+	MemoryLimit int64
+}
+
+/*
+Expired returns true when a match or mismatch with the timestamp is older than the ttl, a zero timestamp never expires
+*/
+func (r *Retention) Expired(timestamp, now time.Time) bool {
+	return r.TTL > 0 && !timestamp.IsZero() && now.Sub(timestamp) > r.TTL
+}
+
+/*
+ExceedsCapacity returns true when a list with the length has more entries than the capacity
+*/
+func (r *Retention) ExceedsCapacity(length int64) bool {
+	return r.Capacity > 0 && length > int64(r.Capacity)
+}
+
+/*
+ExceedsMemoryLimit returns true when the size in bytes is greater than the memory limit
+*/
+func (r *Retention) ExceedsMemoryLimit(size int64) bool {
+	return r.MemoryLimit > 0 && size > r.MemoryLimit
+}
+
+var evictionsMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "matchstore_evictions",
+		Help: "Number of matches and mismatches which were evicted from the matchstore",
+	},
+	[]string{"type", "reason"},
+)
+
+/*
+RegisterMetrics registers the prometheus metrics of the matchstore
+*/
+func RegisterMetrics() error {
+	return prometheus.Register(evictionsMetric)
+}
+
+/*
+RecordEvictions counts the evicted matches or mismatches, the eventType is EventTypeMatch or EventTypeMismatch
+*/
+func RecordEvictions(eventType, reason string, count int) {
+	evictionsMetric.With(prometheus.Labels{"type": eventType, "reason": reason}).Add(float64(count))
+}
+
+func jsonSize(value interface{}) int64 {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
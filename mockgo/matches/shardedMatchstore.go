@@ -0,0 +1,170 @@
+package matches
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alitari/mockgo-server/mockgo/util"
+)
+
+/*
+DefaultShards is the default count of shards of a ShardedMatchstore
+*/
+const DefaultShards = 32
+
+/*
+ShardedMatchstore implements a Matchstore using local memory, which is safe for concurrent use and scales with the count of endpoints.
+The matches are distributed by their endpoint id to InMemoryMatchstores with an own lock, the mismatches have an own InMemoryMatchstore.
+*/
+type ShardedMatchstore struct {
+	retention  *Retention
+	shards     []*InMemoryMatchstore
+	mismatches *InMemoryMatchstore
+	evictLock  sync.Mutex
+	events     *Broadcaster
+}
+
+/*
+NewShardedMatchstore creates a new instance of ShardedMatchstore, the memory limit of the retention applies to all shards together
+*/
+func NewShardedMatchstore(shards int, retention *Retention) Matchstore {
+	if shards < 1 {
+		shards = 1
+	}
+	shardRetention := &Retention{Capacity: retention.Capacity, TTL: retention.TTL}
+	sized := retention.MemoryLimit > 0
+	store := &ShardedMatchstore{retention: retention, events: NewBroadcaster()}
+	for i := 0; i < shards; i++ {
+		store.shards = append(store.shards, newInMemoryMatchstore(shardRetention, sized, store.events))
+	}
+	store.mismatches = newInMemoryMatchstore(shardRetention, sized, store.events)
+	return store
+}
+
+func (s *ShardedMatchstore) shard(endpointID string) *InMemoryMatchstore {
+	return s.shards[util.ShardIndex(len(s.shards), endpointID)]
+}
+
+/*
+GetMatches returns all matches of http requests which hit an endpoint
+*/
+func (s *ShardedMatchstore) GetMatches(endpointID string) ([]*Match, error) {
+	return s.shard(endpointID).GetMatches(endpointID)
+}
+
+/*
+GetMatchesCount returns the count of all matches of http requests which hit an endpoint
+*/
+func (s *ShardedMatchstore) GetMatchesCount(endpointID string) (uint64, error) {
+	return s.shard(endpointID).GetMatchesCount(endpointID)
+}
+
+/*
+GetMismatches returns all mismatches of http requests
+*/
+func (s *ShardedMatchstore) GetMismatches() ([]*Mismatch, error) {
+	return s.mismatches.GetMismatches()
+}
+
+/*
+QueryMatches returns the matches of an endpoint which pass the filters of the query
+*/
+func (s *ShardedMatchstore) QueryMatches(endpointID string, query *Query) ([]*Match, error) {
+	return s.shard(endpointID).QueryMatches(endpointID, query)
+}
+
+/*
+QueryMismatches returns the mismatches which pass the filters of the query
+*/
+func (s *ShardedMatchstore) QueryMismatches(query *Query) ([]*Mismatch, error) {
+	return s.mismatches.QueryMismatches(query)
+}
+
+/*
+AddMatch registers a match for an endpoint
+*/
+func (s *ShardedMatchstore) AddMatch(endpointID string, match *Match) error {
+	if err := s.shard(endpointID).AddMatch(endpointID, match); err != nil {
+		return err
+	}
+	s.evictMemory()
+	return nil
+}
+
+/*
+AddMismatch registers a mismatch
+*/
+func (s *ShardedMatchstore) AddMismatch(mismatch *Mismatch) error {
+	if err := s.mismatches.AddMismatch(mismatch); err != nil {
+		return err
+	}
+	s.evictMemory()
+	return nil
+}
+
+/*
+GetMismatchesCount returns count of all mismatches
+*/
+func (s *ShardedMatchstore) GetMismatchesCount() (uint64, error) {
+	return s.mismatches.GetMismatchesCount()
+}
+
+/*
+DeleteMatches unregisters all matches for an endpoint
+*/
+func (s *ShardedMatchstore) DeleteMatches(endpointID string) error {
+	return s.shard(endpointID).DeleteMatches(endpointID)
+}
+
+/*
+DeleteMismatches unregisters all mismatches
+*/
+func (s *ShardedMatchstore) DeleteMismatches() error {
+	return s.mismatches.DeleteMismatches()
+}
+
+/*
+Subscribe returns the events of the added matches and mismatches of all shards until the context is done
+*/
+func (s *ShardedMatchstore) Subscribe(ctx context.Context) (<-chan *Event, error) {
+	return s.events.Subscribe(ctx), nil
+}
+
+/*
+Shutdown is a no-op for ShardedMatchstore
+*/
+func (s *ShardedMatchstore) Shutdown() error {
+	return nil
+}
+
+func (s *ShardedMatchstore) storedSize() int64 {
+	size := s.mismatches.storedSizeBytes()
+	for _, shard := range s.shards {
+		size += shard.storedSizeBytes()
+	}
+	return size
+}
+
+/*
+evictMemory removes the oldest entries of all shards as long as the memory limit is exceeded,
+only one goroutine evicts at a time, the others don't wait for it
+*/
+func (s *ShardedMatchstore) evictMemory() {
+	if s.retention.MemoryLimit == 0 || !s.evictLock.TryLock() {
+		return
+	}
+	defer s.evictLock.Unlock()
+	for s.retention.ExceedsMemoryLimit(s.storedSize()) {
+		var oldestShard *InMemoryMatchstore
+		var oldestSequence uint64
+		for _, shard := range append([]*InMemoryMatchstore{s.mismatches}, s.shards...) {
+			if sequence, ok := shard.oldest(); ok && (oldestShard == nil || sequence < oldestSequence) {
+				oldestShard, oldestSequence = shard, sequence
+			}
+		}
+		if oldestShard == nil {
+			return
+		}
+		oldestShard.evictOldest(EvictionReasonMemory)
+	}
+}
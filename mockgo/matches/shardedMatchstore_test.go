@@ -0,0 +1,144 @@
+package matches
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedMatchstore(t *testing.T) {
+	matchstore := NewShardedMatchstore(4, &Retention{Capacity: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := matchstore.Subscribe(ctx)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		endpointID := "endpoint" + strconv.Itoa(i)
+		for _, match := range createMatchesForEndpoint(endpointID, i+1) {
+			assert.NoError(t, matchstore.AddMatch(endpointID, match))
+		}
+	}
+	assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+	for i := 0; i < 10; i++ {
+		endpointID := "endpoint" + strconv.Itoa(i)
+		matches, err := matchstore.GetMatches(endpointID)
+		assert.NoError(t, err)
+		assert.Len(t, matches, min3(i+1))
+		queriedMatches, err := matchstore.QueryMatches(endpointID, &Query{Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, queriedMatches, 1)
+		count, err := matchstore.GetMatchesCount(endpointID)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i+1), count)
+	}
+	mismatches, err := matchstore.QueryMismatches(&Query{})
+	assert.NoError(t, err)
+	assert.Len(t, mismatches, 1)
+	assert.Equal(t, EventTypeMatch, (<-events).Type)
+
+	assert.NoError(t, matchstore.DeleteMatches("endpoint9"))
+	matches, err := matchstore.GetMatches("endpoint9")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.NoError(t, matchstore.DeleteMismatches())
+	mismatches, err = matchstore.GetMismatches()
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+	count, err := matchstore.GetMismatchesCount()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+	assert.NoError(t, matchstore.Shutdown())
+}
+
+func min3(i int) int {
+	if i > 3 {
+		return 3
+	}
+	return i
+}
+
+func TestShardedMatchstore_MemoryLimit(t *testing.T) {
+	matchSize := jsonSize(createMatch(endpointID1))
+	mismatchSize := jsonSize(createMismatch())
+	matchstore := NewShardedMatchstore(4, &Retention{MemoryLimit: matchSize + mismatchSize}).(*ShardedMatchstore)
+	oldestMatch := createMatch(endpointID1)
+	assert.NoError(t, matchstore.AddMatch(endpointID1, oldestMatch))
+	assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+	assert.NoError(t, matchstore.AddMatch(endpointID2, createMatch(endpointID2)))
+
+	matches, err := matchstore.GetMatches(endpointID1)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	matches, err = matchstore.GetMatches(endpointID2)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	assert.Len(t, mismatches, 1)
+	assert.Equal(t, matchSize+mismatchSize, matchstore.storedSize())
+}
+
+func TestShardedMatchstore_concurrent(t *testing.T) {
+	matchstore := NewShardedMatchstore(DefaultShards, &Retention{Capacity: 50, MemoryLimit: 1 << 20})
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			endpointID := "endpoint" + strconv.Itoa(g%5)
+			for i := 0; i < 100; i++ {
+				assert.NoError(t, matchstore.AddMatch(endpointID, createMatch(endpointID)))
+				assert.NoError(t, matchstore.AddMismatch(createMismatch()))
+				_, err := matchstore.QueryMatches(endpointID, &Query{Limit: 10})
+				assert.NoError(t, err)
+				_, err = matchstore.GetMismatches()
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+	for i := 0; i < 5; i++ {
+		count, err := matchstore.GetMatchesCount("endpoint" + strconv.Itoa(i))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(400), count)
+	}
+	count, err := matchstore.GetMismatchesCount()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2000), count)
+}
+
+func benchmarkAddMatch(b *testing.B, matchstore Matchstore, endpoints int) {
+	match := createMatch(endpointID1)
+	var goroutines uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		endpointID := "endpoint" + strconv.Itoa(int(atomic.AddUint64(&goroutines, 1))%endpoints)
+		for i := 0; pb.Next(); i++ {
+			if err := matchstore.AddMatch(endpointID, match); err != nil {
+				b.Fatal(err)
+			}
+			if i%10 == 0 {
+				if _, err := matchstore.QueryMatches(endpointID, &Query{Limit: 10}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkInMemoryMatchstore_AddMatch(b *testing.B) {
+	benchmarkAddMatch(b, NewInMemoryMatchstoreWithRetention(&Retention{Capacity: 1000}), 64)
+}
+
+func BenchmarkShardedMatchstore_AddMatch(b *testing.B) {
+	benchmarkAddMatch(b, NewShardedMatchstore(DefaultShards, &Retention{Capacity: 1000}), 64)
+}
+
+func BenchmarkShardedMatchstore_AddMatch_memoryLimit(b *testing.B) {
+	benchmarkAddMatch(b, NewShardedMatchstore(DefaultShards, &Retention{Capacity: 1000, MemoryLimit: 1 << 20}), 64)
+}
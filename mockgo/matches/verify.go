@@ -0,0 +1,368 @@
+package matches
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const maxNearMisses = 3
+
+/*
+Verification is a set of expectations for the stored requests, InOrder defines the ids of expectations
+whose first matching requests must have been received in this order
+*/
+type Verification struct {
+	Expectations []*Expectation `json:"expectations"`
+	InOrder      []string       `json:"inOrder,omitempty"`
+}
+
+/*
+Expectation expects a number of requests which matched the endpoint and the request pattern within a time window.
+Without an endpoint id the requests of all endpoints are considered, without times at least one request is expected.
+*/
+type Expectation struct {
+	ID         string          `json:"id,omitempty"`
+	EndpointID string          `json:"endpointId,omitempty"`
+	Request    *RequestPattern `json:"request,omitempty"`
+	Times      *Times          `json:"times,omitempty"`
+	From       *time.Time      `json:"from,omitempty"`
+	To         *time.Time      `json:"to,omitempty"`
+	Within     string          `json:"within,omitempty"`
+}
+
+/*
+RequestPattern describes the expected requests, see Query for the semantics of the fields
+*/
+type RequestPattern struct {
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Header     string `json:"header,omitempty"`
+	Body       string `json:"body,omitempty"`
+	StatusCode int    `json:"status,omitempty"`
+}
+
+/*
+Times is the expected number of requests
+*/
+type Times struct {
+	Exactly *int `json:"exactly,omitempty"`
+	AtLeast *int `json:"atLeast,omitempty"`
+	AtMost  *int `json:"atMost,omitempty"`
+	Never   bool `json:"never,omitempty"`
+}
+
+/*
+VerificationReport is the result of a Verification
+*/
+type VerificationReport struct {
+	Passed       bool                 `json:"passed"`
+	Expectations []*ExpectationResult `json:"expectations"`
+	InOrder      *OrderResult         `json:"inOrder,omitempty"`
+}
+
+/*
+ExpectationResult is the result of an Expectation, for a failed expectation the nearest non-matching requests are reported
+*/
+type ExpectationResult struct {
+	ID         string      `json:"id"`
+	Passed     bool        `json:"passed"`
+	Expected   string      `json:"expected"`
+	Count      int         `json:"count"`
+	NearMisses []*NearMiss `json:"nearMisses,omitempty"`
+	first      *time.Time
+}
+
+/*
+NearMiss is a request within the time window of an expectation which differs from the request pattern
+*/
+type NearMiss struct {
+	EndpointID    string         `json:"endpointId,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"`
+	ActualRequest *ActualRequest `json:"actualRequest"`
+	Differences   []string       `json:"differences"`
+}
+
+/*
+OrderResult is the result of the InOrder check of a Verification
+*/
+type OrderResult struct {
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+/*
+Verifier verifies expectations with the requests of a Matchstore
+*/
+type Verifier struct {
+	matchstore  Matchstore
+	endpointIDs func() []string
+}
+
+/*
+NewVerifier creates an instance of Verifier, endpointIDs provides the endpoints for expectations without endpoint id
+*/
+func NewVerifier(matchstore Matchstore, endpointIDs func() []string) *Verifier {
+	return &Verifier{matchstore: matchstore, endpointIDs: endpointIDs}
+}
+
+/*
+Validate checks the verification and sets the default ids of the expectations
+*/
+func (v *Verifier) Validate(verification *Verification) error {
+	ids := map[string]bool{}
+	for i, expectation := range verification.Expectations {
+		if len(expectation.ID) == 0 {
+			expectation.ID = fmt.Sprintf("expectation%d", i+1)
+		}
+		if ids[expectation.ID] {
+			return fmt.Errorf("error parsing expectation '%s' , id is not unique", expectation.ID)
+		}
+		ids[expectation.ID] = true
+		if err := v.validateExpectation(expectation); err != nil {
+			return err
+		}
+	}
+	for _, id := range verification.InOrder {
+		if !ids[id] {
+			return fmt.Errorf("error parsing inOrder , expectation '%s' doesn't exist", id)
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) validateExpectation(expectation *Expectation) error {
+	if len(expectation.EndpointID) == 0 && expectation.Request == nil {
+		return fmt.Errorf("error parsing expectation '%s' , endpointId or request must be defined", expectation.ID)
+	}
+	if len(expectation.EndpointID) == 0 && v.endpointIDs == nil {
+		return fmt.Errorf("error parsing expectation '%s' , endpointId must be defined", expectation.ID)
+	}
+	if expectation.Request != nil && len(expectation.Request.Path) > 0 {
+		if _, err := path.Match(expectation.Request.Path, ""); err != nil {
+			return fmt.Errorf("error parsing expectation '%s' , invalid path glob '%s': %v", expectation.ID, expectation.Request.Path, err)
+		}
+	}
+	if len(expectation.Within) > 0 {
+		if within, err := time.ParseDuration(expectation.Within); err != nil || within <= 0 {
+			return fmt.Errorf("error parsing expectation '%s' , within must be a positive duration", expectation.ID)
+		}
+	}
+	if !expectation.Times.valid() {
+		return fmt.Errorf("error parsing expectation '%s' , times must be either never, exactly or a range of atLeast and atMost", expectation.ID)
+	}
+	return nil
+}
+
+/*
+Verify checks the expectations of a validated verification
+*/
+func (v *Verifier) Verify(verification *Verification) (*VerificationReport, error) {
+	report := &VerificationReport{Passed: true, Expectations: []*ExpectationResult{}}
+	results := map[string]*ExpectationResult{}
+	now := time.Now()
+	for _, expectation := range verification.Expectations {
+		result, err := v.verifyExpectation(expectation, now)
+		if err != nil {
+			return nil, err
+		}
+		report.Passed = report.Passed && result.Passed
+		report.Expectations = append(report.Expectations, result)
+		results[expectation.ID] = result
+	}
+	if len(verification.InOrder) > 0 {
+		report.InOrder = verifyOrder(verification.InOrder, results)
+		report.Passed = report.Passed && report.InOrder.Passed
+	}
+	return report, nil
+}
+
+func (v *Verifier) verifyExpectation(expectation *Expectation, now time.Time) (*ExpectationResult, error) {
+	window := expectation.window(now)
+	query := *window
+	if pattern := expectation.Request; pattern != nil {
+		query.Method, query.Path, query.Header, query.Body, query.StatusCode = pattern.Method, pattern.Path, pattern.Header, pattern.Body, pattern.StatusCode
+	}
+	endpointIDs := []string{expectation.EndpointID}
+	if len(expectation.EndpointID) == 0 {
+		endpointIDs = v.endpointIDs()
+	}
+
+	result := &ExpectationResult{ID: expectation.ID}
+	for _, endpointID := range endpointIDs {
+		matches, err := v.matchstore.QueryMatches(endpointID, &query)
+		if err != nil {
+			return nil, err
+		}
+		result.Count += len(matches)
+		for _, match := range matches {
+			if result.first == nil || match.Timestamp.Before(*result.first) {
+				timestamp := match.Timestamp
+				result.first = &timestamp
+			}
+		}
+	}
+	result.Passed, result.Expected = expectation.Times.check(result.Count)
+	if !result.Passed && result.Count < expectation.Times.minimum() && expectation.Request != nil {
+		nearMisses, err := v.nearMisses(endpointIDs, window, &query)
+		if err != nil {
+			return nil, err
+		}
+		result.NearMisses = nearMisses
+	}
+	return result, nil
+}
+
+/*
+window returns a query for the time window of the expectation, within limits the window to the duration before now
+*/
+func (e *Expectation) window(now time.Time) *Query {
+	window := &Query{}
+	if e.From != nil {
+		window.From = *e.From
+	}
+	if e.To != nil {
+		window.To = *e.To
+	}
+	if len(e.Within) > 0 {
+		within, _ := time.ParseDuration(e.Within)
+		if from := now.Add(-within); from.After(window.From) {
+			window.From = from
+		}
+	}
+	return window
+}
+
+/*
+nearMisses returns the requests within the time window which differ least from the request pattern,
+requests which didn't match any endpoint are considered as well
+*/
+func (v *Verifier) nearMisses(endpointIDs []string, window, pattern *Query) ([]*NearMiss, error) {
+	var nearMisses []*NearMiss
+	for _, endpointID := range endpointIDs {
+		matches, err := v.matchstore.QueryMatches(endpointID, window)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if differences := pattern.differences(match.ActualRequest, match.ActualResponse); len(differences) > 0 {
+				nearMisses = append(nearMisses, &NearMiss{EndpointID: endpointID, Timestamp: match.Timestamp, ActualRequest: match.ActualRequest, Differences: differences})
+			}
+		}
+	}
+	mismatches, err := v.matchstore.QueryMismatches(window)
+	if err != nil {
+		return nil, err
+	}
+	for _, mismatch := range mismatches {
+		differences := append(pattern.differences(mismatch.ActualRequest, nil), "request didn't match an endpoint")
+		nearMisses = append(nearMisses, &NearMiss{Timestamp: mismatch.Timestamp, ActualRequest: mismatch.ActualRequest, Differences: differences})
+	}
+	sort.SliceStable(nearMisses, func(i, j int) bool {
+		if len(nearMisses[i].Differences) != len(nearMisses[j].Differences) {
+			return len(nearMisses[i].Differences) < len(nearMisses[j].Differences)
+		}
+		return nearMisses[i].Timestamp.After(nearMisses[j].Timestamp)
+	})
+	if len(nearMisses) > maxNearMisses {
+		nearMisses = nearMisses[:maxNearMisses]
+	}
+	return nearMisses, nil
+}
+
+/*
+differences describes the fields of the request and response which don't match the query
+*/
+func (q *Query) differences(actualRequest *ActualRequest, actualResponse *ActualResponse) []string {
+	differences := []string{}
+	if actualRequest == nil {
+		return append(differences, "request not recorded")
+	}
+	match := &Match{ActualRequest: actualRequest, ActualResponse: actualResponse}
+	fields := []struct {
+		name     string
+		query    *Query
+		expected string
+	}{
+		{name: "method", query: &Query{Method: q.Method}, expected: q.Method},
+		{name: "path", query: &Query{Path: q.Path}, expected: q.Path},
+		{name: "header", query: &Query{Header: q.Header}, expected: q.Header},
+		{name: "body", query: &Query{Body: q.Body}, expected: q.Body},
+		{name: "status", query: &Query{StatusCode: q.StatusCode}, expected: fmt.Sprint(q.StatusCode)},
+	}
+	for _, field := range fields {
+		if !field.query.MatchesMatch(match) {
+			differences = append(differences, fmt.Sprintf("%s doesn't match '%s'", field.name, field.expected))
+		}
+	}
+	return differences
+}
+
+/*
+check returns whether the count of requests fulfills the times and a description of the expected times
+*/
+func (t *Times) check(count int) (bool, string) {
+	switch {
+	case t == nil:
+		return count >= 1, "at least 1"
+	case t.Never:
+		return count == 0, "never"
+	case t.Exactly != nil:
+		return count == *t.Exactly, fmt.Sprintf("exactly %d", *t.Exactly)
+	}
+	passed := true
+	var expected []string
+	if t.AtLeast != nil {
+		passed = count >= *t.AtLeast
+		expected = append(expected, fmt.Sprintf("at least %d", *t.AtLeast))
+	}
+	if t.AtMost != nil {
+		passed = passed && count <= *t.AtMost
+		expected = append(expected, fmt.Sprintf("at most %d", *t.AtMost))
+	}
+	if len(expected) == 0 {
+		return count >= 1, "at least 1"
+	}
+	return passed, strings.Join(expected, " and ")
+}
+
+/*
+valid returns false when never, exactly and the range of atLeast and atMost are combined
+*/
+func (t *Times) valid() bool {
+	if t == nil {
+		return true
+	}
+	return !(t.Never && (t.Exactly != nil || t.AtLeast != nil || t.AtMost != nil) ||
+		t.Exactly != nil && (t.AtLeast != nil || t.AtMost != nil))
+}
+
+func (t *Times) minimum() int {
+	switch {
+	case t == nil:
+		return 1
+	case t.Never:
+		return 0
+	case t.Exactly != nil:
+		return *t.Exactly
+	case t.AtLeast != nil:
+		return *t.AtLeast
+	case t.AtMost != nil:
+		return 0
+	}
+	return 1
+}
+
+func verifyOrder(inOrder []string, results map[string]*ExpectationResult) *OrderResult {
+	for i, id := range inOrder {
+		if results[id].first == nil {
+			return &OrderResult{Message: fmt.Sprintf("no request for expectation '%s'", id)}
+		}
+		if i > 0 && results[id].first.Before(*results[inOrder[i-1]].first) {
+			return &OrderResult{Message: fmt.Sprintf("first request for expectation '%s' was received before first request for expectation '%s'", id, inOrder[i-1])}
+		}
+	}
+	return &OrderResult{Passed: true}
+}
@@ -0,0 +1,126 @@
+package matches
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func createVerifyMatchstore(t *testing.T) Matchstore {
+	matchstore := NewInMemoryMatchstore(100)
+	now := time.Now()
+	for i, request := range []*ActualRequest{
+		{Method: "POST", URL: "/orders", Header: map[string][]string{"Content-Type": {"application/json"}}, Body: `{ "amount": 42 }`},
+		{Method: "POST", URL: "/orders", Header: map[string][]string{"Content-Type": {"text/plain"}}, Body: "amount=7"},
+	} {
+		assert.NoError(t, matchstore.AddMatch("createOrder", &Match{EndpointID: "createOrder", Timestamp: now.Add(time.Duration(i-10) * time.Second),
+			ActualRequest: request, ActualResponse: &ActualResponse{StatusCode: 201}}))
+	}
+	assert.NoError(t, matchstore.AddMatch("createPayment", &Match{EndpointID: "createPayment", Timestamp: now.Add(-5 * time.Second),
+		ActualRequest: &ActualRequest{Method: "POST", URL: "/payments"}, ActualResponse: &ActualResponse{StatusCode: 202}}))
+	assert.NoError(t, matchstore.AddMismatch(&Mismatch{Timestamp: now.Add(-time.Second), ActualRequest: &ActualRequest{Method: "PUT", URL: "/orders/1", Body: `{ "amount": 42 }`}}))
+	return matchstore
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	verifier := NewVerifier(createVerifyMatchstore(t), func() []string { return []string{"createOrder", "createPayment"} })
+	testCases := []struct {
+		name        string
+		expectation *Expectation
+		passed      bool
+		expected    string
+		count       int
+	}{
+		{name: "endpoint at least once", expectation: &Expectation{EndpointID: "createOrder"}, passed: true, expected: "at least 1", count: 2},
+		{name: "endpoint exactly", expectation: &Expectation{EndpointID: "createOrder", Times: &Times{Exactly: intPtr(1)}}, passed: false, expected: "exactly 1", count: 2},
+		{name: "pattern exactly", expectation: &Expectation{EndpointID: "createOrder", Request: &RequestPattern{Header: "application/json"}, Times: &Times{Exactly: intPtr(1)}}, passed: true, expected: "exactly 1", count: 1},
+		{name: "pattern of all endpoints", expectation: &Expectation{Request: &RequestPattern{Method: "POST"}, Times: &Times{AtLeast: intPtr(2), AtMost: intPtr(3)}}, passed: true, expected: "at least 2 and at most 3", count: 3},
+		{name: "never", expectation: &Expectation{EndpointID: "cancelOrder", Times: &Times{Never: true}}, passed: true, expected: "never", count: 0},
+		{name: "at most", expectation: &Expectation{Request: &RequestPattern{StatusCode: 201}, Times: &Times{AtMost: intPtr(1)}}, passed: false, expected: "at most 1", count: 2},
+		{name: "within", expectation: &Expectation{EndpointID: "createOrder", Within: "3s"}, passed: false, expected: "at least 1", count: 0},
+		{name: "time window", expectation: &Expectation{EndpointID: "createPayment", From: timePtr(time.Now().Add(-time.Minute)), To: timePtr(time.Now())}, passed: true, expected: "at least 1", count: 1},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			verification := &Verification{Expectations: []*Expectation{testCase.expectation}}
+			assert.NoError(t, verifier.Validate(verification))
+			report, err := verifier.Verify(verification)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.passed, report.Passed)
+			if assert.Len(t, report.Expectations, 1) {
+				assert.Equal(t, "expectation1", report.Expectations[0].ID)
+				assert.Equal(t, testCase.passed, report.Expectations[0].Passed)
+				assert.Equal(t, testCase.expected, report.Expectations[0].Expected)
+				assert.Equal(t, testCase.count, report.Expectations[0].Count)
+				assert.Empty(t, report.Expectations[0].NearMisses)
+			}
+		})
+	}
+}
+
+func timePtr(timestamp time.Time) *time.Time {
+	return &timestamp
+}
+
+func TestVerifier_Verify_nearMisses(t *testing.T) {
+	verifier := NewVerifier(createVerifyMatchstore(t), nil)
+	verification := &Verification{Expectations: []*Expectation{{ID: "jsonOrder", EndpointID: "createOrder", Request: &RequestPattern{Method: "POST", Path: "/orders", Body: `"amount": 43`}}}}
+	assert.NoError(t, verifier.Validate(verification))
+	report, err := verifier.Verify(verification)
+	assert.NoError(t, err)
+	assert.False(t, report.Passed)
+	nearMisses := report.Expectations[0].NearMisses
+	if assert.Len(t, nearMisses, 3) {
+		assert.Equal(t, "createOrder", nearMisses[0].EndpointID)
+		assert.Equal(t, []string{`body doesn't match '"amount": 43'`}, nearMisses[0].Differences)
+		assert.Equal(t, "amount=7", nearMisses[0].ActualRequest.Body)
+		assert.Equal(t, `{ "amount": 42 }`, nearMisses[1].ActualRequest.Body)
+		assert.Empty(t, nearMisses[2].EndpointID)
+		assert.Equal(t, []string{"method doesn't match 'POST'", "path doesn't match '/orders'", `body doesn't match '"amount": 43'`, "request didn't match an endpoint"}, nearMisses[2].Differences)
+	}
+}
+
+func TestVerifier_Verify_inOrder(t *testing.T) {
+	verifier := NewVerifier(createVerifyMatchstore(t), nil)
+	verification := &Verification{Expectations: []*Expectation{{ID: "order", EndpointID: "createOrder"}, {ID: "payment", EndpointID: "createPayment"}, {ID: "cancel", EndpointID: "cancelOrder", Times: &Times{Never: true}}},
+		InOrder: []string{"order", "payment"}}
+	report, err := verifier.Verify(verification)
+	assert.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.True(t, report.InOrder.Passed)
+
+	verification.InOrder = []string{"payment", "order"}
+	report, err = verifier.Verify(verification)
+	assert.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, "first request for expectation 'order' was received before first request for expectation 'payment'", report.InOrder.Message)
+
+	verification.InOrder = []string{"order", "cancel"}
+	report, err = verifier.Verify(verification)
+	assert.NoError(t, err)
+	assert.False(t, report.InOrder.Passed)
+	assert.Equal(t, "no request for expectation 'cancel'", report.InOrder.Message)
+}
+
+func TestVerifier_Validate(t *testing.T) {
+	verifier := NewVerifier(NewInMemoryMatchstore(10), nil)
+	for _, testCase := range []struct {
+		verification *Verification
+		expectedErr  string
+	}{
+		{verification: &Verification{Expectations: []*Expectation{{}}}, expectedErr: "error parsing expectation 'expectation1' , endpointId or request must be defined"},
+		{verification: &Verification{Expectations: []*Expectation{{Request: &RequestPattern{Method: "GET"}}}}, expectedErr: "error parsing expectation 'expectation1' , endpointId must be defined"},
+		{verification: &Verification{Expectations: []*Expectation{{ID: "a", EndpointID: "e"}, {ID: "a", EndpointID: "e"}}}, expectedErr: "error parsing expectation 'a' , id is not unique"},
+		{verification: &Verification{Expectations: []*Expectation{{EndpointID: "e", Request: &RequestPattern{Path: "/["}}}}, expectedErr: "error parsing expectation 'expectation1' , invalid path glob '/[': syntax error in pattern"},
+		{verification: &Verification{Expectations: []*Expectation{{EndpointID: "e", Within: "-1s"}}}, expectedErr: "error parsing expectation 'expectation1' , within must be a positive duration"},
+		{verification: &Verification{Expectations: []*Expectation{{EndpointID: "e", Times: &Times{Never: true, AtLeast: intPtr(1)}}}}, expectedErr: "error parsing expectation 'expectation1' , times must be either never, exactly or a range of atLeast and atMost"},
+		{verification: &Verification{Expectations: []*Expectation{{EndpointID: "e"}}, InOrder: []string{"other"}}, expectedErr: "error parsing inOrder , expectation 'other' doesn't exist"},
+	} {
+		assert.EqualError(t, verifier.Validate(testCase.verification), testCase.expectedErr)
+	}
+}
@@ -0,0 +1,217 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+const templateCallback = "callback"
+
+const maxCallbackResponseBodySize = 64 * 1024
+
+func callbackTemplateName(index int, field string) string {
+	return fmt.Sprintf("%s-%d-%s", templateCallback, index, field)
+}
+
+/*
+initCallbacks validates the callbacks of an endpoint or a job with the id ownerID and parses their templates into tmpl
+*/
+func initCallbacks(ownerID string, callbacks []*Callback, tmpl *template.Template) error {
+	for i, callback := range callbacks {
+		if len(callback.URL) == 0 {
+			return fmt.Errorf("error parsing callback %d of '%s' , url must be defined", i+1, ownerID)
+		}
+		if len(callback.ID) == 0 {
+			callback.ID = fmt.Sprintf("%s-callback-%d", ownerID, i+1)
+		}
+		if len(callback.Method) == 0 {
+			callback.Method = http.MethodPost
+		}
+		if len(callback.Delay) > 0 {
+			delay, err := time.ParseDuration(callback.Delay)
+			if err != nil {
+				return fmt.Errorf("error parsing delay of callback '%s' of '%s': %v", callback.ID, ownerID, err)
+			}
+			callback.DelayDuration = delay
+		}
+		if callback.Retry == nil {
+			callback.Retry = &CallbackRetry{}
+		}
+		if callback.Retry.Attempts < 1 {
+			callback.Retry.Attempts = 1
+		}
+		callback.Retry.BackoffDuration = time.Second
+		if len(callback.Retry.Backoff) > 0 {
+			backoff, err := time.ParseDuration(callback.Retry.Backoff)
+			if err != nil {
+				return fmt.Errorf("error parsing retry backoff of callback '%s' of '%s': %v", callback.ID, ownerID, err)
+			}
+			callback.Retry.BackoffDuration = backoff
+		}
+		for field, text := range map[string]string{"url": callback.URL, "headers": callback.Headers, "body": callback.Body} {
+			if _, err := tmpl.New(callbackTemplateName(i, field)).Parse(text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+triggerCallbacks renders the callbacks of an endpoint with the data of the request and sends them in the background,
+callbacks are only sent when the response was rendered successfully
+*/
+func (r *RequestHandler) triggerCallbacks(endpoint *Endpoint, match *matches.Match, request *http.Request, requestPathParams, queryParams map[string]string) {
+	if len(endpoint.Callbacks) == 0 || match.ActualResponse == nil {
+		return
+	}
+	responseTemplateData, err := r.createResponseTemplateData(request, requestPathParams, queryParams)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("can't create template data for callbacks of endpoint '%s'", endpoint.ID), zap.Error(err))
+		return
+	}
+	responseTemplateData.ResponseStatus = match.ActualResponse.StatusCode
+	r.sendCallbacks(endpoint.Callbacks, endpoint.Response.Template, responseTemplateData)
+}
+
+/*
+sendCallbacks renders the callbacks with the template data and delivers them in the background
+*/
+func (r *RequestHandler) sendCallbacks(callbacks []*Callback, tmpl *template.Template, templateData interface{}) {
+	for i, callback := range callbacks {
+		callbackMatch := &matches.Match{EndpointID: callback.ID, Timestamp: time.Now(),
+			ActualRequest:  &matches.ActualRequest{Method: callback.Method, Header: map[string][]string{}},
+			ActualResponse: &matches.ActualResponse{Header: map[string][]string{}}}
+		body, err := renderCallback(tmpl, i, callbackMatch.ActualRequest, templateData)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("can't render callback '%s'", callback.ID), zap.Error(err))
+			callbackMatch.CallbackAttempts = []*matches.CallbackAttempt{{Timestamp: time.Now(), Error: err.Error()}}
+			r.matchstore.AddMatch(callback.ID, callbackMatch)
+			continue
+		}
+		r.callbacksRunning.Add(1)
+		go func(callback *Callback) {
+			defer r.callbacksRunning.Done()
+			r.deliverCallback(callback, callbackMatch, body)
+		}(callback)
+	}
+}
+
+func renderCallback(tmpl *template.Template, index int, actualRequest *matches.ActualRequest, templateData interface{}) ([]byte, error) {
+	rendered := map[string][]byte{}
+	for _, field := range []string{"url", "headers", "body"} {
+		var renderedField bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&renderedField, callbackTemplateName(index, field), templateData); err != nil {
+			return nil, fmt.Errorf("error rendering callback %s: %v", field, err)
+		}
+		rendered[field] = renderedField.Bytes()
+	}
+	actualRequest.URL = strings.TrimSpace(string(rendered["url"]))
+	callbackURL, err := url.Parse(actualRequest.URL)
+	if err != nil || len(callbackURL.Host) == 0 {
+		return nil, fmt.Errorf("error parsing callback url '%s'", actualRequest.URL)
+	}
+	actualRequest.Host = callbackURL.Host
+	var headers map[string]string
+	if err := yaml.Unmarshal(rendered["headers"], &headers); err != nil {
+		return nil, fmt.Errorf("error unmarshalling callback headers: %v", err)
+	}
+	for key, val := range headers {
+		actualRequest.Header[http.CanonicalHeaderKey(key)] = []string{val}
+	}
+	return rendered["body"], nil
+}
+
+/*
+deliverCallback sends the callback after its delay and repeats it after the backoff until a 2xx status is received or
+the attempts are exhausted, all attempts are recorded in the matchstore under the id of the callback
+*/
+func (r *RequestHandler) deliverCallback(callback *Callback, callbackMatch *matches.Match, body []byte) {
+	defer func() {
+		r.matchstore.AddMatch(callback.ID, callbackMatch)
+	}()
+	if !r.waitForCallback(callback.DelayDuration) {
+		return
+	}
+	for attempt := 1; attempt <= callback.Retry.Attempts; attempt++ {
+		if attempt > 1 && !r.waitForCallback(callback.Retry.BackoffDuration) {
+			return
+		}
+		callbackAttempt := r.sendCallback(callbackMatch.ActualRequest, body)
+		callbackMatch.CallbackAttempts = append(callbackMatch.CallbackAttempts, callbackAttempt)
+		callbackMatch.ActualResponse = &matches.ActualResponse{StatusCode: callbackAttempt.StatusCode, Header: callbackAttempt.Header}
+		if callbackAttempt.StatusCode >= 200 && callbackAttempt.StatusCode < 300 {
+			r.logger.Debug(fmt.Sprintf("callback '%s' delivered with attempt %d", callback.ID, attempt))
+			return
+		}
+		r.logger.Info(fmt.Sprintf("callback '%s' not delivered with attempt %d: status %d %s", callback.ID, attempt, callbackAttempt.StatusCode, callbackAttempt.Error))
+	}
+}
+
+/*
+waitForCallback waits for the duration, it returns false when the callbacks are shut down in the meantime
+*/
+func (r *RequestHandler) waitForCallback(duration time.Duration) bool {
+	if duration <= 0 {
+		return r.callbackCtx.Err() == nil
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.callbackCtx.Done():
+		return false
+	}
+}
+
+func (r *RequestHandler) sendCallback(actualRequest *matches.ActualRequest, body []byte) *matches.CallbackAttempt {
+	callbackAttempt := &matches.CallbackAttempt{Timestamp: time.Now(), Header: map[string][]string{}}
+	request, err := http.NewRequestWithContext(r.callbackCtx, actualRequest.Method, actualRequest.URL, bytes.NewReader(body))
+	if err != nil {
+		callbackAttempt.Error = err.Error()
+		return callbackAttempt
+	}
+	for key, values := range actualRequest.Header {
+		request.Header[key] = values
+	}
+	response, err := r.callbackClient.Do(request)
+	if err != nil {
+		callbackAttempt.Error = err.Error()
+		return callbackAttempt
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxCallbackResponseBodySize))
+	if err != nil {
+		callbackAttempt.Error = err.Error()
+	}
+	callbackAttempt.StatusCode = response.StatusCode
+	callbackAttempt.Header = response.Header
+	callbackAttempt.Body = string(responseBody)
+	return callbackAttempt
+}
+
+/*
+WaitForCallbacks blocks until all triggered callbacks are delivered or have exhausted their attempts
+*/
+func (r *RequestHandler) WaitForCallbacks() {
+	r.callbacksRunning.Wait()
+}
+
+/*
+ShutdownCallbacks cancels pending callbacks and waits until their attempts are recorded
+*/
+func (r *RequestHandler) ShutdownCallbacks() {
+	r.cancelCallbacks()
+	r.callbacksRunning.Wait()
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type receivedCallback struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+func startCallbackReceiver(t *testing.T, statusCodes ...int) (*httptest.Server, func() []*receivedCallback) {
+	var lock sync.Mutex
+	var received []*receivedCallback
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		lock.Lock()
+		received = append(received, &receivedCallback{method: request.Method, path: request.URL.Path, header: request.Header, body: string(body)})
+		statusCode := http.StatusOK
+		if len(received) <= len(statusCodes) {
+			statusCode = statusCodes[len(received)-1]
+		}
+		lock.Unlock()
+		writer.WriteHeader(statusCode)
+		writer.Write([]byte("ack"))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []*receivedCallback {
+		lock.Lock()
+		defer lock.Unlock()
+		return received
+	}
+}
+
+func startCallbackMockServer(t *testing.T) (*RequestHandler, *httptest.Server, matches.Matchstore) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/callbackmocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return mockRequestHandler, server, matchstore
+}
+
+func TestCallback_deliveredWithRetry(t *testing.T) {
+	mockRequestHandler, server, matchstore := startCallbackMockServer(t)
+	receiver, received := startCallbackReceiver(t, http.StatusServiceUnavailable)
+	auditReceiver, auditReceived := startCallbackReceiver(t)
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/payments", strings.NewReader(`{ "id": "p1", "callbackUrl": "`+receiver.URL+`" }`))
+	assert.NoError(t, err)
+	request.Header.Set("X-Audit-Url", auditReceiver.URL+"/audit")
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	mockRequestHandler.WaitForCallbacks()
+
+	callbacks := received()
+	if assert.Len(t, callbacks, 2) {
+		assert.Equal(t, http.MethodPost, callbacks[1].method)
+		assert.Equal(t, "/payments/p1", callbacks[1].path)
+		assert.Equal(t, "application/json", callbacks[1].header.Get("Content-Type"))
+		assert.Equal(t, "p1", callbacks[1].header.Get("X-Payment-Id"))
+		assert.Equal(t, `{ "id": "p1", "status": "completed", "responseStatus": 202 }`+"\n", callbacks[1].body)
+	}
+	auditCallbacks := auditReceived()
+	if assert.Len(t, auditCallbacks, 1) {
+		assert.Equal(t, http.MethodPut, auditCallbacks[0].method)
+		assert.Equal(t, "/audit", auditCallbacks[0].path)
+	}
+
+	callbackMatches, err := matchstore.GetMatches("createPayment-callback-1")
+	assert.NoError(t, err)
+	if assert.Len(t, callbackMatches, 1) {
+		assert.Equal(t, receiver.URL+"/payments/p1", callbackMatches[0].ActualRequest.URL)
+		assert.Equal(t, http.StatusOK, callbackMatches[0].ActualResponse.StatusCode)
+		if assert.Len(t, callbackMatches[0].CallbackAttempts, 2) {
+			assert.Equal(t, http.StatusServiceUnavailable, callbackMatches[0].CallbackAttempts[0].StatusCode)
+			assert.Equal(t, http.StatusOK, callbackMatches[0].CallbackAttempts[1].StatusCode)
+			assert.Equal(t, "ack", callbackMatches[0].CallbackAttempts[1].Body)
+		}
+	}
+	auditMatches, err := matchstore.GetMatches("paymentAudit")
+	assert.NoError(t, err)
+	assert.Len(t, auditMatches, 1)
+}
+
+func TestCallback_concurrentRequests(t *testing.T) {
+	mockRequestHandler, server, _ := startCallbackMockServer(t)
+	receiver, received := startCallbackReceiver(t)
+	auditReceiver, _ := startCallbackReceiver(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			request, err := http.NewRequest(http.MethodPost, server.URL+"/payments", strings.NewReader(`{ "id": "`+id+`", "callbackUrl": "`+receiver.URL+`" }`))
+			assert.NoError(t, err)
+			request.Header.Set("X-Audit-Url", auditReceiver.URL+"/audit")
+			response, err := http.DefaultClient.Do(request)
+			if assert.NoError(t, err) {
+				response.Body.Close()
+				assert.Equal(t, http.StatusAccepted, response.StatusCode)
+			}
+		}("p" + strconv.Itoa(i))
+	}
+	wg.Wait()
+	mockRequestHandler.WaitForCallbacks()
+
+	paths := map[string]bool{}
+	for _, callback := range received() {
+		id := strings.TrimPrefix(callback.path, "/payments/")
+		assert.Equal(t, `{ "id": "`+id+`", "status": "completed", "responseStatus": 202 }`+"\n", callback.body)
+		paths[callback.path] = true
+	}
+	assert.Len(t, paths, 20)
+	assert.Len(t, received(), 20)
+}
+
+func TestCallback_attemptsExhausted(t *testing.T) {
+	mockRequestHandler, server, matchstore := startCallbackMockServer(t)
+	receiver, received := startCallbackReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+
+	response, err := http.Post(server.URL+"/payments", "application/json", strings.NewReader(`{ "id": "p2", "callbackUrl": "`+receiver.URL+`" }`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	mockRequestHandler.WaitForCallbacks()
+
+	assert.Len(t, received(), 3)
+	callbackMatches, err := matchstore.GetMatches("createPayment-callback-1")
+	assert.NoError(t, err)
+	if assert.Len(t, callbackMatches, 1) {
+		assert.Len(t, callbackMatches[0].CallbackAttempts, 3)
+		assert.Equal(t, http.StatusInternalServerError, callbackMatches[0].ActualResponse.StatusCode)
+	}
+	auditMatches, err := matchstore.GetMatches("paymentAudit")
+	assert.NoError(t, err)
+	if assert.Len(t, auditMatches, 1) {
+		assert.Equal(t, "error parsing callback url ''", auditMatches[0].CallbackAttempts[0].Error)
+	}
+}
+
+func TestCallback_shutdownCancelsPendingCallbacks(t *testing.T) {
+	mockRequestHandler, server, matchstore := startCallbackMockServer(t)
+	receiver, received := startCallbackReceiver(t)
+
+	request, err := http.NewRequest(http.MethodDelete, server.URL+"/payments", nil)
+	assert.NoError(t, err)
+	request.Header.Set("Callback", receiver.URL)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	mockRequestHandler.ShutdownCallbacks()
+
+	assert.Len(t, received(), 0)
+	callbackMatches, err := matchstore.GetMatches("cancelPayment-callback-1")
+	assert.NoError(t, err)
+	if assert.Len(t, callbackMatches, 1) {
+		assert.Empty(t, callbackMatches[0].CallbackAttempts)
+	}
+}
+
+func TestCallback_invalidDelay(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	endpoint := &Endpoint{ID: "invalid", Response: &Response{}, Callbacks: []*Callback{{URL: "http://localhost", Delay: "soon"}}}
+	assert.ErrorContains(t, mockRequestHandler.initResponseTemplates(endpoint, nil, nil), "error parsing delay of callback 'invalid-callback-1' of 'invalid'")
+}
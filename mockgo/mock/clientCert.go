@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+/*
+ClientCert is the tls client certificate of a request, which is available in response templates
+*/
+type ClientCert struct {
+	CommonName       string
+	Subject          string
+	IssuerCommonName string
+	Issuer           string
+	SANs             []string
+	SerialNumber     string
+	Fingerprint      string
+	NotBefore        time.Time
+	NotAfter         time.Time
+}
+
+func subjectAlternativeNames(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func newClientCert(cert *x509.Certificate) *ClientCert {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &ClientCert{
+		CommonName:       cert.Subject.CommonName,
+		Subject:          cert.Subject.String(),
+		IssuerCommonName: cert.Issuer.CommonName,
+		Issuer:           cert.Issuer.String(),
+		SANs:             subjectAlternativeNames(cert),
+		SerialNumber:     cert.SerialNumber.String(),
+		Fingerprint:      hex.EncodeToString(fingerprint[:]),
+		NotBefore:        cert.NotBefore,
+		NotAfter:         cert.NotAfter,
+	}
+}
+
+/*
+requestClientCert returns the client certificate of a tls request or nil, when the client hasn't sent one
+*/
+func requestClientCert(request *http.Request) *ClientCert {
+	if request.TLS == nil || len(request.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return newClientCert(request.TLS.PeerCertificates[0])
+}
+
+/*
+matchClientCert matches the client certificate, the issuer is compared with the common name and the distinguished name of the issuer
+*/
+func matchClientCert(certMatch *ClientCertMatch, request *http.Request) (bool, string) {
+	if certMatch == nil {
+		return true, ""
+	}
+	clientCert := requestClientCert(request)
+	if clientCert == nil {
+		return false, "no client certificate"
+	}
+	if len(certMatch.CommonName) > 0 && certMatch.CommonName != clientCert.CommonName {
+		return false, fmt.Sprintf("wanted commonName '%s'", certMatch.CommonName)
+	}
+	if len(certMatch.Issuer) > 0 && certMatch.Issuer != clientCert.IssuerCommonName && certMatch.Issuer != clientCert.Issuer {
+		return false, fmt.Sprintf("wanted issuer '%s'", certMatch.Issuer)
+	}
+	if len(certMatch.SAN) > 0 {
+		for _, san := range clientCert.SANs {
+			if san == certMatch.SAN {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("wanted san '%s'", certMatch.SAN)
+	}
+	return true, ""
+}
@@ -0,0 +1,139 @@
+package mock
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type clientCertTestSetup struct {
+	server      *httptest.Server
+	roots       *x509.CertPool
+	clientCerts map[string]tls.Certificate
+}
+
+func startClientCertServer(t *testing.T, clientCertRequired bool) *clientCertTestSetup {
+	ca, caKey, err := util.CreateCA("mockgo test ca")
+	assert.NoError(t, err)
+	serverCert, err := util.CreateCertificate(ca, caKey, "server", []string{"127.0.0.1"})
+	assert.NoError(t, err)
+	clientCerts := map[string]tls.Certificate{}
+	for commonName, hosts := range map[string][]string{"partner-a": {"a.partner.example"}, "partner-b": {"b.partner.example"}} {
+		clientCerts[commonName], err = util.CreateCertificate(ca, caKey, commonName, hosts)
+		assert.NoError(t, err)
+	}
+	otherCA, otherCAKey, err := util.CreateCA("other ca")
+	assert.NoError(t, err)
+	clientCerts["untrusted"], err = util.CreateCertificate(otherCA, otherCAKey, "partner-a", nil)
+	assert.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, util.CertificatePEM(ca), 0600))
+	tlsConfig, err := util.CreateTLSConfig(serverCert, caFile, clientCertRequired)
+	assert.NoError(t, err)
+
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "clientcert-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	server := httptest.NewUnstartedServer(router)
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return &clientCertTestSetup{server: server, roots: roots, clientCerts: clientCerts}
+}
+
+func (s *clientCertTestSetup) get(t *testing.T, path, clientCertName string) (int, string, error) {
+	tlsConfig := &tls.Config{RootCAs: s.roots}
+	if len(clientCertName) > 0 {
+		clientCert := s.clientCerts[clientCertName]
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &clientCert, nil
+		}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	response, err := client.Get(s.server.URL + path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	return response.StatusCode, string(body), nil
+}
+
+func TestMockRequestHandler_serving_clientCert(t *testing.T) {
+	setup := startClientCertServer(t, false)
+	for _, test := range []struct {
+		path, clientCertName string
+		expectedStatus       int
+		expectedBody         string
+	}{
+		{"/partner/orders", "partner-a", http.StatusOK, "orders of partner-a issued by mockgo test ca"},
+		{"/partner/orders", "partner-b", http.StatusOK, "orders of b.partner.example"},
+		{"/partner/orders", "", http.StatusNotFound, ""},
+		{"/partner/whoami", "partner-b", http.StatusOK, "partner-b"},
+		{"/partner/whoami", "", http.StatusOK, "anonymous"},
+	} {
+		status, body, err := setup.get(t, test.path, test.clientCertName)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedStatus, status, "%s with client cert '%s'", test.path, test.clientCertName)
+		if test.expectedStatus == http.StatusOK {
+			assert.Equal(t, test.expectedBody, body)
+		}
+	}
+
+	_, _, err := setup.get(t, "/partner/orders", "untrusted")
+	assert.Error(t, err, "client certificate of an unknown ca must be rejected")
+}
+
+func TestMockRequestHandler_serving_clientCertRequired(t *testing.T) {
+	setup := startClientCertServer(t, true)
+	_, _, err := setup.get(t, "/partner/whoami", "")
+	assert.Error(t, err)
+	status, body, err := setup.get(t, "/partner/whoami", "partner-a")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "partner-a", body)
+}
+
+func TestMatchClientCert(t *testing.T) {
+	ca, caKey, err := util.CreateCA("issuer ca")
+	assert.NoError(t, err)
+	clientCert, err := util.CreateCertificate(ca, caKey, "client", []string{"client.example", "10.0.0.1"})
+	assert.NoError(t, err)
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert.Leaf}}
+
+	assert.Equal(t, []string{"client.example", "10.0.0.1"}, requestClientCert(request).SANs)
+	for certMatch, expected := range map[ClientCertMatch]bool{
+		{}:                                 true,
+		{CommonName: "client"}:             true,
+		{CommonName: "other"}:              false,
+		{SAN: "10.0.0.1"}:                  true,
+		{SAN: "other.example"}:             false,
+		{Issuer: "issuer ca"}:              true,
+		{Issuer: "CN=issuer ca"}:           true,
+		{Issuer: "other ca"}:               false,
+		{CommonName: "client", SAN: "x.y"}: false,
+	} {
+		certMatch := certMatch
+		matched, _ := matchClientCert(&certMatch, request)
+		assert.Equal(t, expected, matched, "%+v", certMatch)
+	}
+	matched, details := matchClientCert(&ClientCertMatch{}, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, matched)
+	assert.Equal(t, "no client certificate", details)
+}
@@ -0,0 +1,158 @@
+package mock
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const yamlDocumentSeparator = "---"
+
+var reservedTemplateNames = map[string]bool{
+	templateResponseBody:          true,
+	templateResponseStatus:        true,
+	templateResponseHeader:        true,
+	templateResponseDefaultHeader: true,
+	templateResponseTrailer:       true,
+	templateGrpcResponseMessage:   true,
+	templateGrpcResponseMetadata:  true,
+}
+
+func (r *RequestHandler) readIncludes(mock *Mock, includingFile *mockFile, includedBy []string) error {
+	includedBy = append(includedBy, includingFile.path)
+	for _, include := range mock.Include {
+		includePath := path.Join(path.Dir(includingFile.path), include)
+		for _, includingPath := range includedBy {
+			if includingPath == includePath {
+				return fmt.Errorf("cyclic include of '%s' in mockfile '%s'", includePath, strings.Join(includedBy, "' -> '"))
+			}
+		}
+		includedFile := &mockFile{path: includePath, mockDir: includingFile.mockDir, source: includingFile.source, fsys: includingFile.fsys}
+		includedMock, err := r.readMockFileIncludedBy(includedFile, includedBy)
+		if err != nil {
+			return err
+		}
+		mergeIncludedMock(mock, includedMock)
+		mock.includedFiles = append(append(mock.includedFiles, includedFile.name()), includedMock.includedFiles...)
+	}
+	return nil
+}
+
+/*
+withoutIncludedMocks removes the mocks of mockfiles which are included by another mockfile, so that their endpoints are registered only once
+*/
+func (r *RequestHandler) withoutIncludedMocks(mocks []*Mock, mockFileNames []string) []*Mock {
+	included := map[string]bool{}
+	for _, mock := range mocks {
+		for _, includedFile := range mock.includedFiles {
+			included[includedFile] = true
+		}
+	}
+	var result []*Mock
+	for i, mock := range mocks {
+		if included[mockFileNames[i]] {
+			r.logger.Info(fmt.Sprintf("Skipping mock file '%s', it is included by another mock file", mockFileNames[i]))
+			continue
+		}
+		result = append(result, mock)
+	}
+	return result
+}
+
+func mergeIncludedMock(mock, includedMock *Mock) {
+	mock.Endpoints = append(includedMock.Endpoints, mock.Endpoints...)
+	mock.Grpc = append(includedMock.Grpc, mock.Grpc...)
+	mock.Sockets = append(includedMock.Sockets, mock.Sockets...)
+	mock.Jobs = append(includedMock.Jobs, mock.Jobs...)
+	mock.Mismatches = append(includedMock.Mismatches, mock.Mismatches...)
+	templates := map[string]string{}
+	for name, text := range includedMock.Templates {
+		templates[name] = text
+	}
+	for name, text := range mock.Templates {
+		templates[name] = text
+	}
+	mock.Templates = templates
+	mock.Defaults = mergeDefaults(includedMock.Defaults, mock.Defaults)
+}
+
+func mergeDefaults(base, override *Defaults) *Defaults {
+	if base == nil {
+		return override
+	}
+	if override == nil {
+		return base
+	}
+	merged := &Defaults{Prio: base.Prio, Request: &RequestDefaults{Headers: map[string]string{}}, Response: &ResponseDefaults{}}
+	if override.Prio != 0 {
+		merged.Prio = override.Prio
+	}
+	for _, requestDefaults := range []*RequestDefaults{base.Request, override.Request} {
+		if requestDefaults == nil {
+			continue
+		}
+		if len(requestDefaults.Host) > 0 {
+			merged.Request.Host = requestDefaults.Host
+		}
+		for key, val := range requestDefaults.Headers {
+			merged.Request.Headers[key] = val
+		}
+	}
+	var responseHeaders []string
+	for _, responseDefaults := range []*ResponseDefaults{base.Response, override.Response} {
+		if responseDefaults != nil && len(responseDefaults.Headers) > 0 {
+			responseHeaders = append(responseHeaders, responseDefaults.Headers)
+		}
+	}
+	// the headers are rendered as separate yaml documents, the headers of a later document take precedence
+	merged.Response.Headers = strings.Join(responseHeaders, "\n"+yamlDocumentSeparator+"\n")
+	return merged
+}
+
+func applyDefaults(mock *Mock) {
+	defaults := mock.Defaults
+	if defaults == nil {
+		return
+	}
+	for _, endpoint := range mock.Endpoints {
+		if endpoint.Prio == 0 {
+			endpoint.Prio = defaults.Prio
+		}
+		if defaults.Request != nil && endpoint.Request != nil {
+			if len(endpoint.Request.Host) == 0 {
+				endpoint.Request.Host = defaults.Request.Host
+			}
+			if len(defaults.Request.Headers) > 0 {
+				headers := map[string]string{}
+				for key, val := range defaults.Request.Headers {
+					headers[key] = val
+				}
+				for key, val := range endpoint.Request.Headers {
+					headers[key] = val
+				}
+				endpoint.Request.Headers = headers
+			}
+		}
+		if defaults.Response != nil && endpoint.Response != nil {
+			endpoint.Response.DefaultHeaders = defaults.Response.Headers
+		}
+	}
+}
+
+func collectTemplates(mocks []*Mock) (map[string]string, error) {
+	templates := map[string]string{}
+	templateMockNames := map[string]string{}
+	for _, mock := range mocks {
+		for name, text := range mock.Templates {
+			if reservedTemplateNames[name] {
+				return nil, fmt.Errorf("template name '%s' in mockfile '%s' is reserved", name, mock.Name)
+			}
+			if definedText, exists := templates[name]; exists && definedText != text {
+				return nil, fmt.Errorf("template '%s' is defined differently in mockfiles '%s' and '%s'", name, templateMockNames[name], mock.Name)
+			}
+			templates[name] = text
+			templateMockNames[name] = mock.Name
+		}
+	}
+	return templates, nil
+}
@@ -0,0 +1,111 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveIncludesRequest(t *testing.T, mockRequestHandler *RequestHandler, url string, header map[string]string) (*Endpoint, *http.Response, string) {
+	request := httptest.NewRequest(http.MethodGet, url, nil)
+	for key, val := range header {
+		request.Header.Set(key, val)
+	}
+	endpoint, match, _, pathParams, queryParams := mockRequestHandler.matchRequestToEndpoint(request)
+	if endpoint == nil {
+		return nil, nil, ""
+	}
+	recorder := httptest.NewRecorder()
+	mockRequestHandler.renderResponse(recorder, request, endpoint, match, pathParams, queryParams)
+	return endpoint, recorder.Result(), recorder.Body.String()
+}
+
+func TestMockRequestHandler_LoadFiles_includesAndDefaults(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mockfileIncludes", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+
+	endpoint, response, body := serveIncludesRequest(t, mockRequestHandler, "http://orders.example.com/orders/42", map[string]string{"X-Tenant": "acme"})
+	if assert.NotNil(t, endpoint) {
+		assert.Equal(t, "orderNotFound", endpoint.ID)
+		assert.Equal(t, 5, endpoint.Prio)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		assert.Equal(t, "orders", response.Header.Get("X-Mock"))
+		assert.Equal(t, "true", response.Header.Get("X-Shared"), "response headers of the included defaults must be merged")
+		assert.Equal(t, `{ "error": "/orders/42", "status": 404 }`, body)
+	}
+
+	endpoint, _, _ = serveIncludesRequest(t, mockRequestHandler, "http://orders.example.com/orders/42", nil)
+	assert.Nil(t, endpoint, "default request header must be matched")
+
+	endpoint, response, body = serveIncludesRequest(t, mockRequestHandler, "http://text.example.com/orders/42/text", map[string]string{"X-Tenant": "other"})
+	if assert.NotNil(t, endpoint) {
+		assert.Equal(t, "orderText", endpoint.ID)
+		assert.Equal(t, 1, endpoint.Prio)
+		assert.Equal(t, "text/plain", response.Header.Get("Content-Type"))
+		assert.Equal(t, "orders", response.Header.Get("X-Mock"))
+		assert.Equal(t, "true", response.Header.Get("X-Shared"))
+		assert.Equal(t, "order 42", body)
+	}
+
+	endpoint, response, _ = serveIncludesRequest(t, mockRequestHandler, "http://orders.example.com/health", map[string]string{"X-Tenant": "acme"})
+	if assert.NotNil(t, endpoint) {
+		assert.Equal(t, "sharedHealth", endpoint.ID)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	}
+
+	endpoint, _, body = serveIncludesRequest(t, mockRequestHandler, "http://customers.example.com/customers/7", nil)
+	if assert.NotNil(t, endpoint) {
+		assert.Equal(t, "customerNotFound", endpoint.ID)
+		assert.Equal(t, `{ "error": "/customers/7", "status": 404 }`, body)
+	}
+
+	mismatchResponse := mockRequestHandler.findMismatchResponse(httptest.NewRequest(http.MethodGet, "http://orders.example.com/orders", nil))
+	if assert.NotNil(t, mismatchResponse, "mismatch response of included file must be registered") {
+		assert.Equal(t, "404", mismatchResponse.StatusCode)
+		assert.Equal(t, "orders-mock.yaml", mismatchResponse.Mock.Name)
+	}
+}
+
+func TestMockRequestHandler_LoadFiles_includedMockfileMatchesPattern(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mockfileIncludes", "*.yaml", true,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assert.ElementsMatch(t, []string{"customerNotFound", "sharedHealth", "orderNotFound", "orderText"}, mockRequestHandler.EndpointIDs())
+}
+
+func TestMergeIncludedMock(t *testing.T) {
+	mock := &Mock{Endpoints: []*Endpoint{{ID: "own"}}, Grpc: []*GrpcEndpoint{{ID: "ownGrpc"}}}
+	mergeIncludedMock(mock, &Mock{
+		Endpoints:  []*Endpoint{{ID: "included"}},
+		Grpc:       []*GrpcEndpoint{{ID: "includedGrpc"}},
+		Sockets:    []*SocketEndpoint{{ID: "includedSocket"}},
+		Jobs:       []*Job{{ID: "includedJob"}},
+		Mismatches: []*MismatchResponse{{PathPrefix: "/included"}},
+	})
+	assert.Equal(t, []*Endpoint{{ID: "included"}, {ID: "own"}}, mock.Endpoints)
+	assert.Equal(t, []*GrpcEndpoint{{ID: "includedGrpc"}, {ID: "ownGrpc"}}, mock.Grpc)
+	assert.Equal(t, []*SocketEndpoint{{ID: "includedSocket"}}, mock.Sockets)
+	assert.Equal(t, []*Job{{ID: "includedJob"}}, mock.Jobs)
+	assert.Equal(t, []*MismatchResponse{{PathPrefix: "/included"}}, mock.Mismatches)
+}
+
+func TestMockRequestHandler_LoadFiles_cyclicInclude(t *testing.T) {
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/cyclicInclude", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.ErrorContains(t, mockRequestHandlerWithError.LoadFiles(), "cyclic include of 'wrong-mock.yaml'")
+}
+
+func TestCollectTemplates_errors(t *testing.T) {
+	_, err := collectTemplates([]*Mock{{Name: "reserved", Templates: map[string]string{templateResponseBody: "body"}}})
+	assert.ErrorContains(t, err, "template name 'responseBody' in mockfile 'reserved' is reserved")
+	_, err = collectTemplates([]*Mock{
+		{Name: "first", Templates: map[string]string{"errorBody": "first"}},
+		{Name: "second", Templates: map[string]string{"errorBody": "second"}},
+	})
+	assert.ErrorContains(t, err, "template 'errorBody' is defined differently in mockfiles 'first' and 'second'")
+}
@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var envVarPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+/*
+expandEnvVars replaces ${NAME} and ${NAME:-default} with the value of the environment variable NAME, the default is used when NAME is not set or empty.
+'$${' escapes the expansion, variables which are not defined and have no default are not replaced.
+*/
+func expandEnvVars(content []byte, lookupEnv func(string) (string, bool)) ([]byte, []string) {
+	var undefined []string
+	expanded := envVarPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		if strings.HasPrefix(string(match), "$$") {
+			return match[1:]
+		}
+		submatches := envVarPattern.FindSubmatch(match)
+		val, ok := lookupEnv(string(submatches[1]))
+		if ok && (len(val) > 0 || len(submatches[2]) == 0) {
+			return []byte(val)
+		}
+		if len(submatches[2]) > 0 {
+			return submatches[3]
+		}
+		undefined = append(undefined, string(submatches[1]))
+		return match
+	})
+	return expanded, undefined
+}
+
+/*
+isEnvAllowed returns true when the name of the environment variable matches a pattern of envAllowlist
+*/
+func isEnvAllowed(envAllowlist []string, name string) bool {
+	for _, pattern := range envAllowlist {
+		if matched, err := path.Match(strings.TrimSpace(pattern), name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+lookupAllowedEnv looks up the environment variables which match a pattern of envAllowlist, other variables are not set
+*/
+func lookupAllowedEnv(envAllowlist []string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		if !isEnvAllowed(envAllowlist, name) {
+			return "", false
+		}
+		return os.LookupEnv(name)
+	}
+}
+
+/*
+NewEnvTemplateFuncMap creates a template.FuncMap for reading environment variables and files, e.g. mounted secrets.
+Only environment variables whose name matches a pattern of envAllowlist and files in a directory of fileAllowlist can be read.
+*/
+func NewEnvTemplateFuncMap(envAllowlist, fileAllowlist []string) template.FuncMap {
+	return template.FuncMap{
+		"env": func(name string) (string, error) {
+			if !isEnvAllowed(envAllowlist, name) {
+				return "", fmt.Errorf("environment variable '%s' is not allowed", name)
+			}
+			return os.Getenv(name), nil
+		},
+		"expandenv": func(text string) string {
+			return os.Expand(text, func(name string) string {
+				if !isEnvAllowed(envAllowlist, name) {
+					return ""
+				}
+				return os.Getenv(name)
+			})
+		},
+		"file": func(filename string) (string, error) {
+			absFilename, err := filepath.Abs(filename)
+			if err != nil {
+				return "", err
+			}
+			for _, dir := range fileAllowlist {
+				absDir, err := filepath.Abs(strings.TrimSpace(dir))
+				if err != nil || len(strings.TrimSpace(dir)) == 0 {
+					continue
+				}
+				if strings.HasPrefix(absFilename, absDir+string(filepath.Separator)) {
+					content, err := os.ReadFile(absFilename)
+					if err != nil {
+						return "", err
+					}
+					return string(content), nil
+				}
+			}
+			return "", fmt.Errorf("file '%s' is not allowed", filename)
+		},
+	}
+}
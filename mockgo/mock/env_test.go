@@ -0,0 +1,132 @@
+package mock
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandEnvVars(t *testing.T) {
+	lookupEnv := func(name string) (string, bool) {
+		val, ok := map[string]string{"HOST": "example.com", "EMPTY": ""}[name]
+		return val, ok
+	}
+	testCases := []struct {
+		content           string
+		expected          string
+		expectedUndefined []string
+	}{
+		{content: "host: ${HOST}", expected: "host: example.com"},
+		{content: "host: ${HOST:-localhost}", expected: "host: example.com"},
+		{content: "host: ${EMPTY:-localhost}", expected: "host: localhost"},
+		{content: "host: ${EMPTY}", expected: "host: "},
+		{content: "host: ${UNDEFINED:-localhost}", expected: "host: localhost"},
+		{content: "host: ${UNDEFINED:-}", expected: "host: "},
+		{content: "host: ${UNDEFINED}", expected: "host: ${UNDEFINED}", expectedUndefined: []string{"UNDEFINED"}},
+		{content: "host: $${HOST}", expected: "host: ${HOST}"},
+		{content: "{{ $payload := .RequestBodyJSONData }}", expected: "{{ $payload := .RequestBodyJSONData }}"},
+	}
+	for _, testCase := range testCases {
+		expanded, undefined := expandEnvVars([]byte(testCase.content), lookupEnv)
+		assert.Equal(t, testCase.expected, string(expanded))
+		assert.Equal(t, testCase.expectedUndefined, undefined)
+	}
+}
+
+func executeEnvTemplate(funcMap template.FuncMap, text string) (string, error) {
+	tmpl, err := template.New("test").Funcs(funcMap).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, nil)
+	return rendered.String(), err
+}
+
+func TestNewEnvTemplateFuncMap(t *testing.T) {
+	t.Setenv("MOCKGO_TEST_TOKEN", "secrettoken")
+	t.Setenv("OTHER_TOKEN", "othertoken")
+	secretDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "password"), []byte("secretpassword"), 0600))
+
+	funcMap := NewEnvTemplateFuncMap([]string{"MOCKGO_*"}, []string{secretDir})
+	rendered, err := executeEnvTemplate(funcMap, `{{ env "MOCKGO_TEST_TOKEN" }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "secrettoken", rendered)
+	_, err = executeEnvTemplate(funcMap, `{{ env "OTHER_TOKEN" }}`)
+	assert.ErrorContains(t, err, "environment variable 'OTHER_TOKEN' is not allowed")
+	rendered, err = executeEnvTemplate(funcMap, `{{ expandenv "$MOCKGO_TEST_TOKEN-$OTHER_TOKEN" }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "secrettoken-", rendered)
+
+	rendered, err = executeEnvTemplate(funcMap, `{{ file "`+filepath.Join(secretDir, "password")+`" }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "secretpassword", rendered)
+	_, err = executeEnvTemplate(funcMap, `{{ file "`+filepath.Join(secretDir, "..", "password")+`" }}`)
+	assert.ErrorContains(t, err, "is not allowed")
+}
+
+func TestMockRequestHandler_LoadFiles_envVars(t *testing.T) {
+	t.Setenv("MOCKGO_TEST_HOST", "env.example.com")
+	t.Setenv("MOCKGO_TEST_TOKEN", "secrettoken")
+	mockDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(mockDir, "env-mock.yaml"), []byte(`endpoints:
+  - id: "env"
+    request:
+      host: "${MOCKGO_TEST_HOST}"
+      path: "${MOCKGO_TEST_PATH:-/env}"
+    response:
+      body: '{{ env "MOCKGO_TEST_TOKEN" }}'
+`), 0644))
+
+	mockRequestHandler := NewRequestHandler("/__", mockDir, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	mockRequestHandler.SetTemplateAllowlists([]string{"MOCKGO_TEST_TOKEN", "MOCKGO_TEST_HOST"}, nil)
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+
+	request := httptest.NewRequest(http.MethodGet, "http://env.example.com/env", nil)
+	endpoint, match, _, pathParams, queryParams := mockRequestHandler.matchRequestToEndpoint(request)
+	if assert.NotNil(t, endpoint) {
+		recorder := httptest.NewRecorder()
+		mockRequestHandler.renderResponse(recorder, request, endpoint, match, pathParams, queryParams)
+		assert.Equal(t, "secrettoken", recorder.Body.String())
+	}
+}
+
+func TestMockRequestHandler_LoadFiles_envVarsNotAllowed(t *testing.T) {
+	t.Setenv("MOCKGO_TEST_HOST", "env.example.com")
+	t.Setenv("OTHER_SECRET", "secretpassword")
+	mockDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(mockDir, "env-mock.yaml"), []byte(`endpoints:
+  - id: "env"
+    request:
+      path: "/env"
+    response:
+      body: '${MOCKGO_TEST_HOST} ${OTHER_SECRET} ${OTHER_SECRET:-none}'
+`), 0644))
+
+	mockRequestHandler := NewRequestHandler("/__", mockDir, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	mockRequestHandler.SetTemplateAllowlists([]string{"MOCKGO_*"}, nil)
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	if assert.Len(t, mockRequestHandler.endpoints, 1) {
+		assert.Equal(t, "env.example.com ${OTHER_SECRET} none", mockRequestHandler.endpoints[0].Response.Body)
+	}
+}
+
+func TestMockRequestHandler_envNotAllowedByDefault(t *testing.T) {
+	t.Setenv("MOCKGO_TEST_TOKEN", "secrettoken")
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	endpoint := &Endpoint{ID: "env", Response: &Response{Body: `{{ env "MOCKGO_TEST_TOKEN" }}`}}
+	assert.NoError(t, mockRequestHandler.initResponseTemplates(endpoint, nil, nil))
+	request := httptest.NewRequest(http.MethodGet, "/env", nil)
+	recorder := httptest.NewRecorder()
+	mockRequestHandler.renderResponse(recorder, request, endpoint, &matches.Match{}, nil, nil)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "environment variable 'MOCKGO_TEST_TOKEN' is not allowed")
+}
@@ -0,0 +1,241 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+/*
+GraphQLOperation is the parsed operation of a graphql request, which is available in response templates
+*/
+type GraphQLOperation struct {
+	Name   string
+	Type   string
+	Query  string
+	Fields []string
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+/*
+readRequestBody reads the body of the request and replaces it, so that it can be read again
+*/
+func readRequestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+/*
+parseGraphQLRequest reads a graphql request from the json body of a POST request or from the query parameters of a GET request
+*/
+func parseGraphQLRequest(request *http.Request) (*graphQLRequest, error) {
+	gqlRequest := &graphQLRequest{}
+	if request.Method == http.MethodGet {
+		gqlRequest.Query = request.URL.Query().Get("query")
+		gqlRequest.OperationName = request.URL.Query().Get("operationName")
+		if variables := request.URL.Query().Get("variables"); len(variables) > 0 {
+			if err := json.Unmarshal([]byte(variables), &gqlRequest.Variables); err != nil {
+				return nil, fmt.Errorf("error parsing graphql variables: %v", err)
+			}
+		}
+	} else {
+		body, err := readRequestBody(request)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, gqlRequest); err != nil {
+			return nil, fmt.Errorf("error parsing graphql request: %v", err)
+		}
+	}
+	if len(gqlRequest.Query) == 0 {
+		return nil, fmt.Errorf("no graphql query in request")
+	}
+	return gqlRequest, nil
+}
+
+func normalizeGraphQLQuery(document *ast.QueryDocument) string {
+	var normalized bytes.Buffer
+	formatter.NewFormatter(&normalized).FormatQueryDocument(document)
+	return normalized.String()
+}
+
+/*
+parseGraphQLOperation parses the query of a graphql request, the query is validated when a schema is given
+*/
+func parseGraphQLOperation(gqlRequest *graphQLRequest, schema *ast.Schema) (*GraphQLOperation, error) {
+	var document *ast.QueryDocument
+	if schema != nil {
+		var errs error
+		document, errs = gqlparser.LoadQuery(schema, gqlRequest.Query)
+		if document == nil {
+			return nil, fmt.Errorf("invalid graphql query: %v", errs)
+		}
+	} else {
+		var err error
+		document, err = parser.ParseQuery(&ast.Source{Input: gqlRequest.Query})
+		if err != nil {
+			return nil, fmt.Errorf("invalid graphql query: %v", err)
+		}
+	}
+	operation := document.Operations.ForName(gqlRequest.OperationName)
+	if operation == nil {
+		return nil, fmt.Errorf("graphql operation '%s' not found", gqlRequest.OperationName)
+	}
+	gqlOperation := &GraphQLOperation{Name: operation.Name, Type: string(operation.Operation), Query: normalizeGraphQLQuery(document)}
+	for _, selection := range operation.SelectionSet {
+		if field, ok := selection.(*ast.Field); ok {
+			gqlOperation.Fields = append(gqlOperation.Fields, field.Name)
+		}
+	}
+	return gqlOperation, nil
+}
+
+/*
+normalizeYAMLValue converts maps of a yaml document to map[string]interface{}, so that they can be compared with json values
+*/
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := map[string]interface{}{}
+		for key, val := range typedValue {
+			normalized[fmt.Sprint(key)] = normalizeYAMLValue(val)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := map[string]interface{}{}
+		for key, val := range typedValue {
+			normalized[key] = normalizeYAMLValue(val)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(typedValue))
+		for i, val := range typedValue {
+			normalized[i] = normalizeYAMLValue(val)
+		}
+		return normalized
+	}
+	return value
+}
+
+/*
+initGraphQLMatch normalizes the query and variables of a graphql matcher and loads its schema file
+*/
+func initGraphQLMatch(gqlMatch *GraphQLMatch, fsys fs.FS) error {
+	if len(gqlMatch.Query) > 0 {
+		document, err := parser.ParseQuery(&ast.Source{Input: gqlMatch.Query})
+		if err != nil {
+			return fmt.Errorf("error parsing graphql query: %v", err)
+		}
+		gqlMatch.NormalizedQuery = normalizeGraphQLQuery(document)
+	}
+	if len(gqlMatch.Variables) > 0 {
+		variablesJSON, err := json.Marshal(normalizeYAMLValue(gqlMatch.Variables))
+		if err != nil {
+			return fmt.Errorf("error parsing graphql variables: %v", err)
+		}
+		gqlMatch.Variables = nil
+		if err := json.Unmarshal(variablesJSON, &gqlMatch.Variables); err != nil {
+			return fmt.Errorf("error parsing graphql variables: %v", err)
+		}
+	}
+	if len(gqlMatch.SchemaFile) > 0 {
+		schemaContent, err := fs.ReadFile(fsys, gqlMatch.SchemaFile)
+		if err != nil {
+			return err
+		}
+		schema, err := gqlparser.LoadSchema(&ast.Source{Name: gqlMatch.SchemaFile, Input: string(schemaContent)})
+		if err != nil {
+			return fmt.Errorf("error parsing graphql schema '%s': %v", gqlMatch.SchemaFile, err)
+		}
+		gqlMatch.Schema = schema
+	}
+	return nil
+}
+
+func matchGraphQLOperation(gqlMatch *GraphQLMatch, gqlRequest *graphQLRequest) (bool, string) {
+	gqlOperation, err := parseGraphQLOperation(gqlRequest, gqlMatch.Schema)
+	if err != nil {
+		return false, err.Error()
+	}
+	if len(gqlMatch.OperationName) > 0 && gqlMatch.OperationName != gqlOperation.Name {
+		return false, fmt.Sprintf("wanted operationName '%s'", gqlMatch.OperationName)
+	}
+	if len(gqlMatch.OperationType) > 0 && !strings.EqualFold(gqlMatch.OperationType, gqlOperation.Type) {
+		return false, fmt.Sprintf("wanted operationType '%s'", gqlMatch.OperationType)
+	}
+	if len(gqlMatch.NormalizedQuery) > 0 && gqlMatch.NormalizedQuery != gqlOperation.Query {
+		return false, "wanted query"
+	}
+	for key, val := range gqlMatch.Variables {
+		if !matchGraphQLVariable(val, gqlRequest.Variables[key]) {
+			return false, fmt.Sprintf("wanted variable '%s': %v", key, val)
+		}
+	}
+	return true, ""
+}
+
+/*
+matchGraphQLVariable compares a wanted with an actual variable value, a wanted object must be a subset of the actual object
+and a wanted array must have the length of the actual array with matching elements
+*/
+func matchGraphQLVariable(wanted, actual interface{}) bool {
+	switch wantedValue := wanted.(type) {
+	case map[string]interface{}:
+		actualObject, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, val := range wantedValue {
+			if !matchGraphQLVariable(val, actualObject[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualArray, ok := actual.([]interface{})
+		if !ok || len(actualArray) != len(wantedValue) {
+			return false
+		}
+		for i, val := range wantedValue {
+			if !matchGraphQLVariable(val, actualArray[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(wanted, actual)
+	}
+}
+
+func (r *RequestHandler) matchGraphQL(matchRequest *MatchRequest, request *http.Request) (bool, string) {
+	if matchRequest.GraphQL == nil {
+		return true, ""
+	}
+	gqlRequest, err := parseGraphQLRequest(request)
+	if err != nil {
+		return false, err.Error()
+	}
+	return matchGraphQLOperation(matchRequest.GraphQL, gqlRequest)
+}
@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockRequestHandler_serving_graphqlOperation(t *testing.T) {
+	query := `query GetUser($id: ID!) { user(id: $id) { id name } }`
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodPost, "/graphql", testutil.CreateHeader().WithJSONContentType(),
+			`{ "query": "`+query+`", "operationName": "GetUser", "variables": { "id": 42, "filter": { "active": true }, "other": "ignored" } }`),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "graphqlGetUser", response.Header.Get(headerKeyEndpointID))
+			assert.Equal(t, `{ "data": { "user": { "id": "42", "type": "query", "fields": "user" } } }`, responseBody)
+		}))
+
+	assert.NoError(t, testutil.AssertResponseStatusOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodPost, "/graphql", testutil.CreateHeader().WithJSONContentType(),
+			`{ "query": "`+query+`", "operationName": "GetUser", "variables": { "id": 43, "filter": { "active": true } } }`),
+		http.StatusNotFound))
+}
+
+func TestMockRequestHandler_serving_graphqlSchemaValidation(t *testing.T) {
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodPost, "/graphql", testutil.CreateHeader().WithJSONContentType(),
+			`{ "query": "mutation Create($name: String!) { createUser(name: $name) { id } }", "variables": { "name": "alex" } }`),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, `{ "data": { "createUser": { "name": "alex" } } }`, responseBody)
+		}))
+
+	assert.NoError(t, testutil.AssertResponseStatusOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodPost, "/graphql", testutil.CreateHeader().WithJSONContentType(),
+			`{ "query": "mutation { createUser(name: \"alex\") { unknownField } }" }`),
+		http.StatusNotFound))
+}
+
+func TestMockRequestHandler_serving_graphqlNormalizedQuery(t *testing.T) {
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/graphql?query="+url.QueryEscape("{health}"), testutil.CreateHeader(), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, `{ "data": { "health": "OK" } }`, responseBody)
+		}))
+}
+
+func TestMatchGraphQLOperation_nestedVariables(t *testing.T) {
+	gqlMatch := &GraphQLMatch{Variables: map[string]interface{}{
+		"filter": map[string]interface{}{"active": true, "tags": []interface{}{map[string]interface{}{"name": "vip"}}},
+	}}
+	matched, mismatch := matchGraphQLOperation(gqlMatch, &graphQLRequest{Query: "{ users { id } }", Variables: map[string]interface{}{
+		"filter": map[string]interface{}{"active": true, "role": "admin", "tags": []interface{}{map[string]interface{}{"name": "vip", "since": 2020.0}}},
+	}})
+	assert.True(t, matched, mismatch)
+	matched, _ = matchGraphQLOperation(gqlMatch, &graphQLRequest{Query: "{ users { id } }", Variables: map[string]interface{}{
+		"filter": map[string]interface{}{"active": false, "role": "admin", "tags": []interface{}{map[string]interface{}{"name": "vip"}}},
+	}})
+	assert.False(t, matched)
+	matched, _ = matchGraphQLOperation(gqlMatch, &graphQLRequest{Query: "{ users { id } }", Variables: map[string]interface{}{
+		"filter": map[string]interface{}{"active": true, "tags": []interface{}{map[string]interface{}{"name": "vip"}, map[string]interface{}{"name": "new"}}},
+	}})
+	assert.False(t, matched, "arrays must have the same length")
+}
+
+func TestInitGraphQLMatch_errors(t *testing.T) {
+	assert.ErrorContains(t, initGraphQLMatch(&GraphQLMatch{Query: "query {"}, localFS("../../test/mocks")), "error parsing graphql query")
+	assert.ErrorContains(t, initGraphQLMatch(&GraphQLMatch{SchemaFile: "notexists.graphql"}, localFS("../../test/mocks")), "no such file or directory")
+	assert.ErrorContains(t, initGraphQLMatch(&GraphQLMatch{SchemaFile: "graphql-mock.yaml"}, localFS("../../test/mocks")), "error parsing graphql schema 'graphql-mock.yaml'")
+}
+
+func TestParseGraphQLOperation(t *testing.T) {
+	_, err := parseGraphQLOperation(&graphQLRequest{Query: "query A { a } query B { b }"}, nil)
+	assert.ErrorContains(t, err, "graphql operation '' not found")
+	operation, err := parseGraphQLOperation(&graphQLRequest{Query: "query A { a } query B { b c }", OperationName: "B"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &GraphQLOperation{Name: "B", Type: "query", Query: operation.Query, Fields: []string{"b", "c"}}, operation)
+}
@@ -0,0 +1,448 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	sprig "github.com/Masterminds/sprig/v3"
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/bufbuild/protocompile"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/yaml.v2"
+)
+
+const templateGrpcResponseMessage = "grpcResponseMessage"
+const templateGrpcResponseMetadata = "grpcResponseMetadata"
+const templateGrpcResponseStream = "grpcResponseStream"
+
+/*
+GrpcHandler implements a grpc server for the grpc endpoints of the mockfiles, the grpc methods are defined in proto files or descriptor sets
+*/
+type GrpcHandler struct {
+	requestHandler   *RequestHandler
+	protoFilepattern string
+	logger           *zap.Logger
+	server           *grpc.Server
+	lock             sync.RWMutex
+	methods          map[string]protoreflect.MethodDescriptor
+	endpoints        map[string][]*GrpcEndpoint
+}
+
+/*
+NewGrpcHandler creates an instance of GrpcHandler, the grpc endpoints are loaded with RequestHandler.LoadFiles
+*/
+func NewGrpcHandler(requestHandler *RequestHandler, protoFilepattern string) *GrpcHandler {
+	grpcHandler := &GrpcHandler{
+		requestHandler:   requestHandler,
+		protoFilepattern: protoFilepattern,
+		logger:           requestHandler.logger,
+		methods:          map[string]protoreflect.MethodDescriptor{},
+		endpoints:        map[string][]*GrpcEndpoint{},
+	}
+	grpcHandler.server = grpc.NewServer(grpc.UnknownServiceHandler(grpcHandler.handleCall))
+	requestHandler.grpcHandler = grpcHandler
+	return grpcHandler
+}
+
+/*
+Serve serves grpc calls on the listener
+*/
+func (g *GrpcHandler) Serve(listener net.Listener) error {
+	g.logger.Info(fmt.Sprintf("serving grpc mock endpoints at %v", listener.Addr()))
+	return g.server.Serve(listener)
+}
+
+/*
+Shutdown stops the grpc server
+*/
+func (g *GrpcHandler) Shutdown() {
+	g.server.GracefulStop()
+}
+
+func grpcMethodName(method string) string {
+	return strings.Replace(strings.TrimPrefix(method, "/"), "/", ".", 1)
+}
+
+func (g *GrpcHandler) load(mockDirs []*MockDir, mocks []*Mock, templates map[string]string) error {
+	methods := map[string]protoreflect.MethodDescriptor{}
+	for _, mockDir := range mockDirs {
+		if err := g.loadProtoFiles(mockDir, methods); err != nil {
+			return err
+		}
+	}
+	endpoints := map[string][]*GrpcEndpoint{}
+	endpointCounter := 0
+	for _, mock := range mocks {
+		for _, endpoint := range mock.Grpc {
+			endpointCounter++
+			if len(endpoint.ID) == 0 {
+				endpoint.ID = "grpc" + strconv.Itoa(endpointCounter)
+			}
+			methodName := grpcMethodName(endpoint.Method)
+			method := methods[methodName]
+			if method == nil {
+				g.logger.Error(fmt.Sprintf("Grpc method '%s' of endpoint id '%s' is not defined in a proto file, skipping endpoint", endpoint.Method, endpoint.ID))
+				continue
+			}
+			if err := g.initGrpcResponse(endpoint, method, templates); err != nil {
+				g.logger.Error(fmt.Sprintf("Can't initialize response of grpc endpoint id '%s', skipping endpoint ", endpoint.ID), zap.Error(err))
+				continue
+			}
+			endpoints[methodName] = append(endpoints[methodName], endpoint)
+			g.logger.Info(fmt.Sprintf("register grpc endpoint with id '%s' for method: %s", endpoint.ID, methodName))
+		}
+	}
+	for _, methodEndpoints := range endpoints {
+		sort.SliceStable(methodEndpoints, func(i, j int) bool { return methodEndpoints[i].Prio > methodEndpoints[j].Prio })
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.methods = methods
+	g.endpoints = endpoints
+	return nil
+}
+
+func (g *GrpcHandler) loadProtoFiles(mockDir *MockDir, methods map[string]protoreflect.MethodDescriptor) error {
+	protoFilesBySource := map[Source][]*mockFile{}
+	var sources []Source
+	for _, pattern := range strings.Split(g.protoFilepattern, ",") {
+		protoFiles, err := mockDir.findMockFiles(strings.TrimSpace(pattern), g.requestHandler.sourceSizeLimit)
+		if err != nil {
+			return err
+		}
+		for _, protoFile := range protoFiles {
+			if protoFilesBySource[protoFile.source] == nil {
+				sources = append(sources, protoFile.source)
+			}
+			protoFilesBySource[protoFile.source] = append(protoFilesBySource[protoFile.source], protoFile)
+		}
+	}
+	for _, source := range sources {
+		var protoPaths []string
+		var fsys fs.FS
+		for _, protoFile := range protoFilesBySource[source] {
+			g.logger.Info(fmt.Sprintf("Reading proto file '%s' ...", protoFile.name()))
+			fsys = protoFile.fsys
+			if path.Ext(protoFile.path) == ".proto" {
+				protoPaths = append(protoPaths, protoFile.path)
+				continue
+			}
+			if err := readDescriptorSet(protoFile, methods); err != nil {
+				return err
+			}
+		}
+		if len(protoPaths) == 0 {
+			continue
+		}
+		compiler := protocompile.Compiler{Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: func(filename string) (io.ReadCloser, error) {
+				return fsys.Open(filename)
+			}})}
+		files, err := compiler.Compile(context.Background(), protoPaths...)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			addGrpcMethods(file, methods)
+		}
+	}
+	return nil
+}
+
+func readDescriptorSet(protoFile *mockFile, methods map[string]protoreflect.MethodDescriptor) error {
+	content, err := fs.ReadFile(protoFile.fsys, protoFile.path)
+	if err != nil {
+		return err
+	}
+	descriptorSet := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(content, descriptorSet); err != nil {
+		return fmt.Errorf("error reading descriptor set '%s': %v", protoFile.name(), err)
+	}
+	files, err := protodesc.NewFiles(descriptorSet)
+	if err != nil {
+		return fmt.Errorf("error reading descriptor set '%s': %v", protoFile.name(), err)
+	}
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		addGrpcMethods(file, methods)
+		return true
+	})
+	return nil
+}
+
+func addGrpcMethods(file protoreflect.FileDescriptor, methods map[string]protoreflect.MethodDescriptor) {
+	for i := 0; i < file.Services().Len(); i++ {
+		serviceMethods := file.Services().Get(i).Methods()
+		for j := 0; j < serviceMethods.Len(); j++ {
+			methods[string(serviceMethods.Get(j).FullName())] = serviceMethods.Get(j)
+		}
+	}
+}
+
+func parseGrpcStatus(grpcStatus string) (codes.Code, error) {
+	if len(grpcStatus) == 0 {
+		return codes.OK, nil
+	}
+	codeJSON := strconv.Quote(strings.ToUpper(grpcStatus))
+	if _, err := strconv.ParseUint(grpcStatus, 10, 32); err == nil {
+		codeJSON = grpcStatus
+	}
+	var code codes.Code
+	err := code.UnmarshalJSON([]byte(codeJSON))
+	return code, err
+}
+
+func (g *GrpcHandler) initGrpcResponse(endpoint *GrpcEndpoint, method protoreflect.MethodDescriptor, templates map[string]string) error {
+	if endpoint.Response == nil {
+		endpoint.Response = &GrpcResponse{}
+	}
+	response := endpoint.Response
+	if len(response.Stream) > 0 && !method.IsStreamingServer() {
+		return fmt.Errorf("response.stream of endpoint id '%s' is only supported for server-streaming methods", endpoint.ID)
+	}
+	if method.IsStreamingClient() {
+		return fmt.Errorf("client-streaming method '%s' of endpoint id '%s' is not supported", method.FullName(), endpoint.ID)
+	}
+	statusCode, err := parseGrpcStatus(response.Status)
+	if err != nil {
+		return fmt.Errorf("error parsing status of endpoint id '%s': %v", endpoint.ID, err)
+	}
+	response.StatusCode = statusCode
+	response.Template = template.New(endpoint.ID).Funcs(sprig.TxtFuncMap()).Funcs(g.requestHandler.envFuncMap).Funcs(g.requestHandler.funcMap)
+	for name, text := range templates {
+		if _, err := response.Template.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	if _, err := response.Template.New(templateGrpcResponseMessage).Parse(response.Message); err != nil {
+		return err
+	}
+	if _, err := response.Template.New(templateGrpcResponseMetadata).Parse(response.Metadata); err != nil {
+		return err
+	}
+	for i, streamMessage := range response.Stream {
+		if len(streamMessage.Delay) > 0 {
+			delay, err := time.ParseDuration(streamMessage.Delay)
+			if err != nil {
+				return fmt.Errorf("error parsing delay of stream message %d of endpoint id '%s': %v", i, endpoint.ID, err)
+			}
+			streamMessage.DelayDuration = delay
+		}
+		if _, err := response.Template.New(fmt.Sprintf("%s-%d", templateGrpcResponseStream, i)).Parse(streamMessage.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchGrpcRequest(matchRequest *GrpcMatchRequest, md metadata.MD, requestData interface{}) bool {
+	if matchRequest == nil {
+		return true
+	}
+	for key, val := range matchRequest.Metadata {
+		values := md.Get(key)
+		if len(values) == 0 || values[0] != val {
+			return false
+		}
+	}
+	for jsonPath, expected := range matchRequest.Fields {
+		val, ok := lookupJSONPath(requestData, jsonPath)
+		if !ok || fmt.Sprint(val) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *GrpcHandler) handleCall(srv interface{}, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	methodName := grpcMethodName(fullMethod)
+	g.lock.RLock()
+	method := g.methods[methodName]
+	endpoints := g.endpoints[methodName]
+	g.lock.RUnlock()
+	if method == nil {
+		return status.Errorf(codes.Unimplemented, "method '%s' is not defined in a proto file", fullMethod)
+	}
+	if method.IsStreamingClient() {
+		return status.Errorf(codes.Unimplemented, "client-streaming method '%s' is not supported", fullMethod)
+	}
+	request := dynamicpb.NewMessage(method.Input())
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+	requestJSON, err := protojson.Marshal(request)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error converting request to json: %v", err)
+	}
+	var requestData map[string]interface{}
+	if err := json.Unmarshal(requestJSON, &requestData); err != nil {
+		return status.Errorf(codes.Internal, "error converting request to json: %v", err)
+	}
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	host := ""
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		host = authority[0]
+	}
+	actualRequest := &matches.ActualRequest{Method: http.MethodPost, URL: fullMethod, Header: md, Host: host}
+	actualRequest.Body, actualRequest.Binary, actualRequest.BodyTruncated = matches.EncodeBody(requestJSON, g.requestHandler.bodyLimit)
+	for _, endpoint := range endpoints {
+		if !matchGrpcRequest(endpoint.Request, md, requestData) {
+			continue
+		}
+		match := &matches.Match{EndpointID: methodName, Timestamp: time.Now(), ActualRequest: actualRequest}
+		matchesMetric.With(prometheus.Labels{"endpoint": methodName}).Inc()
+		templateData := &responseTemplateData{
+			RequestURL:          fullMethod,
+			RequestPath:         fullMethod,
+			RequestHost:         host,
+			RequestHeader:       map[string]string{},
+			RequestBody:         string(requestJSON),
+			RequestBodyJSONData: requestData,
+		}
+		for key, values := range md {
+			templateData.RequestHeader[key] = values[0]
+		}
+		err := g.respond(stream, method, endpoint, match, templateData)
+		g.requestHandler.storeMatch(match)
+		return err
+	}
+	mismatch := &matches.Mismatch{
+		MismatchDetails:  fmt.Sprintf("grpc method '%s' matched, but no endpoint matched the request", methodName),
+		Timestamp:        time.Now(),
+		ActualRequest:    actualRequest,
+		NearestEndpoints: grpcNearestEndpoints(fullMethod, endpoints, md, requestData)}
+	g.requestHandler.matchstore.AddMismatch(mismatch)
+	mismatchesMetric.Inc()
+	return status.Errorf(codes.Unimplemented, "no grpc endpoint matched the call of method '%s'", fullMethod)
+}
+
+/*
+grpcNearestEndpoints returns the endpoints of the method which came closest to a call which didn't match, ordered by the number of failed matchers
+*/
+func grpcNearestEndpoints(fullMethod string, endpoints []*GrpcEndpoint, md metadata.MD, requestData interface{}) []*matches.NearestEndpoint {
+	var nearestEndpoints []*matches.NearestEndpoint
+	for _, endpoint := range endpoints {
+		nearestEndpoint := &matches.NearestEndpoint{EndpointID: endpoint.ID, Method: http.MethodPost, Path: fullMethod, Differences: []*matches.Difference{}}
+		for _, key := range sortedKeys(endpoint.Request.Metadata) {
+			if actual := strings.Join(md.Get(key), ","); actual != endpoint.Request.Metadata[key] {
+				nearestEndpoint.Differences = append(nearestEndpoint.Differences, &matches.Difference{Matcher: "metadata",
+					Expected: key + ": " + endpoint.Request.Metadata[key], Actual: key + ": " + actual})
+			}
+		}
+		for _, jsonPath := range sortedKeys(endpoint.Request.Fields) {
+			actual := ""
+			if val, ok := lookupJSONPath(requestData, jsonPath); ok {
+				actual = fmt.Sprint(val)
+			}
+			if actual != endpoint.Request.Fields[jsonPath] {
+				nearestEndpoint.Differences = append(nearestEndpoint.Differences, &matches.Difference{Matcher: "field",
+					Expected: jsonPath + "=" + endpoint.Request.Fields[jsonPath], Actual: jsonPath + "=" + actual})
+			}
+		}
+		nearestEndpoints = append(nearestEndpoints, nearestEndpoint)
+	}
+	sort.SliceStable(nearestEndpoints, func(i, j int) bool {
+		return len(nearestEndpoints[i].Differences) < len(nearestEndpoints[j].Differences)
+	})
+	if len(nearestEndpoints) > maxNearestEndpoints {
+		nearestEndpoints = nearestEndpoints[:maxNearestEndpoints]
+	}
+	return nearestEndpoints
+}
+
+func (g *GrpcHandler) renderGrpcMessage(response *GrpcResponse, templateName string, method protoreflect.MethodDescriptor, templateData *responseTemplateData) (*dynamicpb.Message, error) {
+	var rendered bytes.Buffer
+	if err := response.Template.ExecuteTemplate(&rendered, templateName, templateData); err != nil {
+		return nil, err
+	}
+	message := dynamicpb.NewMessage(method.Output())
+	if len(bytes.TrimSpace(rendered.Bytes())) > 0 {
+		if err := protojson.Unmarshal(rendered.Bytes(), message); err != nil {
+			return nil, fmt.Errorf("error converting '%s' to message type '%s': %v", rendered.String(), method.Output().FullName(), err)
+		}
+	}
+	return message, nil
+}
+
+func (g *GrpcHandler) respond(stream grpc.ServerStream, method protoreflect.MethodDescriptor, endpoint *GrpcEndpoint, match *matches.Match, templateData *responseTemplateData) error {
+	response := endpoint.Response
+	var renderedMetadata bytes.Buffer
+	if err := response.Template.ExecuteTemplate(&renderedMetadata, templateGrpcResponseMetadata, templateData); err != nil {
+		return status.Errorf(codes.Internal, "error rendering response metadata: %v", err)
+	}
+	var responseMetadata map[string]string
+	if err := yaml.Unmarshal(renderedMetadata.Bytes(), &responseMetadata); err != nil {
+		return status.Errorf(codes.Internal, "error unmarshalling response metadata: %v", err)
+	}
+	md := metadata.New(responseMetadata)
+	if err := stream.SetHeader(md); err != nil {
+		return err
+	}
+	match.ActualResponse = &matches.ActualResponse{StatusCode: int(response.StatusCode), Header: md}
+
+	if len(response.Stream) > 0 {
+		if err := g.sendStream(stream, method, response, match, templateData); err != nil {
+			return err
+		}
+	} else if response.StatusCode == codes.OK || (method.IsStreamingServer() && len(response.Message) > 0) {
+		message, err := g.renderGrpcMessage(response, templateGrpcResponseMessage, method, templateData)
+		if err != nil {
+			return status.Errorf(codes.Internal, "error rendering response message: %v", err)
+		}
+		if err := stream.SendMsg(message); err != nil {
+			return err
+		}
+	}
+	if response.StatusCode != codes.OK {
+		return status.Error(response.StatusCode, response.StatusMessage)
+	}
+	return nil
+}
+
+/*
+sendStream sends the rendered stream messages of the response with their delays
+*/
+func (g *GrpcHandler) sendStream(stream grpc.ServerStream, method protoreflect.MethodDescriptor, response *GrpcResponse, match *matches.Match, templateData *responseTemplateData) error {
+	for i, streamMessage := range response.Stream {
+		if streamMessage.DelayDuration > 0 {
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-time.After(streamMessage.DelayDuration):
+			}
+		}
+		message, err := g.renderGrpcMessage(response, fmt.Sprintf("%s-%d", templateGrpcResponseStream, i), method, templateData)
+		if err != nil {
+			return status.Errorf(codes.Internal, "error rendering stream message %d: %v", i, err)
+		}
+		if err := stream.SendMsg(message); err != nil {
+			return err
+		}
+		match.ActualResponse.StreamedEvents++
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package mock
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func startGrpcHandler(t *testing.T) (*GrpcHandler, matches.Matchstore, *grpc.ClientConn) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/grpcmocks", "*-mock.yaml", true, matchstore, nil, "DEBUG")
+	grpcHandler := NewGrpcHandler(mockRequestHandler, "*.proto, *.protoset")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go grpcHandler.Serve(listener)
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		grpcHandler.Shutdown()
+	})
+	return grpcHandler, matchstore, conn
+}
+
+func grpcMessages(t *testing.T, grpcHandler *GrpcHandler, methodName, requestJSON string) (*dynamicpb.Message, *dynamicpb.Message) {
+	method := grpcHandler.methods[methodName]
+	if !assert.NotNil(t, method, "method '%s' not loaded", methodName) {
+		t.FailNow()
+	}
+	request := dynamicpb.NewMessage(method.Input())
+	assert.NoError(t, protojson.Unmarshal([]byte(requestJSON), request))
+	return request, dynamicpb.NewMessage(method.Output())
+}
+
+func TestGrpcHandler_unary(t *testing.T) {
+	grpcHandler, matchstore, conn := startGrpcHandler(t)
+	request, response := grpcMessages(t, grpcHandler, "users.UserService.GetUser", `{ "id": "42", "filter": { "active": true } }`)
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+	err := conn.Invoke(ctx, "/users.UserService/GetUser", request, response, grpc.Header(&header))
+	assert.NoError(t, err)
+	responseJSON, err := protojson.Marshal(response)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{ "id": "42", "name": "active user 42", "created": "2023-01-02T03:04:05Z" }`, string(responseJSON))
+	assert.Equal(t, []string{"acme"}, header.Get("x-mock"))
+
+	userMatches, err := matchstore.GetMatches("users.UserService.GetUser")
+	assert.NoError(t, err)
+	if assert.Len(t, userMatches, 1) {
+		assert.Equal(t, "/users.UserService/GetUser", userMatches[0].ActualRequest.URL)
+		assert.Equal(t, []string{"acme"}, userMatches[0].ActualRequest.Header["x-tenant"])
+		assert.JSONEq(t, `{ "id": "42", "filter": { "active": true } }`, userMatches[0].ActualRequest.Body)
+		assert.Equal(t, int(codes.OK), userMatches[0].ActualResponse.StatusCode)
+	}
+}
+
+func TestGrpcHandler_unaryStatus(t *testing.T) {
+	grpcHandler, _, conn := startGrpcHandler(t)
+	request, response := grpcMessages(t, grpcHandler, "users.UserService.GetUser", `{ "id": "0", "filter": { "active": true } }`)
+	err := conn.Invoke(context.Background(), "/users.UserService/GetUser", request, response)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "user not found", status.Convert(err).Message())
+}
+
+func TestGrpcHandler_noMatch(t *testing.T) {
+	grpcHandler, matchstore, conn := startGrpcHandler(t)
+	request, response := grpcMessages(t, grpcHandler, "users.UserService.GetUser", `{ "id": "42" }`)
+	err := conn.Invoke(context.Background(), "/users.UserService/GetUser", request, response)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	mismatchesCount, err := matchstore.GetMismatchesCount()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), mismatchesCount)
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	if assert.Len(t, mismatches, 1) && assert.Len(t, mismatches[0].NearestEndpoints, 2) {
+		assert.JSONEq(t, `{ "id": "42" }`, mismatches[0].ActualRequest.Body)
+		assert.Equal(t, "getUserNotFound", mismatches[0].NearestEndpoints[0].EndpointID)
+		assert.Equal(t, []*matches.Difference{{Matcher: "field", Expected: "id=0", Actual: "id=42"}}, mismatches[0].NearestEndpoints[0].Differences)
+		assert.Equal(t, "getActiveUser", mismatches[0].NearestEndpoints[1].EndpointID)
+		assert.Equal(t, []*matches.Difference{
+			{Matcher: "metadata", Expected: "x-tenant: acme", Actual: "x-tenant: "},
+			{Matcher: "field", Expected: "filter.active=true", Actual: "filter.active="}}, mismatches[0].NearestEndpoints[1].Differences)
+	}
+
+	err = conn.Invoke(context.Background(), "/users.UnknownService/GetUser", request, response)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "is not defined in a proto file")
+}
+
+func TestGrpcHandler_serverStreaming(t *testing.T) {
+	grpcHandler, matchstore, conn := startGrpcHandler(t)
+	request, _ := grpcMessages(t, grpcHandler, "users.UserService.ListUsers", `{ "limit": 2 }`)
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/users.UserService/ListUsers")
+	assert.NoError(t, err)
+	assert.NoError(t, stream.SendMsg(request))
+	assert.NoError(t, stream.CloseSend())
+	var names []string
+	for {
+		_, response := grpcMessages(t, grpcHandler, "users.UserService.ListUsers", `{}`)
+		if err := stream.RecvMsg(response); err == io.EOF {
+			break
+		} else if !assert.NoError(t, err) {
+			break
+		}
+		names = append(names, response.Get(response.Descriptor().Fields().ByName("name")).String())
+	}
+	assert.Equal(t, []string{"first of 2", "second"}, names)
+	listMatches, err := matchstore.GetMatches("users.UserService.ListUsers")
+	assert.NoError(t, err)
+	if assert.Len(t, listMatches, 1) {
+		assert.Equal(t, 2, listMatches[0].ActualResponse.StreamedEvents)
+	}
+}
+
+func TestGrpcHandler_descriptorSet(t *testing.T) {
+	grpcHandler, _, conn := startGrpcHandler(t)
+	request, response := grpcMessages(t, grpcHandler, "greeter.Greeter.SayHello", `{ "name": "mockgo" }`)
+	assert.NoError(t, conn.Invoke(context.Background(), "/greeter.Greeter/SayHello", request, response))
+	assert.Equal(t, "hello mockgo", response.Get(response.Descriptor().Fields().ByName("message")).String())
+}
+
+func TestGrpcHandler_initGrpcResponse_errors(t *testing.T) {
+	grpcHandler, _, _ := startGrpcHandler(t)
+	getUser := grpcHandler.methods["users.UserService.GetUser"]
+	err := grpcHandler.initGrpcResponse(&GrpcEndpoint{ID: "unaryStream", Response: &GrpcResponse{Stream: []*GrpcStreamMessage{{Message: "{}"}}}}, getUser, nil)
+	assert.ErrorContains(t, err, "response.stream of endpoint id 'unaryStream' is only supported for server-streaming methods")
+	err = grpcHandler.initGrpcResponse(&GrpcEndpoint{ID: "wrongStatus", Response: &GrpcResponse{Status: "NOT_A_STATUS"}}, getUser, nil)
+	assert.ErrorContains(t, err, "error parsing status of endpoint id 'wrongStatus'")
+	err = grpcHandler.initGrpcResponse(&GrpcEndpoint{ID: "clientStream"}, grpcHandler.methods["users.UserService.UploadUsers"], nil)
+	assert.ErrorContains(t, err, "client-streaming method 'users.UserService.UploadUsers' of endpoint id 'clientStream' is not supported")
+}
+
+func TestParseGrpcStatus(t *testing.T) {
+	for grpcStatus, expected := range map[string]codes.Code{"": codes.OK, "5": codes.NotFound, "not_found": codes.NotFound, "UNAVAILABLE": codes.Unavailable} {
+		code, err := parseGrpcStatus(grpcStatus)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"gopkg.in/yaml.v2"
+)
+
+/*
+harSkippedHeaders are the response headers which aren't taken over from an http archive, because the mock server sets them itself
+*/
+var harSkippedHeaders = map[string]bool{"Connection": true, "Content-Encoding": true, "Content-Length": true, "Date": true,
+	"Keep-Alive": true, "Transfer-Encoding": true, http.CanonicalHeaderKey(headerKeyEndpointID): true}
+
+/*
+ConvertHAR converts the entries of an http archive into a mock with an endpoint for each distinct method, path and query of the requests,
+the first entry of a request wins and entries without response are skipped. Headers and bodies are escaped, so that they aren't evaluated as templates.
+*/
+func ConvertHAR(har *matches.HAR, name string) (*Mock, error) {
+	if har == nil || har.Log == nil {
+		return nil, fmt.Errorf("error parsing har , log is missing")
+	}
+	mock := &Mock{Name: name}
+	converted := map[string]bool{}
+	for i, entry := range har.Log.Entries {
+		if entry.Request == nil || entry.Response == nil {
+			return nil, fmt.Errorf("error parsing har entry %d , request and response must be defined", i+1)
+		}
+		if entry.Response.Status == 0 {
+			continue
+		}
+		requestURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing har entry %d , invalid url '%s': %v", i+1, entry.Request.URL, err)
+		}
+		request := &MatchRequest{Method: strings.ToUpper(entry.Request.Method), Path: requestURL.Path}
+		if len(request.Path) == 0 {
+			request.Path = "/"
+		}
+		if query := requestURL.Query(); len(query) > 0 {
+			request.Query = map[string]string{}
+			for key := range query {
+				request.Query[key] = query.Get(key)
+			}
+		}
+		requestKey := request.Method + " " + request.Path + "?" + requestURL.Query().Encode()
+		if converted[requestKey] {
+			continue
+		}
+		converted[requestKey] = true
+		response, err := convertHARResponse(entry.Response)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing har entry %d , %v", i+1, err)
+		}
+		mock.Endpoints = append(mock.Endpoints, &Endpoint{ID: fmt.Sprintf("har-%d", len(mock.Endpoints)+1), Request: request, Response: response})
+	}
+	return mock, nil
+}
+
+func convertHARResponse(harResponse *matches.HARResponse) (*Response, error) {
+	response := &Response{StatusCode: strconv.Itoa(harResponse.Status)}
+	headers := map[string]string{}
+	for _, header := range harResponse.Headers {
+		name := http.CanonicalHeaderKey(header.Name)
+		if strings.HasPrefix(name, ":") || harSkippedHeaders[name] || len(headers[name]) > 0 {
+			continue
+		}
+		headers[name] = header.Value
+	}
+	if len(headers) > 0 {
+		headersYaml, err := yaml.Marshal(headers)
+		if err != nil {
+			return nil, err
+		}
+		response.Headers = escapeTemplate(string(headersYaml))
+	}
+	if content := harResponse.Content; content != nil && len(content.Text) > 0 {
+		if content.Encoding == "base64" {
+			response.BodyBase64 = content.Text
+		} else {
+			response.Body = escapeTemplate(content.Text)
+		}
+	}
+	return response, nil
+}
+
+/*
+escapeTemplate escapes the template actions of a text, so that the template renders the text itself
+*/
+func escapeTemplate(text string) string {
+	return strings.ReplaceAll(text, "{{", "{{`{{`}}")
+}
+
+/*
+handleImportHAR converts the http archive of the request body into a mockfile, the query parameter 'name' is the name of the mock
+*/
+func (r *RequestHandler) handleImportHAR(writer http.ResponseWriter, request *http.Request) {
+	har := &matches.HAR{}
+	if err := json.NewDecoder(request.Body).Decode(har); err != nil {
+		http.Error(writer, fmt.Sprintf("error parsing har: %v", err), http.StatusBadRequest)
+		return
+	}
+	mock, err := ConvertHAR(har, request.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mockfile, err := yaml.Marshal(mock)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("error creating mockfile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/yaml")
+	writer.Write(mockfile)
+}
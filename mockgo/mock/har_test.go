@@ -0,0 +1,102 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func createHARMatch(method, url string, statusCode int, header map[string][]string, body string, binary bool) *matches.Match {
+	return &matches.Match{EndpointID: "id", Timestamp: time.Now(),
+		ActualRequest:  &matches.ActualRequest{Method: method, URL: url, Host: "localhost:8081", Header: map[string][]string{}},
+		ActualResponse: &matches.ActualResponse{StatusCode: statusCode, Header: header, Body: body, Binary: binary}}
+}
+
+func TestConvertHAR(t *testing.T) {
+	har := matches.NewHAR("v1.4.0", []*matches.Match{
+		createHARMatch(http.MethodGet, "/orders?state=open", http.StatusOK,
+			map[string][]string{"Content-Type": {"application/json"}, "Content-Length": {"26"}, "Endpoint-Id": {"orders"}}, `{ "name": "{{ .Order }}" }`, false),
+		createHARMatch(http.MethodGet, "/orders?state=open", http.StatusNotFound, nil, "", false),
+		createHARMatch(http.MethodGet, "/orders", http.StatusNoContent, nil, "", false),
+		createHARMatch(http.MethodPost, "/image", http.StatusCreated, nil, base64.StdEncoding.EncodeToString([]byte{0, 1, 2}), true),
+	}, []*matches.Mismatch{{Timestamp: time.Now(), ActualRequest: &matches.ActualRequest{Method: http.MethodGet, URL: "/unknown"}}})
+
+	mock, err := ConvertHAR(har, "har")
+	assert.NoError(t, err)
+	assert.Equal(t, "har", mock.Name)
+	if assert.Len(t, mock.Endpoints, 3) {
+		assert.Equal(t, &Endpoint{ID: "har-1", Request: &MatchRequest{Method: http.MethodGet, Path: "/orders", Query: map[string]string{"state": "open"}},
+			Response: &Response{StatusCode: "200", Headers: "Content-Type: application/json\n", Body: "{ \"name\": \"{{`{{`}} .Order }}\" }"}}, mock.Endpoints[0])
+		assert.Equal(t, &Endpoint{ID: "har-2", Request: &MatchRequest{Method: http.MethodGet, Path: "/orders"},
+			Response: &Response{StatusCode: "204"}}, mock.Endpoints[1])
+		assert.Equal(t, &Endpoint{ID: "har-3", Request: &MatchRequest{Method: http.MethodPost, Path: "/image"},
+			Response: &Response{StatusCode: "201", BodyBase64: "AAEC"}}, mock.Endpoints[2])
+	}
+}
+
+func TestConvertHAR_Error(t *testing.T) {
+	_, err := ConvertHAR(&matches.HAR{}, "")
+	assert.EqualError(t, err, "error parsing har , log is missing")
+	_, err = ConvertHAR(&matches.HAR{Log: &matches.HARLog{Entries: []*matches.HAREntry{{Request: &matches.HARRequest{}}}}}, "")
+	assert.EqualError(t, err, "error parsing har entry 1 , request and response must be defined")
+}
+
+func TestMockRequestHandler_importHAR_roundtrip(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddAPIRoutes(router)
+	mockRequestHandler.AddRoutes(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/minimal", nil))
+	expectedCode, expectedBody := recorder.Code, recorder.Body.String()
+	recordedMatches, err := matchstore.GetMatches("minimal")
+	assert.NoError(t, err)
+	assert.Len(t, recordedMatches, 1)
+
+	harJSON, err := json.Marshal(matches.NewHAR("v1.4.0", recordedMatches, nil))
+	assert.NoError(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/__/import/har?name=imported", bytes.NewReader(harJSON))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/yaml", recorder.Header().Get("Content-Type"))
+
+	mockDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(mockDir, "imported-mock.yaml"), recorder.Body.Bytes(), 0644))
+	importedRequestHandler := NewRequestHandler("/__", mockDir, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, importedRequestHandler.LoadFiles())
+	assert.Equal(t, []string{"har-1"}, importedRequestHandler.EndpointIDs())
+	importedRouter := mux.NewRouter()
+	importedRequestHandler.AddRoutes(importedRouter)
+	recorder = httptest.NewRecorder()
+	importedRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/minimal", nil))
+	assert.Equal(t, expectedCode, recorder.Code)
+	assert.Equal(t, expectedBody, recorder.Body.String())
+}
+
+func TestMockRequestHandler_importHAR_badRequest(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	router := mux.NewRouter()
+	mockRequestHandler.AddAPIRoutes(router)
+	for _, body := range []string{"no json", "{}"} {
+		request := httptest.NewRequest(http.MethodPost, "/__/import/har", bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
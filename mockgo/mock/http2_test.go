@@ -0,0 +1,102 @@
+package mock
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/testutil"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func createHTTP2Router(t *testing.T) *mux.Router {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "http2-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	return router
+}
+
+func assertTrailersResponse(t *testing.T, client *http.Client, url string, expectedProtoMajor int) {
+	response, err := client.Get(url + "/trailers")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedProtoMajor, response.ProtoMajor)
+	assert.Equal(t, "body with trailers", string(body))
+	assert.Equal(t, "0", response.Trailer.Get("Grpc-Status"))
+	assert.Equal(t, "/trailers", response.Trailer.Get("X-Checksum"))
+}
+
+func TestMockRequestHandler_serving_trailers(t *testing.T) {
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t, testutil.CreateOutgoingRequest(t, http.MethodGet, "/trailers", testutil.CreateHeader(), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "body with trailers", responseBody)
+			assert.Equal(t, "0", response.Trailer.Get("Grpc-Status"))
+			assert.Equal(t, "/trailers", response.Trailer.Get("X-Checksum"))
+		}))
+}
+
+func TestMockRequestHandler_serving_informational(t *testing.T) {
+	var informationalCodes []int
+	var informationalLinks []string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			informationalCodes = append(informationalCodes, code)
+			informationalLinks = append(informationalLinks, header.Get("Link"))
+			return nil
+		},
+	}
+	request := testutil.CreateOutgoingRequest(t, http.MethodGet, "/earlyhints", testutil.CreateHeader(), "")
+	request = request.WithContext(httptrace.WithClientTrace(context.Background(), trace))
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t, request,
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "early hints sent", responseBody)
+			assert.Equal(t, "</style.css>; rel=preload; as=style", response.Header.Get("Link"))
+		}))
+	assert.Equal(t, []int{http.StatusEarlyHints}, informationalCodes)
+	assert.Equal(t, []string{"</style.css>; rel=preload; as=style"}, informationalLinks)
+}
+
+func TestMockRequestHandler_serving_http2TLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(createHTTP2Router(t))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	assertTrailersResponse(t, server.Client(), server.URL, 2)
+}
+
+func TestMockRequestHandler_serving_h2c(t *testing.T) {
+	server := httptest.NewServer(h2c.NewHandler(createHTTP2Router(t), &http2.Server{}))
+	defer server.Close()
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	assertTrailersResponse(t, client, server.URL, 2)
+	assertTrailersResponse(t, http.DefaultClient, server.URL, 1)
+}
+
+func TestMockRequestHandler_initResponseTemplates_informationalError(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	err := mockRequestHandler.initResponseTemplates(&Endpoint{ID: "switchingProtocols", Response: &Response{Informational: []*Informational{{StatusCode: 101}}}}, nil, nil)
+	assert.ErrorContains(t, err, "error parsing endpoint id 'switchingProtocols' , 101 is not an informational status code")
+}
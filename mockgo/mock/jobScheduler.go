@@ -0,0 +1,206 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	sprig "github.com/Masterminds/sprig/v3"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const templateJobRun = "jobRun"
+
+/*
+LeaderElection decides which instance of a cluster runs the jobs, so that a job runs once per cluster and not once per instance
+*/
+type LeaderElection interface {
+	IsLeader() (bool, error)
+}
+
+type jobTemplateData struct {
+	JobID string
+	Runs  int
+	Time  time.Time
+}
+
+/*
+intervalSchedule is a cron.Schedule with a fixed interval between the runs
+*/
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s *intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+/*
+JobScheduler runs the jobs of the mockfiles, when a LeaderElection is given only the leader runs the jobs
+*/
+type JobScheduler struct {
+	requestHandler *RequestHandler
+	leaderElection LeaderElection
+	logger         *zap.Logger
+	lock           sync.Mutex
+	cancel         context.CancelFunc
+	running        sync.WaitGroup
+}
+
+/*
+NewJobScheduler creates an instance of JobScheduler, the jobs are loaded and scheduled with RequestHandler.LoadFiles
+*/
+func NewJobScheduler(requestHandler *RequestHandler, leaderElection LeaderElection) *JobScheduler {
+	jobScheduler := &JobScheduler{
+		requestHandler: requestHandler,
+		leaderElection: leaderElection,
+		logger:         requestHandler.logger,
+	}
+	requestHandler.jobScheduler = jobScheduler
+	return jobScheduler
+}
+
+/*
+load initializes the jobs of the mocks and replaces the scheduled jobs with them
+*/
+func (s *JobScheduler) load(mocks []*Mock, templates map[string]string) error {
+	var jobs []*Job
+	jobCounter := 0
+	for _, mock := range mocks {
+		for _, job := range mock.Jobs {
+			jobCounter++
+			if len(job.ID) == 0 {
+				job.ID = "job" + strconv.Itoa(jobCounter)
+			}
+			if err := s.initJob(job, templates); err != nil {
+				s.logger.Error(fmt.Sprintf("Can't initialize job id '%s', skipping job ", job.ID), zap.Error(err))
+				continue
+			}
+			jobs = append(jobs, job)
+			s.logger.Info(fmt.Sprintf("register job with id '%s'", job.ID))
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, job := range jobs {
+		s.running.Add(1)
+		go s.schedule(ctx, job)
+	}
+	return nil
+}
+
+func (s *JobScheduler) initJob(job *Job, templates map[string]string) error {
+	if (len(job.Cron) > 0) == (len(job.Interval) > 0) {
+		return fmt.Errorf("job id '%s' must have either a cron or an interval schedule", job.ID)
+	}
+	if len(job.Cron) > 0 {
+		schedule, err := cron.ParseStandard(job.Cron)
+		if err != nil {
+			return fmt.Errorf("error parsing cron of job id '%s': %v", job.ID, err)
+		}
+		job.Schedule = schedule
+	} else {
+		interval, err := time.ParseDuration(job.Interval)
+		if err != nil {
+			return fmt.Errorf("error parsing interval of job id '%s': %v", job.ID, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("interval of job id '%s' must be positive", job.ID)
+		}
+		job.Schedule = &intervalSchedule{interval: interval}
+	}
+	if job.Count < 0 {
+		return fmt.Errorf("count of job id '%s' must not be negative", job.ID)
+	}
+	job.Template = template.New(job.ID).Funcs(sprig.TxtFuncMap()).Funcs(s.requestHandler.envFuncMap).Funcs(s.requestHandler.funcMap)
+	for name, text := range templates {
+		if _, err := job.Template.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	if _, err := job.Template.New(templateJobRun).Parse(job.Run); err != nil {
+		return err
+	}
+	return initCallbacks(job.ID, job.Callbacks, job.Template)
+}
+
+/*
+schedule runs the job at the times of its schedule until the count of runs is reached or the jobs are reloaded
+*/
+func (s *JobScheduler) schedule(ctx context.Context, job *Job) {
+	defer s.running.Done()
+	runs := 0
+	for job.Count == 0 || runs < job.Count {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now = <-timer.C:
+		}
+		if s.leaderElection != nil {
+			leader, err := s.leaderElection.IsLeader()
+			if err != nil {
+				s.logger.Error(fmt.Sprintf("can't elect leader for job '%s', skipping run", job.ID), zap.Error(err))
+				continue
+			}
+			if !leader {
+				s.logger.Debug(fmt.Sprintf("job '%s' skipped, this instance isn't the leader", job.ID))
+				continue
+			}
+		}
+		s.run(job, &jobTemplateData{JobID: job.ID, Runs: runs, Time: now})
+		runs++
+	}
+}
+
+func (s *JobScheduler) run(job *Job, templateData *jobTemplateData) {
+	var output bytes.Buffer
+	if err := job.Template.ExecuteTemplate(&output, templateJobRun, templateData); err != nil {
+		s.logger.Error(fmt.Sprintf("error running job '%s'", job.ID), zap.Error(err))
+		return
+	}
+	s.logger.Debug(fmt.Sprintf("job '%s' run %d: %s", job.ID, templateData.Runs+1, strings.TrimSpace(output.String())))
+	s.requestHandler.sendCallbacks(job.Callbacks, job.Template, templateData)
+}
+
+/*
+Shutdown stops all jobs and waits until running jobs are finished
+*/
+func (s *JobScheduler) Shutdown() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stop()
+}
+
+func (s *JobScheduler) stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.running.Wait()
+		s.cancel = nil
+	}
+}
+
+/*
+ShutdownJobs stops the jobs of the job scheduler of the request handler
+*/
+func (r *RequestHandler) ShutdownJobs() {
+	if r.jobScheduler != nil {
+		r.jobScheduler.Shutdown()
+	}
+}
@@ -0,0 +1,98 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/kvstore"
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticLeaderElection struct {
+	leader bool
+	err    error
+}
+
+func (l *staticLeaderElection) IsLeader() (bool, error) {
+	return l.leader, l.err
+}
+
+func startJobScheduler(t *testing.T, leaderElection LeaderElection, callbackURL string) (*RequestHandler, *JobScheduler, kvstore.Storage, matches.Matchstore) {
+	kvStore := kvstore.NewInmemoryStorage()
+	assert.NoError(t, kvStore.Put("orders", "o1", "PENDING"))
+	assert.NoError(t, kvStore.Put("orders", "o2", "CANCELLED"))
+	assert.NoError(t, kvStore.Put("config", "callbackUrl", callbackURL))
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/jobmocks", "*-mock.yaml", false, matchstore, kvstore.NewKVStoreTemplateFuncMap(kvStore), "DEBUG")
+	jobScheduler := NewJobScheduler(mockRequestHandler, leaderElection)
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	t.Cleanup(jobScheduler.Shutdown)
+	return mockRequestHandler, jobScheduler, kvStore, matchstore
+}
+
+func TestJobScheduler_intervalJobWithCount(t *testing.T) {
+	receiver, received := startCallbackReceiver(t)
+	mockRequestHandler, jobScheduler, kvStore, matchstore := startJobScheduler(t, nil, receiver.URL)
+	time.Sleep(200 * time.Millisecond)
+	jobScheduler.Shutdown()
+	mockRequestHandler.WaitForCallbacks()
+
+	status, err := kvStore.Get("orders", "o1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SHIPPED", status)
+	status, err = kvStore.Get("orders", "o2")
+	assert.NoError(t, err)
+	assert.Equal(t, "CANCELLED", status)
+	cleanup, err := kvStore.Get("orders", "cleanup")
+	assert.NoError(t, err)
+	assert.Nil(t, cleanup, "cron job must not run before 3 am")
+
+	callbacks := received()
+	if assert.Len(t, callbacks, 2, "job must run count times") {
+		assert.Equal(t, http.MethodPost, callbacks[0].method)
+		assert.Equal(t, "/shipped", callbacks[0].path)
+		assert.ElementsMatch(t, []string{"run 0", "run 1"}, []string{callbacks[0].body, callbacks[1].body})
+	}
+	callbackMatches, err := matchstore.GetMatches("shipOrders-callback-1")
+	assert.NoError(t, err)
+	assert.Len(t, callbackMatches, 2)
+}
+
+func TestJobScheduler_notLeader(t *testing.T) {
+	for _, leaderElection := range []*staticLeaderElection{{leader: false}, {leader: true, err: fmt.Errorf("no quorum")}} {
+		receiver, received := startCallbackReceiver(t)
+		_, jobScheduler, kvStore, _ := startJobScheduler(t, leaderElection, receiver.URL)
+		time.Sleep(100 * time.Millisecond)
+		jobScheduler.Shutdown()
+
+		status, err := kvStore.Get("orders", "o1")
+		assert.NoError(t, err)
+		assert.Equal(t, "PENDING", status)
+		assert.Empty(t, received())
+	}
+}
+
+func TestJobScheduler_initJob(t *testing.T) {
+	jobScheduler := NewJobScheduler(NewRequestHandler("/__", "", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG"), nil)
+	for _, testCase := range []struct {
+		job           *Job
+		expectedError string
+	}{
+		{job: &Job{ID: "none"}, expectedError: "job id 'none' must have either a cron or an interval schedule"},
+		{job: &Job{ID: "both", Cron: "@hourly", Interval: "1h"}, expectedError: "job id 'both' must have either a cron or an interval schedule"},
+		{job: &Job{ID: "cron", Cron: "61 * * * *"}, expectedError: "error parsing cron of job id 'cron'"},
+		{job: &Job{ID: "interval", Interval: "often"}, expectedError: "error parsing interval of job id 'interval'"},
+		{job: &Job{ID: "negative", Interval: "-1s"}, expectedError: "interval of job id 'negative' must be positive"},
+		{job: &Job{ID: "count", Interval: "1s", Count: -1}, expectedError: "count of job id 'count' must not be negative"},
+		{job: &Job{ID: "callback", Interval: "1s", Callbacks: []*Callback{{}}}, expectedError: "error parsing callback 1 of 'callback' , url must be defined"},
+	} {
+		assert.ErrorContains(t, jobScheduler.initJob(testCase.job, nil), testCase.expectedError)
+	}
+	job := &Job{ID: "every", Cron: "@every 1m"}
+	assert.NoError(t, jobScheduler.initJob(job, nil))
+	now := time.Now()
+	assert.True(t, now.Add(time.Minute).Truncate(time.Second).Equal(job.Schedule.Next(now)))
+}
@@ -0,0 +1,75 @@
+package mock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+MockListener serves the mockfiles of MockDirs on an own port or, when Host is set, for requests to this virtual host
+*/
+type MockListener struct {
+	Port     int
+	Host     string
+	MockDirs []*MockDir
+}
+
+/*
+ParseMockListeners creates the MockListeners for a semicolon separated list of entries. Each entry is a port or a host name,
+followed by '=' and the mock dirs in the format of ParseMockDirs, e.g. "9001=/mocks/payments;payments.local=/mocks/payments,/mocks/common"
+*/
+func ParseMockListeners(mockListeners string, recursive bool) ([]*MockListener, error) {
+	var result []*MockListener
+	ports := map[int]bool{}
+	hosts := map[string]bool{}
+	for _, entry := range strings.Split(mockListeners, ";") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		address, mockDirs, found := strings.Cut(entry, "=")
+		address = strings.TrimSpace(address)
+		if !found || len(address) == 0 {
+			return nil, fmt.Errorf("mock listener entry '%s' must have the format '<port or host>=<mock dirs>'", entry)
+		}
+		listener := &MockListener{}
+		if port, err := strconv.Atoi(address); err == nil {
+			if port <= 0 || port > 65535 {
+				return nil, fmt.Errorf("mock listener entry '%s' has an invalid port %d", entry, port)
+			}
+			if ports[port] {
+				return nil, fmt.Errorf("mock listener port %d is defined more than once", port)
+			}
+			ports[port] = true
+			listener.Port = port
+		} else {
+			host := strings.ToLower(address)
+			if strings.ContainsAny(host, ":/ ") {
+				return nil, fmt.Errorf("mock listener entry '%s' has an invalid host '%s'", entry, address)
+			}
+			if hosts[host] {
+				return nil, fmt.Errorf("mock listener host '%s' is defined more than once", host)
+			}
+			hosts[host] = true
+			listener.Host = host
+		}
+		dirs, err := ParseMockDirs(mockDirs, recursive)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mock dirs of mock listener '%s': %v", address, err)
+		}
+		listener.MockDirs = dirs
+		result = append(result, listener)
+	}
+	return result, nil
+}
+
+/*
+Name returns the port or the host of the listener
+*/
+func (l *MockListener) Name() string {
+	if len(l.Host) > 0 {
+		return l.Host
+	}
+	return strconv.Itoa(l.Port)
+}
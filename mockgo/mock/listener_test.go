@@ -0,0 +1,93 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMockListeners(t *testing.T) {
+	listeners, err := ParseMockListeners(" 9001=/mocks/payments ; Shipping.local=/mocks/shipping=/v1,/mocks/common;", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MockListener{
+		{Port: 9001, MockDirs: []*MockDir{{Path: "/mocks/payments", Recursive: true}}},
+		{Host: "shipping.local", MockDirs: []*MockDir{{Path: "/mocks/shipping", PathPrefix: "/v1", Recursive: true}, {Path: "/mocks/common", Recursive: true}}},
+	}, listeners)
+	assert.Equal(t, "9001", listeners[0].Name())
+	assert.Equal(t, "shipping.local", listeners[1].Name())
+
+	listeners, err = ParseMockListeners("", false)
+	assert.NoError(t, err)
+	assert.Empty(t, listeners)
+}
+
+func TestParseMockListeners_errors(t *testing.T) {
+	for mockListeners, expectedError := range map[string]string{
+		"9001":                          "must have the format '<port or host>=<mock dirs>'",
+		"=/mocks":                       "must have the format '<port or host>=<mock dirs>'",
+		"70000=/mocks":                  "has an invalid port 70000",
+		"9001=/mocks;9001=/other":       "mock listener port 9001 is defined more than once",
+		"a.local=/mocks;A.local=/other": "mock listener host 'a.local' is defined more than once",
+		"a.local:8080=/mocks":           "has an invalid host 'a.local:8080'",
+		"9001=,":                        "error parsing mock dirs of mock listener '9001'",
+	} {
+		_, err := ParseMockListeners(mockListeners, false)
+		assert.ErrorContains(t, err, expectedError, mockListeners)
+	}
+}
+
+func TestMockRequestHandler_AddMockRoutes_apiPath(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	mockRequestHandler.AddMockDir(&MockDir{Path: "../../test/mockdirs/shipping", PathPrefix: "/__"})
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+
+	excluded := mux.NewRouter()
+	mockRequestHandler.AddMockRoutes(excluded, true)
+	recorder := httptest.NewRecorder()
+	excluded.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/__/status", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	included := mux.NewRouter()
+	mockRequestHandler.AddMockRoutes(included, false)
+	recorder = httptest.NewRecorder()
+	included.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/__/status", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "shipping", recorder.Body.String())
+}
+
+func TestMockRequestHandler_ReloadWith(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mockdirs/payments", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	listenerRequestHandler := NewRequestHandler("/__", "", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	listenerRequestHandler.AddMockDir(&MockDir{Path: "../../test/mockdirs/shipping"})
+	mockRequestHandler.ReloadWith(listenerRequestHandler)
+
+	router := mux.NewRouter()
+	mockRequestHandler.AddAPIRoutes(router)
+	listenerRouter := mux.NewRouter()
+	listenerRequestHandler.AddMockRoutes(listenerRouter, false)
+
+	recorder := httptest.NewRecorder()
+	listenerRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/__/reload", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	listenerRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "shipping", recorder.Body.String())
+}
+
+func TestMockRequestHandler_RejectGrpc(t *testing.T) {
+	listenerRequestHandler := NewRequestHandler("/__", "", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	listenerRequestHandler.AddMockDir(&MockDir{Path: "../../test/grpcmocks"})
+	listenerRequestHandler.RejectGrpc()
+	assert.ErrorContains(t, listenerRequestHandler.LoadFiles(), "grpc endpoints of mockfile 'users-mock.yaml' are not supported")
+}
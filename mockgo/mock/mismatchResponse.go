@@ -0,0 +1,134 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	sprig "github.com/Masterminds/sprig/v3"
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"gopkg.in/yaml.v2"
+)
+
+/*
+initMismatchResponses creates the templates of the mismatch responses of the mock files, a path prefix can only have one mismatch response
+*/
+func (r *RequestHandler) initMismatchResponses(mocks []*Mock, templates map[string]string) ([]*MismatchResponse, error) {
+	var mismatchResponses []*MismatchResponse
+	pathPrefixes := map[string]bool{}
+	for _, mock := range mocks {
+		for _, mismatchResponse := range mock.Mismatches {
+			mismatchResponse.Mock = mock
+			if len(mismatchResponse.PathPrefix) > 0 && !strings.HasPrefix(mismatchResponse.PathPrefix, "/") {
+				return nil, fmt.Errorf("error parsing mismatch response with path prefix '%s' , path prefix must start with '/'", mismatchResponse.PathPrefix)
+			}
+			pathPrefix := mismatchResponse.fullPathPrefix()
+			if pathPrefixes[pathPrefix] {
+				return nil, fmt.Errorf("error parsing mismatch response with path prefix '%s' , path prefix is defined more than once", pathPrefix)
+			}
+			pathPrefixes[pathPrefix] = true
+			if err := r.initMismatchResponseTemplates(mismatchResponse, templates); err != nil {
+				return nil, fmt.Errorf("error parsing mismatch response with path prefix '%s' , %v", pathPrefix, err)
+			}
+			r.logger.Info(fmt.Sprintf("register mismatch response for path prefix '%s'", pathPrefix))
+			mismatchResponses = append(mismatchResponses, mismatchResponse)
+		}
+	}
+	return mismatchResponses, nil
+}
+
+func (r *RequestHandler) initMismatchResponseTemplates(mismatchResponse *MismatchResponse, templates map[string]string) error {
+	mismatchResponse.Template = template.New("mismatch").Funcs(sprig.TxtFuncMap()).Funcs(r.envFuncMap).Funcs(r.funcMap)
+	for name, text := range templates {
+		if _, err := mismatchResponse.Template.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	if len(mismatchResponse.StatusCode) == 0 {
+		mismatchResponse.StatusCode = strconv.Itoa(http.StatusNotFound)
+	}
+	for name, text := range map[string]string{templateResponseStatus: mismatchResponse.StatusCode,
+		templateResponseHeader: mismatchResponse.Headers, templateResponseBody: mismatchResponse.Body} {
+		if _, err := mismatchResponse.Template.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MismatchResponse) fullPathPrefix() string {
+	pathPrefix := m.PathPrefix
+	if m.Mock != nil {
+		pathPrefix = m.Mock.PathPrefix + pathPrefix
+	}
+	return strings.TrimSuffix(pathPrefix, "/")
+}
+
+/*
+findMismatchResponse returns the mismatch response with the longest path prefix which contains the request path
+*/
+func (r *RequestHandler) findMismatchResponse(request *http.Request) *MismatchResponse {
+	var found *MismatchResponse
+	for _, mismatchResponse := range r.mismatchResponses {
+		pathPrefix := mismatchResponse.fullPathPrefix()
+		if request.URL.Path != pathPrefix && !strings.HasPrefix(request.URL.Path, pathPrefix+"/") {
+			continue
+		}
+		if found == nil || len(pathPrefix) > len(found.fullPathPrefix()) {
+			found = mismatchResponse
+		}
+	}
+	return found
+}
+
+func (r *RequestHandler) renderMismatchResponse(writer http.ResponseWriter, request *http.Request, mismatchResponse *MismatchResponse, mismatch *matches.Mismatch, queryParams map[string]string) {
+	responseTemplateData, err := r.createResponseTemplateData(request, map[string]string{}, queryParams)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error rendering mismatch response: %v", err)
+		return
+	}
+	responseTemplateData.Mismatch = mismatch
+
+	var renderedHeaders bytes.Buffer
+	if err := mismatchResponse.Template.ExecuteTemplate(&renderedHeaders, templateResponseHeader, responseTemplateData); err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error rendering mismatch response headers: %v", err)
+		return
+	}
+	var headers map[string]string
+	if err := yaml.Unmarshal(renderedHeaders.Bytes(), &headers); err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error unmarshalling mismatch response headers: %v", err)
+		return
+	}
+	for key, val := range headers {
+		writer.Header().Add(key, val)
+	}
+
+	var renderedStatus bytes.Buffer
+	if err := mismatchResponse.Template.ExecuteTemplate(&renderedStatus, templateResponseStatus, responseTemplateData); err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error rendering mismatch response status: %v", err)
+		return
+	}
+	responseStatus, err := strconv.Atoi(strings.TrimSpace(renderedStatus.String()))
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error converting mismatch response status: %v", err)
+		return
+	}
+	responseTemplateData.ResponseStatus = responseStatus
+
+	var renderedBody bytes.Buffer
+	if err := mismatchResponse.Template.ExecuteTemplate(&renderedBody, templateResponseBody, responseTemplateData); err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error rendering mismatch response body: %v", err)
+		return
+	}
+	writer.WriteHeader(responseStatus)
+	writer.Write(renderedBody.Bytes())
+}
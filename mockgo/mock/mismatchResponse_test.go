@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockRequestHandler_serving_mismatchResponses(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mismatchmocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/orders/42", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `{ "id": "42" }`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/order/42", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "true", recorder.Header().Get("Mock-Mismatch"))
+	problem := map[string]interface{}{}
+	if assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem)) {
+		assert.Equal(t, map[string]interface{}{"type": "about:blank", "title": "no mock endpoint for /api/order/42", "status": float64(404), "nearestEndpoint": "getOrder"}, problem)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api", nil)
+	request.Header.Set("Accept", "application/xml")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/apis", nil))
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+	assert.Equal(t, "no mock for /apis", recorder.Body.String())
+	assert.Empty(t, recorder.Header().Get("Mock-Mismatch"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/__/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	mismatchesCount, err := matchstore.GetMismatchesCount()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), mismatchesCount)
+}
+
+func TestMockRequestHandler_serving_withoutMismatchResponse(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/minimalwrong", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "404 page not found\n", recorder.Body.String())
+}
+
+func TestMockRequestHandler_LoadFiles_duplicateMismatchPathPrefix(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("", "../../test/mocksWithError/duplicateMismatchPathPrefix", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.EqualError(t, mockRequestHandler.LoadFiles(), "error parsing mismatch response with path prefix '/api' , path prefix is defined more than once")
+}
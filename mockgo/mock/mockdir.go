@@ -0,0 +1,154 @@
+package mock
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+MockDir is a location where mockfiles are searched. All endpoints of the mockfiles found in a MockDir are served under PathPrefix.
+Path is a local directory, a glob pattern, a single file, a bundle or an http(s) url. If Source is set, Path is ignored.
+*/
+type MockDir struct {
+	Path       string
+	PathPrefix string
+	Recursive  bool
+	Source     Source
+}
+
+/*
+ParseMockDirs creates the MockDirs for a comma separated list of directories or glob patterns. Each entry can have an
+optional path prefix separated by '=', e.g. "/mocks/payments=/payments,/mocks/shipping=/shipping"
+*/
+func ParseMockDirs(mockDirs string, recursive bool) ([]*MockDir, error) {
+	var result []*MockDir
+	for _, entry := range strings.Split(mockDirs, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		dir := &MockDir{Path: entry, Recursive: recursive}
+		if pos := strings.LastIndex(entry, "="); pos >= 0 && (!isURL(entry) || strings.HasPrefix(entry[pos+1:], "/")) {
+			dir.Path = strings.TrimSpace(entry[:pos])
+			pathPrefix, err := normalizePathPrefix(entry[pos+1:])
+			if err != nil {
+				return nil, err
+			}
+			dir.PathPrefix = pathPrefix
+		}
+		if len(dir.Path) == 0 {
+			return nil, fmt.Errorf("mock dir entry '%s' has no path", entry)
+		}
+		if isURL(dir.Path) {
+			source, err := NewHTTPSource(dir.Path, nil)
+			if err != nil {
+				return nil, err
+			}
+			dir.Source = source
+		}
+		result = append(result, dir)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no mock dir defined in '%s'", mockDirs)
+	}
+	return result, nil
+}
+
+func normalizePathPrefix(pathPrefix string) (string, error) {
+	pathPrefix = strings.Trim(strings.TrimSpace(pathPrefix), "/")
+	if len(pathPrefix) == 0 {
+		return "", nil
+	}
+	for _, pathSegment := range strings.Split(pathPrefix, "/") {
+		if pathSegment == "*" || pathSegment == "**" || strings.HasPrefix(pathSegment, "{") {
+			return "", fmt.Errorf("path prefix '%s' must not contain wildcards or path params", pathPrefix)
+		}
+	}
+	return "/" + pathPrefix, nil
+}
+
+/*
+mockFile is a mockfile found in a MockDir
+*/
+type mockFile struct {
+	path    string
+	mockDir *MockDir
+	source  Source
+	fsys    fs.FS
+}
+
+func (m *mockFile) name() string {
+	return m.source.Name() + ":" + m.path
+}
+
+/*
+findMockFiles returns the files matching the pattern, sources which are read into memory must not exceed the size limit
+*/
+func (d *MockDir) findMockFiles(pattern string, sizeLimit int64) ([]*mockFile, error) {
+	sources, err := d.sources()
+	if err != nil {
+		return nil, err
+	}
+	var result []*mockFile
+	for _, source := range sources {
+		if limitedSource, ok := source.(sizeLimitedSource); ok {
+			limitedSource.setSizeLimit(sizeLimit)
+		}
+		fsys, err := source.Open()
+		if err != nil {
+			return nil, err
+		}
+		paths, err := source.MockFiles(fsys, pattern, d.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			result = append(result, &mockFile{path: path, mockDir: d, source: source, fsys: fsys})
+		}
+	}
+	return result, nil
+}
+
+func (d *MockDir) sources() ([]Source, error) {
+	if d.Source != nil {
+		return []Source{d.Source}, nil
+	}
+	roots := []string{d.Path}
+	if isGlobPattern(d.Path) {
+		globMatches, err := filepath.Glob(d.Path)
+		if err != nil {
+			return nil, err
+		}
+		roots = globMatches
+	}
+	var sources []Source
+	for _, root := range roots {
+		info, err := os.Lstat(root)
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			info, err = os.Stat(root)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case info.IsDir():
+			sources = append(sources, &fsSource{name: root, fsys: localFS(root)})
+		case isBundle(root):
+			sources = append(sources, NewBundleSource(root))
+		default:
+			sources = append(sources, &fsSource{name: filepath.Dir(root), fsys: localFS(filepath.Dir(root)), file: filepath.Base(root)})
+		}
+	}
+	return sources, nil
+}
+
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
@@ -0,0 +1,79 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMockDirs(t *testing.T) {
+	mockDirs, err := ParseMockDirs(" /mocks/payments=/payments/ ,/mocks/shipping=shipping,/mocks/common", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MockDir{
+		{Path: "/mocks/payments", PathPrefix: "/payments", Recursive: true},
+		{Path: "/mocks/shipping", PathPrefix: "/shipping", Recursive: true},
+		{Path: "/mocks/common", PathPrefix: "", Recursive: true},
+	}, mockDirs)
+}
+
+func TestParseMockDirs_errors(t *testing.T) {
+	_, err := ParseMockDirs(" , ", false)
+	assert.ErrorContains(t, err, "no mock dir defined")
+	_, err = ParseMockDirs("=/payments", false)
+	assert.ErrorContains(t, err, "has no path")
+	_, err = ParseMockDirs("/mocks=/payments/*", false)
+	assert.ErrorContains(t, err, "must not contain wildcards")
+}
+
+func TestMockDir_findMockFiles(t *testing.T) {
+	nonRecursive := &MockDir{Path: "../../test/mockdirs/payments"}
+	mockFiles, err := nonRecursive.findMockFiles("*-mock.yaml", DefaultSourceSizeLimit)
+	assert.NoError(t, err)
+	assert.Len(t, mockFiles, 1)
+
+	recursive := &MockDir{Path: "../../test/mockdirs/payments", Recursive: true}
+	mockFiles, err = recursive.findMockFiles("*-mock.yaml", DefaultSourceSizeLimit)
+	assert.NoError(t, err)
+	assert.Len(t, mockFiles, 2)
+	assert.Equal(t, "payments-mock.yaml", mockFiles[0].path)
+	assert.Equal(t, "v2/paymentsv2-mock.yaml", mockFiles[1].path)
+
+	glob := &MockDir{Path: "../../test/mockdirs/*"}
+	mockFiles, err = glob.findMockFiles("*-mock.yaml", DefaultSourceSizeLimit)
+	assert.NoError(t, err)
+	assert.Len(t, mockFiles, 2)
+
+	file := &MockDir{Path: "../../test/mockdirs/shipping/shipping-mock.yaml"}
+	mockFiles, err = file.findMockFiles("*-mock.yaml", DefaultSourceSizeLimit)
+	assert.NoError(t, err)
+	assert.Len(t, mockFiles, 1)
+	assert.Equal(t, "../../test/mockdirs/shipping:shipping-mock.yaml", mockFiles[0].name())
+}
+
+func TestMockRequestHandler_LoadFiles_multipleMockDirs(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mockdirs/payments=/payments,../../test/mockdirs/shipping=/shipping",
+		"*-mock.yaml", true, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+
+	for path, expectedEndpointID := range map[string]string{
+		"/payments/status":    "payments",
+		"/payments/v2/status": "paymentsv2",
+		"/shipping/status":    "shipping",
+	} {
+		endpoint, _, _, _, _ := mockRequestHandler.matchRequestToEndpoint(httptest.NewRequest(http.MethodGet, path, nil))
+		if assert.NotNil(t, endpoint, "no endpoint found for path '%s'", path) {
+			assert.Equal(t, expectedEndpointID, endpoint.ID)
+		}
+	}
+	endpoint, _, _, _, _ := mockRequestHandler.matchRequestToEndpoint(httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Nil(t, endpoint)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/payments/status", nil)
+	endpoint, match, _, pathParams, queryParams := mockRequestHandler.matchRequestToEndpoint(request)
+	mockRequestHandler.renderResponse(recorder, request, endpoint, match, pathParams, queryParams)
+	assert.Equal(t, `{ "service": "payments" }`, recorder.Body.String())
+}
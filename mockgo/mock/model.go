@@ -1,51 +1,322 @@
 package mock
 
 import (
+	"io/fs"
 	"regexp"
 	"text/template"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/vektah/gqlparser/v2/ast"
+	"google.golang.org/grpc/codes"
 )
 
 /*
 MatchRequest configuration model for a http request
 */
 type MatchRequest struct {
-	Host       string            `yaml:"host" json:"host"`
-	Method     string            `yaml:"method" json:"method"`
-	Path       string            `yaml:"path" json:"path"`
-	Query      map[string]string `yaml:"query" json:"query"`
-	Headers    map[string]string `yaml:"headers" json:"headers"`
-	Body       string            `yaml:"body" json:"body"`
+	Host       string            `yaml:"host,omitempty" json:"host"`
+	Method     string            `yaml:"method,omitempty" json:"method"`
+	Path       string            `yaml:"path,omitempty" json:"path"`
+	Query      map[string]string `yaml:"query,omitempty" json:"query"`
+	Headers    map[string]string `yaml:"headers,omitempty" json:"headers"`
+	Body       string            `yaml:"body,omitempty" json:"body"`
 	BodyRegexp *regexp.Regexp    `yaml:"-" json:"-" `
+	GraphQL    *GraphQLMatch     `yaml:"graphql,omitempty" json:"graphql"`
+	ClientCert *ClientCertMatch  `yaml:"clientCert,omitempty" json:"clientCert"`
+}
+
+/*
+ClientCertMatch configuration model for matching the tls client certificate of a request
+*/
+type ClientCertMatch struct {
+	CommonName string `yaml:"commonName,omitempty" json:"commonName"`
+	SAN        string `yaml:"san,omitempty" json:"san"`
+	Issuer     string `yaml:"issuer,omitempty" json:"issuer"`
+}
+
+/*
+GraphQLMatch configuration model for matching a graphql request
+*/
+type GraphQLMatch struct {
+	OperationName   string                 `yaml:"operationName,omitempty" json:"operationName"`
+	OperationType   string                 `yaml:"operationType,omitempty" json:"operationType"`
+	Variables       map[string]interface{} `yaml:"variables,omitempty" json:"variables"`
+	Query           string                 `yaml:"query,omitempty" json:"query"`
+	SchemaFile      string                 `yaml:"schemaFile,omitempty" json:"schemaFile"`
+	NormalizedQuery string                 `yaml:"-" json:"-"`
+	Schema          *ast.Schema            `yaml:"-" json:"-"`
 }
 
 /*
 Response configuration model for a http response
 */
 type Response struct {
-	Template     *template.Template `yaml:"-" json:"-"`
-	StatusCode   string             `yaml:"statusCode" json:"statusCode"`
-	Headers      string             `yaml:"headers" json:"headers"`
-	Body         string             `yaml:"body" json:"body"`
-	BodyFilename string             `yaml:"bodyFilename" json:"bodyFilename"`
+	Template       *template.Template `yaml:"-" json:"-"`
+	StatusCode     string             `yaml:"statusCode,omitempty" json:"statusCode"`
+	Headers        string             `yaml:"headers,omitempty" json:"headers"`
+	Body           string             `yaml:"body,omitempty" json:"body"`
+	BodyFilename   string             `yaml:"bodyFilename,omitempty" json:"bodyFilename"`
+	BodyFile       string             `yaml:"bodyFile,omitempty" json:"bodyFile"`
+	BodyBase64     string             `yaml:"bodyBase64,omitempty" json:"bodyBase64"`
+	RawBody        []byte             `yaml:"-" json:"-"`
+	DefaultHeaders string             `yaml:"-" json:"-"`
+	Stream         *Stream            `yaml:"stream,omitempty" json:"stream"`
+	WebSocket      *WebSocket         `yaml:"websocket,omitempty" json:"websocket"`
+	Trailers       string             `yaml:"trailers,omitempty" json:"trailers"`
+	Informational  []*Informational   `yaml:"informational,omitempty" json:"informational"`
+}
+
+/*
+Informational configuration model for an informational response, e.g. '103 Early Hints', which is sent before the response
+*/
+type Informational struct {
+	StatusCode int               `yaml:"statusCode,omitempty" json:"statusCode"`
+	Headers    map[string]string `yaml:"headers,omitempty" json:"headers"`
+}
+
+/*
+StreamEvent configuration model for a server-sent event
+*/
+type StreamEvent struct {
+	Event         string        `yaml:"event,omitempty" json:"event"`
+	ID            string        `yaml:"id,omitempty" json:"id"`
+	Data          string        `yaml:"data,omitempty" json:"data"`
+	Retry         int           `yaml:"retry,omitempty" json:"retry"`
+	Delay         string        `yaml:"delay,omitempty" json:"delay"`
+	DelayDuration time.Duration `yaml:"-" json:"-"`
+}
+
+/*
+Stream configuration model for a response which is sent as a stream of server-sent events
+*/
+type Stream struct {
+	Events []*StreamEvent `yaml:"events,omitempty" json:"events"`
+	Loop   bool           `yaml:"loop,omitempty" json:"loop"`
+}
+
+/*
+WebSocketExpect configuration model for matching an inbound websocket message
+*/
+type WebSocketExpect struct {
+	Body       string            `yaml:"body,omitempty" json:"body"`
+	JSON       map[string]string `yaml:"json,omitempty" json:"json"`
+	BodyRegexp *regexp.Regexp    `yaml:"-" json:"-"`
+}
+
+/*
+WebSocketClose configuration model for closing a websocket connection
+*/
+type WebSocketClose struct {
+	Code   int    `yaml:"code,omitempty" json:"code"`
+	Reason string `yaml:"reason,omitempty" json:"reason"`
+}
+
+/*
+WebSocketStep configuration model for a step of a websocket script
+*/
+type WebSocketStep struct {
+	Expect *WebSocketExpect `yaml:"expect,omitempty" json:"expect"`
+	Reply  []string         `yaml:"reply,omitempty" json:"reply"`
+	Close  *WebSocketClose  `yaml:"close,omitempty" json:"close"`
+}
+
+/*
+WebSocketPush configuration model for a message which is sent periodically by the server
+*/
+type WebSocketPush struct {
+	Interval         string        `yaml:"interval,omitempty" json:"interval"`
+	Message          string        `yaml:"message,omitempty" json:"message"`
+	Count            int           `yaml:"count,omitempty" json:"count"`
+	IntervalDuration time.Duration `yaml:"-" json:"-"`
+}
+
+/*
+WebSocket configuration model for a response which upgrades the connection to a websocket
+*/
+type WebSocket struct {
+	Script  []*WebSocketStep `yaml:"script,omitempty" json:"script"`
+	Push    []*WebSocketPush `yaml:"push,omitempty" json:"push"`
+	Origins []string         `yaml:"origins,omitempty" json:"origins"`
 }
 
 /*
 Endpoint configuration model for a mock endpoint
 */
 type Endpoint struct {
-	ID       string        `yaml:"id" json:"id"`
-	Mock     *Mock         `yaml:"-" json:"mock" `
-	Prio     int           `yaml:"prio" json:"prio"`
-	Request  *MatchRequest `yaml:"request" json:"request"`
-	Response *Response     `yaml:"response" json:"response"`
+	ID        string        `yaml:"id,omitempty" json:"id"`
+	Mock      *Mock         `yaml:"-" json:"mock" `
+	Prio      int           `yaml:"prio,omitempty" json:"prio"`
+	Request   *MatchRequest `yaml:"request,omitempty" json:"request"`
+	Response  *Response     `yaml:"response,omitempty" json:"response"`
+	Callbacks []*Callback   `yaml:"callbacks,omitempty" json:"callbacks"`
+}
+
+/*
+CallbackRetry configuration model for repeating a callback which failed or wasn't answered with a 2xx status
+*/
+type CallbackRetry struct {
+	Attempts        int           `yaml:"attempts,omitempty" json:"attempts"`
+	Backoff         string        `yaml:"backoff,omitempty" json:"backoff"`
+	BackoffDuration time.Duration `yaml:"-" json:"-"`
+}
+
+/*
+Callback configuration model for an http request which is sent after the response of a matched request
+*/
+type Callback struct {
+	ID            string         `yaml:"id,omitempty" json:"id"`
+	URL           string         `yaml:"url,omitempty" json:"url"`
+	Method        string         `yaml:"method,omitempty" json:"method"`
+	Headers       string         `yaml:"headers,omitempty" json:"headers"`
+	Body          string         `yaml:"body,omitempty" json:"body"`
+	Delay         string         `yaml:"delay,omitempty" json:"delay"`
+	Retry         *CallbackRetry `yaml:"retry,omitempty" json:"retry"`
+	DelayDuration time.Duration  `yaml:"-" json:"-"`
+}
+
+/*
+GrpcMatchRequest configuration model for a grpc call
+*/
+type GrpcMatchRequest struct {
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata"`
+	Fields   map[string]string `yaml:"fields,omitempty" json:"fields"`
+}
+
+/*
+GrpcStreamMessage configuration model for a message of a server-streaming grpc response
+*/
+type GrpcStreamMessage struct {
+	Message       string        `yaml:"message,omitempty" json:"message"`
+	Delay         string        `yaml:"delay,omitempty" json:"delay"`
+	DelayDuration time.Duration `yaml:"-" json:"-"`
+}
+
+/*
+GrpcResponse configuration model for a grpc response
+*/
+type GrpcResponse struct {
+	Template      *template.Template   `yaml:"-" json:"-"`
+	Status        string               `yaml:"status,omitempty" json:"status"`
+	StatusMessage string               `yaml:"statusMessage,omitempty" json:"statusMessage"`
+	Metadata      string               `yaml:"metadata,omitempty" json:"metadata"`
+	Message       string               `yaml:"message,omitempty" json:"message"`
+	Stream        []*GrpcStreamMessage `yaml:"stream,omitempty" json:"stream"`
+	StatusCode    codes.Code           `yaml:"-" json:"-"`
+}
+
+/*
+GrpcEndpoint configuration model for a mock endpoint of a grpc method
+*/
+type GrpcEndpoint struct {
+	ID       string            `yaml:"id,omitempty" json:"id"`
+	Prio     int               `yaml:"prio,omitempty" json:"prio"`
+	Method   string            `yaml:"method,omitempty" json:"method"`
+	Request  *GrpcMatchRequest `yaml:"request,omitempty" json:"request"`
+	Response *GrpcResponse     `yaml:"response,omitempty" json:"response"`
+}
+
+/*
+SocketMatchRequest configuration model for matching a message received by a tcp or udp endpoint
+*/
+type SocketMatchRequest struct {
+	Line          string         `yaml:"line,omitempty" json:"line"`
+	Regexp        string         `yaml:"regexp,omitempty" json:"regexp"`
+	HexPrefix     string         `yaml:"hexPrefix,omitempty" json:"hexPrefix"`
+	MessageRegexp *regexp.Regexp `yaml:"-" json:"-"`
+	Prefix        []byte         `yaml:"-" json:"-"`
+}
+
+/*
+SocketResponse configuration model for the reply of a tcp or udp endpoint
+*/
+type SocketResponse struct {
+	Template *template.Template `yaml:"-" json:"-"`
+	Body     string             `yaml:"body,omitempty" json:"body"`
+	Close    bool               `yaml:"close,omitempty" json:"close"`
+}
+
+/*
+SocketEndpoint configuration model for a mock endpoint which listens for tcp connections or udp datagrams on a port
+*/
+type SocketEndpoint struct {
+	ID       string              `yaml:"id,omitempty" json:"id"`
+	Prio     int                 `yaml:"prio,omitempty" json:"prio"`
+	Protocol string              `yaml:"protocol,omitempty" json:"protocol"`
+	Port     int                 `yaml:"port,omitempty" json:"port"`
+	Framing  string              `yaml:"framing,omitempty" json:"framing"`
+	Request  *SocketMatchRequest `yaml:"request,omitempty" json:"request"`
+	Response *SocketResponse     `yaml:"response,omitempty" json:"response"`
+}
+
+/*
+Job configuration model for a template which runs on a cron or interval schedule, e.g. for changing the kvstore over time
+*/
+type Job struct {
+	Template  *template.Template `yaml:"-" json:"-"`
+	ID        string             `yaml:"id,omitempty" json:"id"`
+	Cron      string             `yaml:"cron,omitempty" json:"cron"`
+	Interval  string             `yaml:"interval,omitempty" json:"interval"`
+	Count     int                `yaml:"count,omitempty" json:"count"`
+	Run       string             `yaml:"run,omitempty" json:"run"`
+	Callbacks []*Callback        `yaml:"callbacks,omitempty" json:"callbacks"`
+	Schedule  cron.Schedule      `yaml:"-" json:"-"`
+}
+
+/*
+RequestDefaults configuration model for request attributes which are merged into every endpoint of a mock file
+*/
+type RequestDefaults struct {
+	Host    string            `yaml:"host,omitempty" json:"host"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers"`
+}
+
+/*
+ResponseDefaults configuration model for response attributes which are merged into every endpoint of a mock file
+*/
+type ResponseDefaults struct {
+	Headers string `yaml:"headers,omitempty" json:"headers"`
+}
+
+/*
+Defaults configuration model for attributes which are merged into every endpoint of a mock file
+*/
+type Defaults struct {
+	Prio     int               `yaml:"prio,omitempty" json:"prio"`
+	Request  *RequestDefaults  `yaml:"request,omitempty" json:"request"`
+	Response *ResponseDefaults `yaml:"response,omitempty" json:"response"`
+}
+
+/*
+MismatchResponse configuration model for the response of requests which didn't match an endpoint, it applies to the requests
+whose path starts with the path prefix
+*/
+type MismatchResponse struct {
+	Template   *template.Template `yaml:"-" json:"-"`
+	Mock       *Mock              `yaml:"-" json:"-"`
+	PathPrefix string             `yaml:"pathPrefix,omitempty" json:"pathPrefix"`
+	StatusCode string             `yaml:"statusCode,omitempty" json:"statusCode"`
+	Headers    string             `yaml:"headers,omitempty" json:"headers"`
+	Body       string             `yaml:"body,omitempty" json:"body"`
 }
 
 /*
 Mock configuration model for a mock file
 */
 type Mock struct {
-	Name      string      `yaml:"name" json:"name"`
-	Endpoints []*Endpoint `yaml:"endpoints" json:"-"`
+	Name       string              `yaml:"name,omitempty" json:"name"`
+	Include    []string            `yaml:"include,omitempty" json:"include"`
+	Defaults   *Defaults           `yaml:"defaults,omitempty" json:"defaults"`
+	Templates  map[string]string   `yaml:"templates,omitempty" json:"templates"`
+	Endpoints  []*Endpoint         `yaml:"endpoints,omitempty" json:"-"`
+	Grpc       []*GrpcEndpoint     `yaml:"grpc,omitempty" json:"-"`
+	Sockets    []*SocketEndpoint   `yaml:"sockets,omitempty" json:"-"`
+	Jobs       []*Job              `yaml:"jobs,omitempty" json:"-"`
+	Mismatches []*MismatchResponse `yaml:"mismatches,omitempty" json:"-"`
+	PathPrefix string              `yaml:"-" json:"pathPrefix"`
+	FS         fs.FS               `yaml:"-" json:"-"`
+	// includedFiles are the names of the mockfiles which are included directly or indirectly
+	includedFiles []string
 }
 
 type epSearchNode struct {
@@ -0,0 +1,173 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+)
+
+const maxNearestEndpoints = 3
+
+/*
+nearestEndpoints returns the endpoints which came closest to a request which didn't match, the endpoints are ordered
+by the edit distance of the paths and the number of failed matchers
+*/
+func (r *RequestHandler) nearestEndpoints(request *http.Request, actualRequest *matches.ActualRequest) []*matches.NearestEndpoint {
+	var nearestEndpoints []*matches.NearestEndpoint
+	for _, endpoint := range r.endpoints {
+		nearestEndpoint := r.compareEndpoint(endpoint, request, actualRequest)
+		if len(nearestEndpoint.Differences) > 0 {
+			nearestEndpoints = append(nearestEndpoints, nearestEndpoint)
+		}
+	}
+	sort.SliceStable(nearestEndpoints, func(i, j int) bool {
+		if nearestEndpoints[i].PathDistance != nearestEndpoints[j].PathDistance {
+			return nearestEndpoints[i].PathDistance < nearestEndpoints[j].PathDistance
+		}
+		return len(nearestEndpoints[i].Differences) < len(nearestEndpoints[j].Differences)
+	})
+	if len(nearestEndpoints) > maxNearestEndpoints {
+		nearestEndpoints = nearestEndpoints[:maxNearestEndpoints]
+	}
+	return nearestEndpoints
+}
+
+func (r *RequestHandler) compareEndpoint(endpoint *Endpoint, request *http.Request, actualRequest *matches.ActualRequest) *matches.NearestEndpoint {
+	endpointPath := endpoint.Request.Path
+	if endpoint.Mock != nil {
+		endpointPath = endpoint.Mock.PathPrefix + endpointPath
+	}
+	nearestEndpoint := &matches.NearestEndpoint{EndpointID: endpoint.ID, Method: endpoint.Request.Method, Path: endpointPath,
+		PathDistance: pathDistance(endpointPath, request.URL.Path), Differences: []*matches.Difference{}}
+	addDifference := func(matcher, expected, actual string) {
+		nearestEndpoint.Differences = append(nearestEndpoint.Differences, &matches.Difference{Matcher: matcher, Expected: expected, Actual: actual})
+	}
+	if nearestEndpoint.PathDistance > 0 {
+		addDifference("path", endpointPath, request.URL.Path)
+	}
+	if endpoint.Request.Method != request.Method {
+		addDifference("method", endpoint.Request.Method, request.Method)
+	}
+	if host := strings.Split(request.Host, ":")[0]; len(endpoint.Request.Host) > 0 && endpoint.Request.Host != host {
+		addDifference("host", endpoint.Request.Host, host)
+	}
+	for _, key := range sortedKeys(endpoint.Request.Query) {
+		if actual := request.URL.Query().Get(key); actual != endpoint.Request.Query[key] {
+			addDifference("query", key+"="+endpoint.Request.Query[key], key+"="+actual)
+		}
+	}
+	for _, key := range sortedKeys(endpoint.Request.Headers) {
+		if actual := request.Header.Get(key); actual != endpoint.Request.Headers[key] {
+			addDifference("header", key+": "+endpoint.Request.Headers[key], key+": "+actual)
+		}
+	}
+	if !r.matchBody(endpoint.Request, request) {
+		addDifference("body", endpoint.Request.Body, actualRequest.Body)
+	}
+	if matched, details := r.matchGraphQL(endpoint.Request, request); !matched {
+		addDifference("graphql", jsonString(endpoint.Request.GraphQL), details)
+	}
+	if matched, details := matchClientCert(endpoint.Request.ClientCert, request); !matched {
+		addDifference("clientCert", jsonString(endpoint.Request.ClientCert), details)
+	}
+	return nearestEndpoint
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonString(config interface{}) string {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Sprintf("%v", config)
+	}
+	return string(data)
+}
+
+/*
+pathDistance computes the edit distance of the segments of an endpoint path and a request path, a segment costs the
+edit distance of its characters and a missing or superfluous segment its length including the slash.
+Path parameters and '*' match any segment, '**' matches any number of segments.
+*/
+func pathDistance(endpointPath, requestPath string) int {
+	patternSegments := strings.Split(endpointPath, "/")[1:]
+	requestSegments := strings.Split(requestPath, "/")[1:]
+	segmentCost := func(segment string) int {
+		if segment == "**" {
+			return 0
+		}
+		return len(segment) + 1
+	}
+	distances := make([][]int, len(patternSegments)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(requestSegments)+1)
+		if i > 0 {
+			distances[i][0] = distances[i-1][0] + segmentCost(patternSegments[i-1])
+		}
+	}
+	for j := 1; j <= len(requestSegments); j++ {
+		distances[0][j] = distances[0][j-1] + len(requestSegments[j-1]) + 1
+	}
+	for i := 1; i <= len(patternSegments); i++ {
+		pattern := patternSegments[i-1]
+		for j := 1; j <= len(requestSegments); j++ {
+			distance := distances[i-1][j-1] + segmentDistance(pattern, requestSegments[j-1])
+			if deletion := distances[i-1][j] + segmentCost(pattern); deletion < distance {
+				distance = deletion
+			}
+			insertion := distances[i][j-1] + len(requestSegments[j-1]) + 1
+			if pattern == "**" {
+				insertion = distances[i][j-1]
+			}
+			if insertion < distance {
+				distance = insertion
+			}
+			distances[i][j] = distance
+		}
+	}
+	return distances[len(patternSegments)][len(requestSegments)]
+}
+
+func segmentDistance(pattern, segment string) int {
+	if pattern == "*" || pattern == "**" || strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}") {
+		return 0
+	}
+	return levenshtein(pattern, segment)
+}
+
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current := make([]int, len(runesB)+1)
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+	return previous[len(runesB)]
+}
@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathDistance(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		endpointPath string
+		requestPath  string
+		distance     int
+	}{
+		{"equal", "/minimal", "/minimal", 0},
+		{"typo", "/minimal", "/minimol", 1},
+		{"superfluous chars", "/minimal", "/minimalwrong", 5},
+		{"superfluous segment", "/minimal", "/minimal/foo", 4},
+		{"missing segment", "/minimal/foo", "/minimal", 4},
+		{"path param", "/users/{id}/orders", "/users/42/orders", 0},
+		{"path param typo", "/users/{id}/orders", "/users/42/order", 1},
+		{"wildcard", "/users/*", "/users/42", 0},
+		{"all match wildcard", "/files/**/raw", "/files/a/b/c/raw", 0},
+		{"all match wildcard empty", "/files/**/raw", "/files/raw", 0},
+		{"all match wildcard typo", "/files/**/raw", "/files/a/b/row", 1},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.distance, pathDistance(testCase.endpointPath, testCase.requestPath))
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("", ""))
+	assert.Equal(t, 3, levenshtein("", "abc"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 2, levenshtein("straße", "strasse"))
+}
+
+func TestMockRequestHandler_serving_nearestEndpoints(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	mockRouter := mux.NewRouter()
+	mockRequestHandler.AddRoutes(mockRouter)
+	server := httptest.NewServer(mockRouter)
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/maximal?firstQueryParam=value1&secondQueryParam=wrong", strings.NewReader(`{ "mybody": "is min" }`))
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	response, err = http.Get(server.URL + "/minimol")
+	assert.NoError(t, err)
+	response.Body.Close()
+
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	if assert.Len(t, mismatches, 2) {
+		nearestEndpoints := mismatches[0].NearestEndpoints
+		if assert.NotEmpty(t, nearestEndpoints) {
+			assert.Equal(t, &matches.NearestEndpoint{EndpointID: "maximal", Method: http.MethodPost, Path: "/maximal", PathDistance: 0,
+				Differences: []*matches.Difference{
+					{Matcher: "query", Expected: "secondQueryParam=value2", Actual: "secondQueryParam=wrong"},
+					{Matcher: "header", Expected: "Myheader: myheaderValue", Actual: "Myheader: "},
+					{Matcher: "body", Expected: "{\n  \"mybody\": \"is max\"\n}\n", Actual: `{ "mybody": "is min" }`},
+				}}, nearestEndpoints[0])
+		}
+		nearestEndpoints = mismatches[1].NearestEndpoints
+		if assert.NotEmpty(t, nearestEndpoints) {
+			assert.Equal(t, "minimal", nearestEndpoints[0].EndpointID)
+			assert.Equal(t, 1, nearestEndpoints[0].PathDistance)
+			assert.Equal(t, []*matches.Difference{{Matcher: "path", Expected: "/minimal", Actual: "/minimol"}}, nearestEndpoints[0].Differences)
+		}
+		assert.LessOrEqual(t, len(nearestEndpoints), maxNearestEndpoints)
+	}
+}
@@ -0,0 +1,140 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/go-http-utils/headers"
+)
+
+func hasRawBody(response *Response) bool {
+	return len(response.BodyFile) > 0 || len(response.BodyBase64) > 0
+}
+
+/*
+initRawBody validates the raw body of a response, a raw body is not processed by the template engine
+*/
+func (r *RequestHandler) initRawBody(endpoint *Endpoint) error {
+	definedBodies := 0
+	for _, body := range []string{endpoint.Response.Body, endpoint.Response.BodyFilename, endpoint.Response.BodyFile, endpoint.Response.BodyBase64} {
+		if len(body) > 0 {
+			definedBodies++
+		}
+	}
+	if definedBodies > 1 {
+		return fmt.Errorf("error parsing endpoint id '%s' , only one of response.body, response.bodyFilename, response.bodyFile and response.bodyBase64 can be defined", endpoint.ID)
+	}
+	if len(endpoint.Response.BodyBase64) > 0 {
+		rawBody, err := base64.StdEncoding.DecodeString(endpoint.Response.BodyBase64)
+		if err != nil {
+			return fmt.Errorf("error decoding response.bodyBase64 of endpoint id '%s': %v", endpoint.ID, err)
+		}
+		endpoint.Response.RawBody = rawBody
+		return nil
+	}
+	if len(endpoint.Response.BodyFile) > 0 {
+		if _, err := fs.Stat(endpoint.Mock.FS, endpoint.Response.BodyFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+openRawBody returns the raw body of a response with its name, modification time and ETag
+*/
+func openRawBody(endpoint *Endpoint) (io.ReadSeeker, string, time.Time, string, error) {
+	if endpoint.Response.RawBody != nil {
+		hash := sha256.Sum256(endpoint.Response.RawBody)
+		return bytes.NewReader(endpoint.Response.RawBody), "", time.Time{}, `"` + hex.EncodeToString(hash[:16]) + `"`, nil
+	}
+	file, err := endpoint.Mock.FS.Open(endpoint.Response.BodyFile)
+	if err != nil {
+		return nil, "", time.Time{}, "", err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, "", time.Time{}, "", err
+	}
+	etag := fmt.Sprintf(`"%x-%x"`, info.Size(), info.ModTime().UnixNano())
+	if readSeeker, ok := file.(io.ReadSeeker); ok {
+		return readSeeker, path.Base(endpoint.Response.BodyFile), info.ModTime(), etag, nil
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", time.Time{}, "", err
+	}
+	return bytes.NewReader(content), path.Base(endpoint.Response.BodyFile), info.ModTime(), etag, nil
+}
+
+/*
+serveRawBody writes the raw body of a response, for status code 200 range and conditional requests are supported
+*/
+func (r *RequestHandler) serveRawBody(writer http.ResponseWriter, request *http.Request, endpoint *Endpoint, responseStatus int) error {
+	content, name, modTime, etag, err := openRawBody(endpoint)
+	if err != nil {
+		return err
+	}
+	if closer, ok := content.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if len(writer.Header().Get(headers.ContentType)) == 0 {
+		if contentType := mime.TypeByExtension(path.Ext(name)); len(contentType) > 0 {
+			writer.Header().Set(headers.ContentType, contentType)
+		}
+	}
+	if len(writer.Header().Get(headers.ETag)) == 0 {
+		writer.Header().Set(headers.ETag, etag)
+	}
+	if responseStatus == http.StatusOK {
+		http.ServeContent(writer, request, name, modTime, content)
+		return nil
+	}
+	if len(writer.Header().Get(headers.ContentType)) == 0 {
+		sniffed := make([]byte, 512)
+		n, _ := io.ReadFull(content, sniffed)
+		writer.Header().Set(headers.ContentType, http.DetectContentType(sniffed[:n]))
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	writer.WriteHeader(responseStatus)
+	_, err = io.Copy(writer, content)
+	return err
+}
+
+/*
+bodyRecorder records the status code and the first bytes of a response body up to limit+1, so that a truncation can be detected
+*/
+type bodyRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	limit      int
+	body       bytes.Buffer
+}
+
+func (b *bodyRecorder) WriteHeader(statusCode int) {
+	b.statusCode = statusCode
+	b.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (b *bodyRecorder) Write(data []byte) (int, error) {
+	if remaining := b.limit + 1 - b.body.Len(); remaining > 0 {
+		if remaining > len(data) {
+			remaining = len(data)
+		}
+		b.body.Write(data[:remaining])
+	}
+	return b.ResponseWriter.Write(data)
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockRequestHandler_serving_bodyFile(t *testing.T) {
+	pixel, err := os.ReadFile("../../test/mocks/pixel.png")
+	assert.NoError(t, err)
+	var etag string
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/binary/file", testutil.CreateHeader(), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "image/png", response.Header.Get("Content-Type"))
+			assert.Equal(t, "bytes", response.Header.Get("Accept-Ranges"))
+			assert.Equal(t, string(pixel), responseBody)
+			etag = response.Header.Get("ETag")
+			assert.NotEmpty(t, etag)
+		}))
+
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/binary/file", testutil.CreateHeader().WithKeyValue("If-None-Match", etag), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusNotModified, response.StatusCode)
+			assert.Empty(t, responseBody)
+		}))
+
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/binary/file", testutil.CreateHeader().WithKeyValue("Range", "bytes=1-3"), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusPartialContent, response.StatusCode)
+			assert.Equal(t, "PNG", responseBody)
+		}))
+}
+
+func TestMockRequestHandler_serving_bodyBase64(t *testing.T) {
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/binary/base64", testutil.CreateHeader(), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "application/octet-stream", response.Header.Get("Content-Type"))
+			assert.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, []byte(responseBody))
+		}))
+}
+
+func TestMockRequestHandler_serving_bodyFileWithStatus(t *testing.T) {
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/binary/notfound", testutil.CreateHeader().WithKeyValue("Range", "bytes=1-3"), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusNotFound, response.StatusCode)
+			assert.Equal(t, "image/png", response.Header.Get("Content-Type"))
+			assert.Len(t, responseBody, 70)
+		}))
+}
+
+func TestMockRequestHandler_initRawBody_errors(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	err := mockRequestHandler.initRawBody(&Endpoint{ID: "double", Response: &Response{Body: "body", BodyBase64: "AAE="}})
+	assert.ErrorContains(t, err, "only one of response.body, response.bodyFilename, response.bodyFile and response.bodyBase64 can be defined")
+	err = mockRequestHandler.initRawBody(&Endpoint{ID: "wrongBase64", Response: &Response{BodyBase64: "not base64"}})
+	assert.ErrorContains(t, err, "error decoding response.bodyBase64 of endpoint id 'wrongBase64'")
+	err = mockRequestHandler.initRawBody(&Endpoint{ID: "notExists", Mock: &Mock{FS: localFS("../../test/mocks")}, Response: &Response{BodyFile: "notexists.png"}})
+	assert.ErrorContains(t, err, "no such file or directory")
+}
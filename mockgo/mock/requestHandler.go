@@ -2,16 +2,18 @@ package mock
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
 	"net/http"
-	"os"
-	"path/filepath"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -30,49 +32,85 @@ import (
 const templateResponseBody = "responseBody"
 const templateResponseStatus = "responseStatus"
 const templateResponseHeader = "responseHeader"
+const templateResponseDefaultHeader = "responseDefaultHeader"
+const templateResponseTrailer = "responseTrailer"
 
 const headerKeyEndpointID = "endpoint-Id"
 
 const reloadPath = "/__/reload"
 
 type responseTemplateData struct {
-	RequestPathParams   map[string]string
-	RequestQueryParams  map[string]string
-	RequestHeader       map[string]string
-	KVStore             map[string]interface{}
-	RequestURL          string
-	RequestPath         string
-	RequestHost         string
-	RequestBody         string
-	RequestBodyJSONData map[string]interface{}
-	ResponseStatus      int
+	RequestPathParams        map[string]string
+	RequestQueryParams       map[string]string
+	RequestHeader            map[string]string
+	KVStore                  map[string]interface{}
+	RequestURL               string
+	RequestPath              string
+	RequestHost              string
+	RequestBody              string
+	RequestBodyJSONData      map[string]interface{}
+	ResponseStatus           int
+	StreamedEvents           int
+	WebSocketMessage         string
+	WebSocketMessageJSONData map[string]interface{}
+	PushedMessages           int
+	GraphQLOperation         *GraphQLOperation
+	GraphQLVariables         map[string]interface{}
+	RequestClientCert        *ClientCert
+	Mismatch                 *matches.Mismatch
 }
 
 /*
 RequestHandler implements an http server for mock endpoints
 */
 type RequestHandler struct {
-	pathPrefix      string
-	mockDir         string
-	mockFilepattern string
-	logger          *zap.Logger
-	EpSearchNode    *epSearchNode
-	matchstore      matches.Matchstore
-	funcMap         template.FuncMap
+	pathPrefix        string
+	mockDir           string
+	mockFilepattern   string
+	mockDirRecursive  bool
+	mockDirs          []*MockDir
+	extraMockDirs     []*MockDir
+	logger            *zap.Logger
+	EpSearchNode      *epSearchNode
+	matchstore        matches.Matchstore
+	funcMap           template.FuncMap
+	envFuncMap        template.FuncMap
+	envAllowlist      []string
+	grpcHandler       *GrpcHandler
+	socketHandler     *SocketHandler
+	jobScheduler      *JobScheduler
+	reloadWith        []*RequestHandler
+	callbackClient    *http.Client
+	callbackCtx       context.Context
+	cancelCallbacks   context.CancelFunc
+	callbacksRunning  sync.WaitGroup
+	bodyLimit         int
+	sourceSizeLimit   int64
+	rejectGrpc        bool
+	endpoints         []*Endpoint
+	mismatchResponses []*MismatchResponse
 }
 
 /*
 NewRequestHandler creates an instance of RequestHandler
 */
-func NewRequestHandler(pathPrefix string, mockDir, mockFilepattern string, matchstore matches.Matchstore, funcMap template.FuncMap, logLevel string) *RequestHandler {
+func NewRequestHandler(pathPrefix string, mockDir, mockFilepattern string, mockDirRecursive bool, matchstore matches.Matchstore, funcMap template.FuncMap, logLevel string) *RequestHandler {
+	callbackCtx, cancelCallbacks := context.WithCancel(context.Background())
 	mockRouter := &RequestHandler{
-		pathPrefix:      pathPrefix,
-		mockDir:         mockDir,
-		mockFilepattern: mockFilepattern,
-		logger:          util.CreateLogger(logLevel),
-		EpSearchNode:    &epSearchNode{},
-		matchstore:      matchstore,
-		funcMap:         funcMap,
+		pathPrefix:       pathPrefix,
+		mockDir:          mockDir,
+		mockFilepattern:  mockFilepattern,
+		mockDirRecursive: mockDirRecursive,
+		logger:           util.CreateLogger(logLevel),
+		EpSearchNode:     &epSearchNode{},
+		matchstore:       matchstore,
+		funcMap:          funcMap,
+		envFuncMap:       NewEnvTemplateFuncMap(nil, nil),
+		callbackClient:   &http.Client{Timeout: 10 * time.Second},
+		callbackCtx:      callbackCtx,
+		cancelCallbacks:  cancelCallbacks,
+		bodyLimit:        matches.DefaultBodyLimit,
+		sourceSizeLimit:  DefaultSourceSizeLimit,
 	}
 	return mockRouter
 }
@@ -104,57 +142,190 @@ func RegisterMetrics() error {
 	return nil
 }
 
+/*
+SetTemplateAllowlists defines which environment variables and files can be read with the template functions 'env' and 'file',
+the environment variables are also the ones which are expanded in the mockfiles
+*/
+func (r *RequestHandler) SetTemplateAllowlists(envAllowlist, fileAllowlist []string) {
+	r.envFuncMap = NewEnvTemplateFuncMap(envAllowlist, fileAllowlist)
+	r.envAllowlist = envAllowlist
+}
+
+/*
+SetMatchesBodyLimit defines the maximum number of bytes of request and response bodies which are stored in the matches
+*/
+func (r *RequestHandler) SetMatchesBodyLimit(limit int) {
+	r.bodyLimit = limit
+}
+
+/*
+SetSourceSizeLimit defines the maximum number of bytes of a downloaded mockfile or bundle and of the uncompressed content of a bundle
+*/
+func (r *RequestHandler) SetSourceSizeLimit(limit int64) {
+	r.sourceSizeLimit = limit
+}
+
+/*
+AddMockDir adds a MockDir, e.g. with a Source for embedded mockfiles, which is loaded in addition to the mockDir
+*/
+func (r *RequestHandler) AddMockDir(mockDir *MockDir) {
+	r.extraMockDirs = append(r.extraMockDirs, mockDir)
+}
+
+/*
+readMocks reads the mockfiles of all mock dirs and applies their defaults
+*/
+func (r *RequestHandler) readMocks() ([]*Mock, error) {
+	if r.mockDirs == nil && (len(r.mockDir) > 0 || len(r.extraMockDirs) == 0) {
+		mockDirs, err := ParseMockDirs(r.mockDir, r.mockDirRecursive)
+		if err != nil {
+			return nil, err
+		}
+		r.mockDirs = mockDirs
+	}
+	var mockFiles []*mockFile
+	for _, mockDir := range append(r.mockDirs, r.extraMockDirs...) {
+		mockDirFiles, err := mockDir.findMockFiles(r.mockFilepattern, r.sourceSizeLimit)
+		if err != nil {
+			return nil, err
+		}
+		mockFiles = append(mockFiles, mockDirFiles...)
+	}
+	r.logger.Info(fmt.Sprintf("Found %v mock file(s):", len(mockFiles)))
+	var mocks []*Mock
+	var mockFileNames []string
+	for _, mockFile := range mockFiles {
+		mock, err := r.readMockFile(mockFile)
+		if err != nil {
+			return nil, err
+		}
+		mock.PathPrefix = mockFile.mockDir.PathPrefix
+		mock.FS = mockFile.fsys
+		applyDefaults(mock)
+		mocks = append(mocks, mock)
+		mockFileNames = append(mockFileNames, mockFile.name())
+	}
+	return r.withoutIncludedMocks(mocks, mockFileNames), nil
+}
+
 /*
 LoadFiles reads the mockfiles from the mockDir and creates the datamodel for serving mock endpoints for http requests
 */
 func (r *RequestHandler) LoadFiles() error {
 	tmpSearchNode := &epSearchNode{}
+	var endpoints []*Endpoint
 	endPointCounter := 0
-	mockFiles, err := walkMatch(r.mockDir, r.mockFilepattern)
+	mocks, err := r.readMocks()
 	if err != nil {
 		return err
 	}
-	r.logger.Info(fmt.Sprintf("Found %v mock file(s):", len(mockFiles)))
-	for _, mockFile := range mockFiles {
-		mock, err := r.readMockFile(mockFile)
-		if err != nil {
-			return err
-		}
+	templates, err := collectTemplates(mocks)
+	if err != nil {
+		return err
+	}
+	for _, mock := range mocks {
 		for _, endpoint := range mock.Endpoints {
 			endPointCounter++
 			if len(endpoint.ID) == 0 {
 				endpoint.ID = strconv.Itoa(endPointCounter)
 			}
 			endpoint.Mock = mock
-			err := r.initResponseTemplates(endpoint, r.funcMap)
+			err := r.initResponseTemplates(endpoint, r.funcMap, templates)
 			if err != nil {
 				r.logger.Error(fmt.Sprintf("Can't initialize response templates of endpoint id '%s', skipping endpoint ", endpoint.ID), zap.Error(err))
 				continue
 			}
 			r.registerEndpoint(endpoint, tmpSearchNode)
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	mismatchResponses, err := r.initMismatchResponses(mocks, templates)
+	if err != nil {
+		return err
+	}
+
+	if err := r.loadGrpc(mocks, templates); err != nil {
+		return err
+	}
+
+	if r.socketHandler != nil {
+		if err := r.socketHandler.load(mocks, templates); err != nil {
+			return err
+		}
+	}
+
+	if r.jobScheduler != nil {
+		if err := r.jobScheduler.load(mocks, templates); err != nil {
+			return err
 		}
 	}
 
 	r.EpSearchNode = tmpSearchNode
+	r.endpoints = endpoints
+	r.mismatchResponses = mismatchResponses
 	return nil
 }
 
-func (r *RequestHandler) readMockFile(mockFile string) (*Mock, error) {
-	r.logger.Info(fmt.Sprintf("Reading mock file '%s' ...", mockFile))
-	mockFileContent, err := os.ReadFile(mockFile)
+/*
+loadGrpc loads the grpc endpoints of the mocks, when grpc is rejected a mockfile with grpc endpoints fails the loading
+*/
+func (r *RequestHandler) loadGrpc(mocks []*Mock, templates map[string]string) error {
+	if r.rejectGrpc {
+		for _, mock := range mocks {
+			if len(mock.Grpc) > 0 {
+				return fmt.Errorf("grpc endpoints of mockfile '%s' are not supported, they are only served for the mockfiles of the grpc handler", mock.Name)
+			}
+		}
+	}
+	if r.grpcHandler == nil {
+		return nil
+	}
+	return r.grpcHandler.load(append(r.mockDirs, r.extraMockDirs...), mocks, templates)
+}
+
+/*
+RejectGrpc lets LoadFiles fail for mockfiles with grpc endpoints, e.g. for a request handler without a grpc handler
+*/
+func (r *RequestHandler) RejectGrpc() {
+	r.rejectGrpc = true
+}
+
+/*
+EndpointIDs returns the ids of the http endpoints which are loaded from the mockfiles
+*/
+func (r *RequestHandler) EndpointIDs() []string {
+	var endpointIDs []string
+	for _, endpoint := range r.endpoints {
+		endpointIDs = append(endpointIDs, endpoint.ID)
+	}
+	return endpointIDs
+}
+
+func (r *RequestHandler) readMockFile(mockFile *mockFile) (*Mock, error) {
+	return r.readMockFileIncludedBy(mockFile, nil)
+}
+
+func (r *RequestHandler) readMockFileIncludedBy(mockFile *mockFile, includedBy []string) (*Mock, error) {
+	r.logger.Info(fmt.Sprintf("Reading mock file '%s' ...", mockFile.name()))
+	mockFileContent, err := fs.ReadFile(mockFile.fsys, mockFile.path)
 	if err != nil {
 		return nil, err
 	}
+	mockFileContent, undefinedEnvVars := expandEnvVars(mockFileContent, lookupAllowedEnv(r.envAllowlist))
+	if len(undefinedEnvVars) > 0 {
+		r.logger.Warn(fmt.Sprintf("Undefined or not allowed environment variables in mock file '%s': %v", mockFile.name(), undefinedEnvVars))
+	}
 
 	var mock Mock
-	if strings.HasSuffix(mockFile, ".yaml") || strings.HasSuffix(mockFile, ".yml") {
+	if strings.HasSuffix(mockFile.path, ".yaml") || strings.HasSuffix(mockFile.path, ".yml") {
 		err = yaml.Unmarshal(mockFileContent, &mock)
 	}
 	if err != nil {
 		return nil, err
 	}
 	if len(mock.Name) == 0 {
-		mock.Name = filepath.Base(mockFile)
+		mock.Name = path.Base(mockFile.path)
 	}
 	for _, endpoint := range mock.Endpoints {
 		if len(endpoint.Request.Body) > 0 {
@@ -164,28 +335,42 @@ func (r *RequestHandler) readMockFile(mockFile string) (*Mock, error) {
 			}
 			endpoint.Request.BodyRegexp = bodyregexp
 		}
+		if endpoint.Request.GraphQL != nil {
+			if err := initGraphQLMatch(endpoint.Request.GraphQL, mockFile.fsys); err != nil {
+				return nil, fmt.Errorf("error parsing graphql of endpoint id '%s': %v", endpoint.ID, err)
+			}
+		}
+	}
+	if err := r.readIncludes(&mock, mockFile, includedBy); err != nil {
+		return nil, err
 	}
 	return &mock, nil
 }
 
-func (r *RequestHandler) initResponseTemplates(endpoint *Endpoint, funcMap template.FuncMap) error {
-	endpoint.Response.Template = template.New(endpoint.ID).Funcs(sprig.TxtFuncMap()).Funcs(funcMap)
-	body := ""
-	if len(endpoint.Response.Body) > 0 {
-		if len(endpoint.Response.BodyFilename) > 0 {
-			return fmt.Errorf("error parsing endpoint id '%s' , response.body and response.bodyFilename can't be defined both", endpoint.ID)
-		}
-		body = endpoint.Response.Body
-	} else {
-		if len(endpoint.Response.BodyFilename) > 0 {
-			bodyBytes, err := os.ReadFile(filepath.Join(r.mockDir, endpoint.Response.BodyFilename))
-			if err != nil {
-				return err
-			}
-			body = string(bodyBytes)
+func (r *RequestHandler) initResponseTemplates(endpoint *Endpoint, funcMap template.FuncMap, templates map[string]string) error {
+	endpoint.Response.Template = template.New(endpoint.ID).Funcs(sprig.TxtFuncMap()).Funcs(r.envFuncMap).Funcs(funcMap)
+	for name, text := range templates {
+		if _, err := endpoint.Response.Template.New(name).Parse(text); err != nil {
+			return err
 		}
 	}
-	_, err := endpoint.Response.Template.New(templateResponseBody).Parse(body)
+	if err := r.initRawBody(endpoint); err != nil {
+		return err
+	}
+	if err := r.initStream(endpoint); err != nil {
+		return err
+	}
+	if err := r.initWebSocket(endpoint); err != nil {
+		return err
+	}
+	if err := initCallbacks(endpoint.ID, endpoint.Callbacks, endpoint.Response.Template); err != nil {
+		return err
+	}
+	body, err := responseBody(endpoint)
+	if err != nil {
+		return err
+	}
+	_, err = endpoint.Response.Template.New(templateResponseBody).Parse(body)
 	if err != nil {
 		return err
 	}
@@ -202,6 +387,47 @@ func (r *RequestHandler) initResponseTemplates(endpoint *Endpoint, funcMap templ
 		return err
 	}
 
+	_, err = endpoint.Response.Template.New(templateResponseDefaultHeader).Parse(endpoint.Response.DefaultHeaders)
+	if err != nil {
+		return err
+	}
+
+	_, err = endpoint.Response.Template.New(templateResponseTrailer).Parse(endpoint.Response.Trailers)
+	if err != nil {
+		return err
+	}
+	return validateInformational(endpoint)
+}
+
+/*
+responseBody returns the body of the response or the content of the body file
+*/
+func responseBody(endpoint *Endpoint) (string, error) {
+	if len(endpoint.Response.Body) > 0 {
+		if len(endpoint.Response.BodyFilename) > 0 {
+			return "", fmt.Errorf("error parsing endpoint id '%s' , response.body and response.bodyFilename can't be defined both", endpoint.ID)
+		}
+		return endpoint.Response.Body, nil
+	}
+	if len(endpoint.Response.BodyFilename) > 0 {
+		bodyBytes, err := fs.ReadFile(endpoint.Mock.FS, endpoint.Response.BodyFilename)
+		if err != nil {
+			return "", err
+		}
+		return string(bodyBytes), nil
+	}
+	return "", nil
+}
+
+/*
+validateInformational checks that the informational responses have a status code 1xx other than 101
+*/
+func validateInformational(endpoint *Endpoint) error {
+	for _, informational := range endpoint.Response.Informational {
+		if informational.StatusCode < 100 || informational.StatusCode > 199 || informational.StatusCode == http.StatusSwitchingProtocols {
+			return fmt.Errorf("error parsing endpoint id '%s' , %d is not an informational status code", endpoint.ID, informational.StatusCode)
+		}
+	}
 	return nil
 }
 
@@ -209,34 +435,75 @@ func (r *RequestHandler) initResponseTemplates(endpoint *Endpoint, funcMap templ
 AddRoutes adds mux.Routes for the http API to a given mux.Router
 */
 func (r *RequestHandler) AddRoutes(router *mux.Router) {
-	var endPoint *Endpoint
-	var match *matches.Match
-	var requestPathParam map[string]string
-	var queryParams map[string]string
-	route := router.MatcherFunc(func(request *http.Request, routematch *mux.RouteMatch) bool {
-		if strings.HasPrefix(request.URL.Path, r.pathPrefix) {
+	r.AddMockRoutes(router, true)
+	r.AddAPIRoutes(router)
+}
+
+/*
+AddMockRoutes adds the route for serving the mock endpoints, requests with the api path prefix are excluded when excludeAPIPath is set.
+Requests which don't match an endpoint are served with the mismatch response of their path prefix, if there is one.
+*/
+func (r *RequestHandler) AddMockRoutes(router *mux.Router, excludeAPIPath bool) {
+	router.MatcherFunc(func(request *http.Request, routematch *mux.RouteMatch) bool {
+		if excludeAPIPath && strings.HasPrefix(request.URL.Path, r.pathPrefix) {
 			return false
 		}
-		endPoint, match, requestPathParam, queryParams = r.matchRequestToEndpoint(request)
-		return endPoint != nil
-	})
-	route.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		r.renderResponse(writer, request, endPoint, match, requestPathParam, queryParams)
+		endPoint, match, mismatch, requestPathParam, queryParams := r.matchRequestToEndpoint(request)
+		if endPoint != nil {
+			// the handler is bound to the request, so that concurrent requests don't share the result of the matching
+			routematch.Handler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				r.serveEndpoint(writer, request, endPoint, match, requestPathParam, queryParams)
+			})
+			return true
+		}
+		mismatchResponse := r.findMismatchResponse(request)
+		if mismatchResponse == nil {
+			return false
+		}
+		routematch.Handler = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			r.renderMismatchResponse(writer, request, mismatchResponse, mismatch, queryParams)
+		})
+		return true
 	})
+}
+
+/*
+serveEndpoint renders the response of the endpoint, stores the match and triggers the callbacks
+*/
+func (r *RequestHandler) serveEndpoint(writer http.ResponseWriter, request *http.Request, endPoint *Endpoint, match *matches.Match, requestPathParam, queryParams map[string]string) {
+	r.renderResponse(writer, request, endPoint, match, requestPathParam, queryParams)
+	r.storeMatch(match)
+	r.triggerCallbacks(endPoint, match, request, requestPathParam, queryParams)
+}
+
+/*
+AddAPIRoutes adds the routes of the configuration api
+*/
+func (r *RequestHandler) AddAPIRoutes(router *mux.Router) {
 	router.NewRoute().Name("reload").Path(r.pathPrefix + "/reload").Methods(http.MethodPost).
 		HandlerFunc(r.handleReload)
+	router.NewRoute().Name("importHAR").Path(r.pathPrefix + "/import/har").Methods(http.MethodPost).
+		HandlerFunc(util.JSONContentTypeRequest(r.handleImportHAR))
+}
+
+/*
+ReloadWith adds request handlers, e.g. of other mock listeners, whose mockfiles are reloaded together with the mockfiles of this request handler
+*/
+func (r *RequestHandler) ReloadWith(requestHandlers ...*RequestHandler) {
+	r.reloadWith = append(r.reloadWith, requestHandlers...)
 }
 
 func (r *RequestHandler) handleReload(writer http.ResponseWriter, request *http.Request) {
 	r.logger.Info("Reloading mock files...")
-	err := r.LoadFiles()
-	if err != nil {
-		r.logger.Error("Error reloading mock files", zap.Error(err))
-		writer.WriteHeader(http.StatusInternalServerError)
-	} else {
-		r.logger.Info("Reloaded mock files successfully.")
-		writer.WriteHeader(http.StatusOK)
+	for _, requestHandler := range append([]*RequestHandler{r}, r.reloadWith...) {
+		if err := requestHandler.LoadFiles(); err != nil {
+			r.logger.Error("Error reloading mock files", zap.Error(err))
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 	}
+	r.logger.Info("Reloaded mock files successfully.")
+	writer.WriteHeader(http.StatusOK)
 }
 
 func (r *RequestHandler) registerEndpoint(endpoint *Endpoint, sn *epSearchNode) {
@@ -244,7 +511,11 @@ func (r *RequestHandler) registerEndpoint(endpoint *Endpoint, sn *epSearchNode)
 		endpoint.Request.Method = "GET"
 	}
 
-	pathSegments := strings.Split(endpoint.Request.Path, "/")
+	endpointPath := endpoint.Request.Path
+	if endpoint.Mock != nil {
+		endpointPath = endpoint.Mock.PathPrefix + endpointPath
+	}
+	pathSegments := strings.Split(endpointPath, "/")
 	for _, pathSegment := range pathSegments[1:] {
 		if sn.searchNodes == nil {
 			sn.searchNodes = make(map[string]*epSearchNode)
@@ -284,7 +555,7 @@ func (r *RequestHandler) registerEndpoint(endpoint *Endpoint, sn *epSearchNode)
 		sn.endpoints[endpointKey] = append(sn.endpoints[endpointKey][:insertIndex+1], sn.endpoints[endpointKey][insertIndex:]...)
 		sn.endpoints[endpointKey][insertIndex] = endpoint
 	}
-	r.logger.Info(fmt.Sprintf("register endpoint with id '%s' for path|method: %s|%s", endpoint.ID, endpoint.Request.Path, endpoint.Request.Method))
+	r.logger.Info(fmt.Sprintf("register endpoint with id '%s' for path|method: %s|%s", endpoint.ID, endpointPath, endpoint.Request.Method))
 }
 
 func getPathSegment(segments []string, pos int) string {
@@ -294,7 +565,7 @@ func getPathSegment(segments []string, pos int) string {
 	return ""
 }
 
-func (r *RequestHandler) matchRequestToEndpoint(request *http.Request) (*Endpoint, *matches.Match, map[string]string, map[string]string) {
+func (r *RequestHandler) matchRequestToEndpoint(request *http.Request) (*Endpoint, *matches.Match, *matches.Mismatch, map[string]string, map[string]string) {
 	requestPathParams := map[string]string{}
 	queryParams := map[string]string{}
 
@@ -311,8 +582,7 @@ func (r *RequestHandler) matchRequestToEndpoint(request *http.Request) (*Endpoin
 			if allMatch {
 				break
 			} else {
-				r.addMismatch(sn, pos, "", request)
-				return nil, nil, requestPathParams, queryParams
+				return nil, nil, r.addMismatch(sn, pos, "", request), requestPathParams, queryParams
 			}
 		} else {
 			if allMatch {
@@ -328,8 +598,7 @@ func (r *RequestHandler) matchRequestToEndpoint(request *http.Request) (*Endpoin
 			if sn.searchNodes[pathSegment] == nil {
 				if sn.searchNodes["*"] == nil {
 					if sn.searchNodes["**"] == nil {
-						r.addMismatch(sn, pos, "", request)
-						return nil, nil, requestPathParams, queryParams
+						return nil, nil, r.addMismatch(sn, pos, "", request), requestPathParams, queryParams
 					}
 					allMatch = true
 					sn = sn.searchNodes["**"]
@@ -349,22 +618,20 @@ func (r *RequestHandler) matchRequestToEndpoint(request *http.Request) (*Endpoin
 
 }
 
-func (r *RequestHandler) matchSearchNode(sn *epSearchNode, request *http.Request, requestPathParams map[string]string, queryParams map[string]string) (*Endpoint, *matches.Match, map[string]string, map[string]string) {
+func (r *RequestHandler) matchSearchNode(sn *epSearchNode, request *http.Request, requestPathParams map[string]string, queryParams map[string]string) (*Endpoint, *matches.Match, *matches.Mismatch, map[string]string, map[string]string) {
 	if sn != nil && sn.endpoints != nil {
 		endpoints := sn.endpoints["+"+request.Method+"-"+strings.Split(request.Host, ":")[0]]
 		endpoints = append(endpoints, sn.endpoints[request.Method]...)
 		if endpoints != nil && len(endpoints) > 0 {
-			ep, match := r.matchEndPointsAttributes(endpoints, request)
-			return ep, match, requestPathParams, queryParams
+			ep, match, mismatch := r.matchEndPointsAttributes(endpoints, request)
+			return ep, match, mismatch, requestPathParams, queryParams
 		}
-		r.addMismatch(nil, -1, fmt.Sprintf("no endpoint found with method '%s'", request.Method), request)
-		return nil, nil, requestPathParams, queryParams
+		return nil, nil, r.addMismatch(nil, -1, fmt.Sprintf("no endpoint found with method '%s'", request.Method), request), requestPathParams, queryParams
 	}
-	r.addMismatch(sn, math.MaxInt, "", request)
-	return nil, nil, requestPathParams, queryParams
+	return nil, nil, r.addMismatch(sn, math.MaxInt, "", request), requestPathParams, queryParams
 }
 
-func (r *RequestHandler) matchEndPointsAttributes(endPoints []*Endpoint, request *http.Request) (*Endpoint, *matches.Match) {
+func (r *RequestHandler) matchEndPointsAttributes(endPoints []*Endpoint, request *http.Request) (*Endpoint, *matches.Match, *matches.Mismatch) {
 	mismatchMessage := ""
 	for _, ep := range endPoints {
 		if !r.matchQueryParams(ep.Request, request) {
@@ -379,11 +646,18 @@ func (r *RequestHandler) matchEndPointsAttributes(endPoints []*Endpoint, request
 			mismatchMessage = mismatchMessage + fmt.Sprintf(", endpointId '%s' not matched because of wanted body: '%s'", ep.ID, ep.Request.Body)
 			continue
 		}
+		if matched, details := r.matchGraphQL(ep.Request, request); !matched {
+			mismatchMessage = mismatchMessage + fmt.Sprintf(", endpointId '%s' not matched because of graphql: %s", ep.ID, details)
+			continue
+		}
+		if matched, details := matchClientCert(ep.Request.ClientCert, request); !matched {
+			mismatchMessage = mismatchMessage + fmt.Sprintf(", endpointId '%s' not matched because of client certificate: %s", ep.ID, details)
+			continue
+		}
 		match := r.addMatch(ep, request)
-		return ep, match
+		return ep, match, nil
 	}
-	r.addMismatch(nil, -1, mismatchMessage, request)
-	return nil, nil
+	return nil, nil, r.addMismatch(nil, -1, mismatchMessage, request)
 }
 
 func (r *RequestHandler) matchQueryParams(matchRequest *MatchRequest, request *http.Request) bool {
@@ -412,7 +686,7 @@ func (r *RequestHandler) matchHeaderValues(matchRequest *MatchRequest, request *
 
 func (r *RequestHandler) matchBody(matchRequest *MatchRequest, request *http.Request) bool {
 	if matchRequest.BodyRegexp != nil {
-		reqBodyBytes, err := io.ReadAll(request.Body)
+		reqBodyBytes, err := readRequestBody(request)
 		if err != nil {
 			r.logger.Error("no match, error reading request body", zap.Error(err))
 			return false
@@ -422,15 +696,32 @@ func (r *RequestHandler) matchBody(matchRequest *MatchRequest, request *http.Req
 	return true
 }
 
+/*
+addMatch creates the match of a request, it is stored with storeMatch after the response is rendered
+*/
 func (r *RequestHandler) addMatch(endPoint *Endpoint, request *http.Request) *matches.Match {
-	actualRequest := &matches.ActualRequest{Method: request.Method, URL: request.URL.String(), Header: request.Header, Host: request.Host}
-	match := &matches.Match{EndpointID: endPoint.ID, Timestamp: time.Now(), ActualRequest: actualRequest}
-	r.matchstore.AddMatch(endPoint.ID, match)
+	match := &matches.Match{EndpointID: endPoint.ID, Timestamp: time.Now(), ActualRequest: r.newActualRequest(request)}
 	matchesMetric.With(prometheus.Labels{"endpoint": endPoint.ID}).Inc()
 	return match
 }
 
-func (r *RequestHandler) addMismatch(sn *epSearchNode, pathPos int, endpointMismatchDetails string, request *http.Request) {
+func (r *RequestHandler) storeMatch(match *matches.Match) {
+	if err := r.matchstore.AddMatch(match.EndpointID, match); err != nil {
+		r.logger.Error(fmt.Sprintf("can't store match of endpoint '%s'", match.EndpointID), zap.Error(err))
+	}
+}
+
+func (r *RequestHandler) newActualRequest(request *http.Request) *matches.ActualRequest {
+	actualRequest := &matches.ActualRequest{Method: request.Method, URL: request.URL.String(), Header: request.Header, Host: request.Host, RemoteAddr: request.RemoteAddr}
+	body, err := readRequestBody(request)
+	if err != nil {
+		r.logger.Info("can't read request body for the match", zap.Error(err))
+	}
+	actualRequest.Body, actualRequest.Binary, actualRequest.BodyTruncated = matches.EncodeBody(body, r.bodyLimit)
+	return actualRequest
+}
+
+func (r *RequestHandler) addMismatch(sn *epSearchNode, pathPos int, endpointMismatchDetails string, request *http.Request) *matches.Mismatch {
 	var mismatchDetails string
 	if sn == nil { // node found -> path matched
 		mismatchDetails = fmt.Sprintf("path '%s' matched, but %s", request.URL.Path, endpointMismatchDetails)
@@ -444,13 +735,30 @@ func (r *RequestHandler) addMismatch(sn *epSearchNode, pathPos int, endpointMism
 		}
 		mismatchDetails = fmt.Sprintf("path '%s' not matched, subpath which matched: '%s'", request.URL.Path, matchedSubPath)
 	}
-	actualRequest := &matches.ActualRequest{Method: request.Method, URL: request.URL.String(), Header: request.Header, Host: request.Host}
+	actualRequest := r.newActualRequest(request)
 	mismatch := &matches.Mismatch{
-		MismatchDetails: mismatchDetails,
-		Timestamp:       time.Now(),
-		ActualRequest:   actualRequest}
+		MismatchDetails:  mismatchDetails,
+		Timestamp:        time.Now(),
+		ActualRequest:    actualRequest,
+		NearestEndpoints: r.nearestEndpoints(request, actualRequest)}
+	if r.logger.Core().Enabled(zap.DebugLevel) {
+		r.logger.Debug(fmt.Sprintf("request '%s %s' didn't match, nearest endpoints: %s", request.Method, request.URL.Path, jsonString(mismatch.NearestEndpoints)))
+	}
 	r.matchstore.AddMismatch(mismatch)
 	mismatchesMetric.Inc()
+	return mismatch
+}
+
+func writeInformationalResponses(writer http.ResponseWriter, informationals []*Informational) {
+	for _, informational := range informationals {
+		for key, val := range informational.Headers {
+			writer.Header().Set(key, val)
+		}
+		writer.WriteHeader(informational.StatusCode)
+		for key := range informational.Headers {
+			writer.Header().Del(key)
+		}
+	}
 }
 
 func (r *RequestHandler) renderResponse(writer http.ResponseWriter, request *http.Request, endpoint *Endpoint, match *matches.Match, requestPathParams, queryParams map[string]string) {
@@ -461,53 +769,158 @@ func (r *RequestHandler) renderResponse(writer http.ResponseWriter, request *htt
 		fmt.Fprintf(writer, "Error rendering response: %v", err)
 		return
 	}
+	writeInformationalResponses(writer, endpoint.Response.Informational)
+	if endpoint.Request != nil && endpoint.Request.GraphQL != nil {
+		if gqlRequest, err := parseGraphQLRequest(request); err == nil {
+			responseTemplateData.GraphQLVariables = gqlRequest.Variables
+			responseTemplateData.GraphQLOperation, _ = parseGraphQLOperation(gqlRequest, nil)
+		}
+	}
 
-	var renderedHeaders bytes.Buffer
-	err = endpoint.Response.Template.ExecuteTemplate(&renderedHeaders, templateResponseHeader, responseTemplateData)
-	if err != nil {
-		writer.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(writer, "Error rendering response headers: %v", err)
+	if !writeResponseHeaders(writer, endpoint, responseTemplateData) {
+		return
+	}
+	responseStatus, ok := renderResponseStatus(writer, endpoint, responseTemplateData)
+	if !ok {
 		return
 	}
+	responseTemplateData.ResponseStatus = responseStatus
 
-	var headers map[string]string
-	err = yaml.Unmarshal(renderedHeaders.Bytes(), &headers)
-	if err != nil {
-		writer.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(writer, "Error unmarshalling response headers: %v", err)
+	if endpoint.Response.Stream != nil {
+		if err := r.serveStream(writer, request, endpoint, match, responseTemplateData, responseStatus); err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(writer, "Error serving response stream: %v", err)
+		}
 		return
 	}
+
+	if endpoint.Response.WebSocket != nil {
+		if err := r.serveWebSocket(writer, request, endpoint, match, responseTemplateData); err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(writer, "Error serving websocket: %v", err)
+		}
+		return
+	}
+
+	if hasRawBody(endpoint.Response) {
+		recorder := &bodyRecorder{ResponseWriter: writer, statusCode: responseStatus, limit: r.bodyLimit}
+		if err := r.serveRawBody(recorder, request, endpoint, responseStatus); err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(writer, "Error serving response body: %v", err)
+			return
+		}
+		match.ActualResponse = r.newActualResponse(match, recorder.statusCode, writer.Header(), recorder.body.Bytes())
+		return
+	}
+	r.writeResponseBody(writer, endpoint, match, responseTemplateData, responseStatus)
+}
+
+/*
+writeResponseHeaders adds the rendered default headers and headers of the endpoint, on error it writes an error response and returns false
+*/
+func writeResponseHeaders(writer http.ResponseWriter, endpoint *Endpoint, responseTemplateData *responseTemplateData) bool {
+	headers := map[string]string{}
+	for _, headerTemplate := range []string{templateResponseDefaultHeader, templateResponseHeader} {
+		var renderedHeaders bytes.Buffer
+		err := endpoint.Response.Template.ExecuteTemplate(&renderedHeaders, headerTemplate, responseTemplateData)
+		if err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(writer, "Error rendering response headers: %v", err)
+			return false
+		}
+		if err := decodeHeaders(&renderedHeaders, headers); err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(writer, "Error unmarshalling response headers: %v", err)
+			return false
+		}
+	}
 	for key, val := range headers {
 		writer.Header().Add(key, val)
 	}
+	return true
+}
 
+/*
+decodeHeaders adds the headers of all yaml documents of the rendered headers, the headers of a later document take precedence
+*/
+func decodeHeaders(renderedHeaders io.Reader, headers map[string]string) error {
+	decoder := yaml.NewDecoder(renderedHeaders)
+	for {
+		var documentHeaders map[string]string
+		if err := decoder.Decode(&documentHeaders); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for key, val := range documentHeaders {
+			headers[key] = val
+		}
+	}
+}
+
+/*
+renderResponseStatus renders the status of the endpoint, on error it writes an error response and returns false
+*/
+func renderResponseStatus(writer http.ResponseWriter, endpoint *Endpoint, responseTemplateData *responseTemplateData) (int, bool) {
 	var renderedStatus bytes.Buffer
-	err = endpoint.Response.Template.ExecuteTemplate(&renderedStatus, templateResponseStatus, responseTemplateData)
+	err := endpoint.Response.Template.ExecuteTemplate(&renderedStatus, templateResponseStatus, responseTemplateData)
 	if err != nil {
 		writer.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(writer, "Error rendering response status: %v", err)
-		return
+		return 0, false
 	}
 	responseStatus, err := strconv.Atoi(renderedStatus.String())
 	if err != nil {
 		writer.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(writer, "Error converting response status: %v", err)
-		return
+		return 0, false
 	}
-	responseTemplateData.ResponseStatus = responseStatus
+	return responseStatus, true
+}
 
+/*
+writeResponseBody writes the rendered body and trailers of the endpoint and stores the actual response in the match
+*/
+func (r *RequestHandler) writeResponseBody(writer http.ResponseWriter, endpoint *Endpoint, match *matches.Match, responseTemplateData *responseTemplateData, responseStatus int) {
 	var renderedBody bytes.Buffer
-	err = endpoint.Response.Template.ExecuteTemplate(&renderedBody, templateResponseBody, responseTemplateData)
+	err := endpoint.Response.Template.ExecuteTemplate(&renderedBody, templateResponseBody, responseTemplateData)
 	if err != nil {
 		writer.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(writer, "Error rendering response body: %v", err)
 		return
 	}
+	var renderedTrailers bytes.Buffer
+	err = endpoint.Response.Template.ExecuteTemplate(&renderedTrailers, templateResponseTrailer, responseTemplateData)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error rendering response trailers: %v", err)
+		return
+	}
+	var trailers map[string]string
+	err = yaml.Unmarshal(renderedTrailers.Bytes(), &trailers)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(writer, "Error unmarshalling response trailers: %v", err)
+		return
+	}
+	for key := range trailers {
+		writer.Header().Add("Trailer", key)
+	}
+	match.ActualResponse = r.newActualResponse(match, responseStatus, writer.Header(), renderedBody.Bytes())
 	writer.WriteHeader(responseStatus)
 	writer.Write(renderedBody.Bytes())
+	for key, val := range trailers {
+		writer.Header().Set(key, val)
+	}
+}
 
-	//TODO: handle headers
-	match.ActualResponse = &matches.ActualResponse{StatusCode: responseStatus, Header: make(map[string][]string)}
+/*
+newActualResponse creates the actual response of a match, the duration is measured from the timestamp of the match
+*/
+func (r *RequestHandler) newActualResponse(match *matches.Match, statusCode int, header http.Header, body []byte) *matches.ActualResponse {
+	actualResponse := &matches.ActualResponse{StatusCode: statusCode, Header: header.Clone(), Duration: time.Since(match.Timestamp)}
+	actualResponse.Body, actualResponse.Binary, actualResponse.BodyTruncated = matches.EncodeBody(body, r.bodyLimit)
+	return actualResponse
 }
 
 func (r *RequestHandler) createResponseTemplateData(request *http.Request, requestPathParams, queryParams map[string]string) (*responseTemplateData, error) {
@@ -519,54 +932,23 @@ func (r *RequestHandler) createResponseTemplateData(request *http.Request, reque
 		RequestPath:        request.URL.Path,
 		RequestHost:        request.URL.Host,
 		ResponseStatus:     0,
+		RequestClientCert:  requestClientCert(request),
 	}
 
 	for k, v := range request.Header {
 		data.RequestHeader[k] = v[0]
 	}
 	if request.Body != nil {
-		body := new(bytes.Buffer)
-		_, err := body.ReadFrom(request.Body)
+		body, err := readRequestBody(request)
 		if err != nil {
 			return nil, err
 		}
-		data.RequestBody = body.String()
+		data.RequestBody = string(body)
 		bodyData := &map[string]interface{}{}
-		err = json.Unmarshal(body.Bytes(), bodyData)
+		err = json.Unmarshal(body, bodyData)
 		if err == nil { // ignore when no json
 			data.RequestBodyJSONData = *bodyData
 		}
 	}
 	return data, nil
 }
-
-func walkMatch(root, pattern string) ([]string, error) {
-	var matches []string
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
-		info.Mode()
-		if matched, err := filepath.Match(pattern, filepath.Base(path)); err != nil {
-			return err
-		} else if matched {
-			if filepath.IsAbs(root) {
-				if filepath.Dir(path) == root {
-					matches = append(matches, path)
-				} else {
-					return nil
-				}
-			} else {
-				matches = append(matches, path)
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	return matches, nil
-}
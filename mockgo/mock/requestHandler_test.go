@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"log"
@@ -8,7 +9,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alitari/mockgo-server/mockgo/matches"
 	"github.com/alitari/mockgo-server/mockgo/testutil"
@@ -36,7 +41,7 @@ type mockTestCase struct {
 var router = mux.NewRouter()
 
 func TestMain(m *testing.M) {
-	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	if err := mockRequestHandler.LoadFiles(); err != nil {
 		log.Fatal(err)
 	}
@@ -53,25 +58,25 @@ func TestMain(m *testing.M) {
 }
 
 func TestMockRequestHandler_LoadFiles_dir_not_exists(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "pathnotexists", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "pathnotexists", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	assert.ErrorContains(t, mockRequestHandlerWithError.LoadFiles(), "lstat pathnotexists: no such file or directory")
 }
 
 func TestMockRequestHandler_ReadMockfile_wrong_requestBody(t *testing.T) {
 	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongRequestBodyRegexp",
-		"*-mock.yaml", matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+		"*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	assert.ErrorContains(t, mockRequestHandlerWithError.LoadFiles(), "error parsing regexp: missing closing ]: `[a`")
 }
 
 func TestMockRequestHandler_ReadMockfile_wrong_yaml(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongYaml", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongYaml", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	assert.ErrorContains(t, mockRequestHandlerWithError.LoadFiles(), "yaml: line 3: mapping values are not allowed in this context")
 }
 
 func TestMockRequestHandler_InitResponseTemplates_doubleBody(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/doubleResponseBody", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/doubleResponseBody", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	err := mockRequestHandlerWithError.LoadFiles()
 	assert.NoError(t, err)
@@ -80,7 +85,7 @@ func TestMockRequestHandler_InitResponseTemplates_doubleBody(t *testing.T) {
 }
 
 func TestMockRequestHandler_InitResponseTemplates_bodyfilename_not_exists(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/bodyfilenameDoesNotExist", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/bodyfilenameDoesNotExist", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	err := mockRequestHandlerWithError.LoadFiles()
 	assert.NoError(t, err)
@@ -89,7 +94,7 @@ func TestMockRequestHandler_InitResponseTemplates_bodyfilename_not_exists(t *tes
 }
 
 func TestMockRequestHandler_InitResponseTemplates_wrongResponseBodyTemplate(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseBodyTemplate", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseBodyTemplate", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	err := mockRequestHandlerWithError.LoadFiles()
 	assert.NoError(t, err)
@@ -98,7 +103,7 @@ func TestMockRequestHandler_InitResponseTemplates_wrongResponseBodyTemplate(t *t
 }
 
 func TestMockRequestHandler_InitResponseTemplates_wrongResponseStatusTemplate(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseStatusTemplate", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseStatusTemplate", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	err := mockRequestHandlerWithError.LoadFiles()
 	assert.NoError(t, err)
@@ -107,7 +112,7 @@ func TestMockRequestHandler_InitResponseTemplates_wrongResponseStatusTemplate(t
 }
 
 func TestMockRequestHandler_InitResponseTemplates_wrongResponseHeaderTemplate(t *testing.T) {
-	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseHeaderTemplate", "*-mock.yaml",
+	mockRequestHandlerWithError := NewRequestHandler("", "../../test/mocksWithError/wrongResponseHeaderTemplate", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	err := mockRequestHandlerWithError.LoadFiles()
 	assert.NoError(t, err)
@@ -116,14 +121,14 @@ func TestMockRequestHandler_InitResponseTemplates_wrongResponseHeaderTemplate(t
 }
 
 func TestMockRequestHandler_matchBody_readerror(t *testing.T) {
-	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml",
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	errorRequest := testutil.CreateIncomingErrorReadingBodyRequest(http.MethodGet, "/path", testutil.CreateHeader())
 	assert.False(t, mockRequestHandler.matchBody(&MatchRequest{BodyRegexp: regexp.MustCompile(`^`)}, errorRequest))
 }
 
 func TestMockRequestHandler_renderResponse_readerror(t *testing.T) {
-	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml",
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
 		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
 	errorRequest := testutil.CreateIncomingErrorReadingBodyRequest(http.MethodGet, "/path", testutil.CreateHeader())
 	recorder := httptest.NewRecorder()
@@ -442,3 +447,108 @@ func assertTestcases(t *testing.T, mockTestCases []*mockTestCase) {
 		})
 	}
 }
+
+func TestMockRequestHandler_serving_matchesBodies(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	mockRequestHandler.SetMatchesBodyLimit(12)
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	mockRouter := mux.NewRouter()
+	mockRequestHandler.AddRoutes(mockRouter)
+	server := httptest.NewServer(mockRouter)
+
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/responsetemplates/foo", strings.NewReader(`{ "mybody": "is cool!" }`))
+	assert.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	request, err = http.NewRequest(http.MethodGet, server.URL+"/minimalwrong", bytes.NewReader([]byte{0xff, 0xfe}))
+	assert.NoError(t, err)
+	response, err = http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	response, err = http.Get(server.URL + "/binary/file")
+	assert.NoError(t, err)
+	response.Body.Close()
+	server.Close()
+
+	templateMatches, err := matchstore.GetMatches("response-templates")
+	assert.NoError(t, err)
+	if assert.Len(t, templateMatches, 1) {
+		actualRequest := templateMatches[0].ActualRequest
+		assert.Equal(t, `{ "mybody": `, actualRequest.Body)
+		assert.True(t, actualRequest.BodyTruncated)
+		assert.False(t, actualRequest.Binary)
+		assert.NotEmpty(t, actualRequest.RemoteAddr)
+		actualResponse := templateMatches[0].ActualResponse
+		assert.Equal(t, http.StatusOK, actualResponse.StatusCode)
+		assert.Equal(t, []string{"response-templates"}, actualResponse.Header["Endpoint-Id"])
+		assert.Equal(t, "RequestPathP", actualResponse.Body)
+		assert.True(t, actualResponse.BodyTruncated)
+		assert.Greater(t, actualResponse.Duration, time.Duration(0))
+	}
+
+	fileMatches, err := matchstore.GetMatches("binaryFile")
+	assert.NoError(t, err)
+	if assert.Len(t, fileMatches, 1) {
+		assert.Equal(t, "iVBORw0KGgoAAAAN", fileMatches[0].ActualResponse.Body)
+		assert.True(t, fileMatches[0].ActualResponse.Binary)
+		assert.True(t, fileMatches[0].ActualResponse.BodyTruncated)
+		assert.Equal(t, []string{"image/png"}, fileMatches[0].ActualResponse.Header["Content-Type"])
+	}
+
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	if assert.Len(t, mismatches, 1) {
+		assert.Equal(t, "//4=", mismatches[0].ActualRequest.Body)
+		assert.True(t, mismatches[0].ActualRequest.Binary)
+		assert.False(t, mismatches[0].ActualRequest.BodyTruncated)
+	}
+}
+
+func TestMockRequestHandler_EndpointIDs(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.Empty(t, mockRequestHandler.EndpointIDs())
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assert.Contains(t, mockRequestHandler.EndpointIDs(), "minimal")
+	assert.Contains(t, mockRequestHandler.EndpointIDs(), "response-templates")
+}
+
+func TestMockRequestHandler_AddMockRoutes_concurrent(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(1000))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	concurrentRouter := mux.NewRouter()
+	mockRequestHandler.AddMockRoutes(concurrentRouter, true)
+	server := httptest.NewServer(concurrentRouter)
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				pathParam := strconv.Itoa(i*10 + j)
+				response, err := http.Get(server.URL + "/pathParams/" + pathParam + "/foo")
+				if assert.NoError(t, err) {
+					body, _ := io.ReadAll(response.Body)
+					response.Body.Close()
+					assert.Equal(t, "pathParam="+pathParam, string(body))
+				}
+				response, err = http.Get(server.URL + "/minimal")
+				if assert.NoError(t, err) {
+					response.Body.Close()
+					assert.Equal(t, http.StatusNoContent, response.StatusCode)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	count, err := matchstore.GetMatchesCount("singlepathparam")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), count)
+	count, err = matchstore.GetMatchesCount("minimal")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), count)
+}
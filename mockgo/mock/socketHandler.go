@@ -0,0 +1,409 @@
+package mock
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	sprig "github.com/Masterminds/sprig/v3"
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const templateSocketResponse = "socketResponse"
+
+const socketProtocolTCP = "tcp"
+const socketProtocolUDP = "udp"
+
+const socketFramingLine = "line"
+const socketFramingRaw = "raw"
+
+const maxSocketMessageSize = 64 * 1024
+
+/*
+SocketHandler serves the tcp and udp endpoints of the mockfiles, a listener is opened for every protocol and port of the endpoints
+*/
+type SocketHandler struct {
+	requestHandler *RequestHandler
+	logger         *zap.Logger
+	lock           sync.RWMutex
+	listeners      map[string]*socketListener
+	serving        sync.WaitGroup
+}
+
+type socketListener struct {
+	protocol    string
+	port        int
+	framing     string
+	endpoints   []*SocketEndpoint
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+	connsLock   sync.Mutex
+	conns       map[net.Conn]bool
+}
+
+/*
+NewSocketHandler creates an instance of SocketHandler, the socket endpoints are loaded and the listeners are opened with RequestHandler.LoadFiles
+*/
+func NewSocketHandler(requestHandler *RequestHandler) *SocketHandler {
+	socketHandler := &SocketHandler{
+		requestHandler: requestHandler,
+		logger:         requestHandler.logger,
+		listeners:      map[string]*socketListener{},
+	}
+	requestHandler.socketHandler = socketHandler
+	return socketHandler
+}
+
+func socketListenerKey(protocol string, port int) string {
+	return protocol + "/" + strconv.Itoa(port)
+}
+
+/*
+Addr returns the address of the listener of a socket endpoint, this is useful for endpoints with port 0
+*/
+func (s *SocketHandler) Addr(endpointID string) net.Addr {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, listener := range s.listeners {
+		for _, endpoint := range listener.endpoints {
+			if endpoint.ID == endpointID {
+				return listener.addr()
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Shutdown closes all listeners and connections and waits until the handling of received messages is finished
+*/
+func (s *SocketHandler) Shutdown() {
+	s.lock.Lock()
+	for key, listener := range s.listeners {
+		listener.close()
+		delete(s.listeners, key)
+	}
+	s.lock.Unlock()
+	s.serving.Wait()
+}
+
+func (s *SocketHandler) load(mocks []*Mock, templates map[string]string) error {
+	endpointsByKey := s.registerSocketEndpoints(mocks, templates)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	opened := map[string]*socketListener{}
+	for key, endpoints := range endpointsByKey {
+		if s.listeners[key] != nil {
+			continue
+		}
+		listener, err := s.listen(endpoints[0])
+		if err != nil {
+			for _, openedListener := range opened {
+				openedListener.close()
+			}
+			return err
+		}
+		opened[key] = listener
+	}
+	for key, listener := range s.listeners {
+		if endpointsByKey[key] == nil {
+			listener.close()
+			delete(s.listeners, key)
+		}
+	}
+	for key, listener := range opened {
+		s.listeners[key] = listener
+		s.serving.Add(1)
+		if listener.tcpListener != nil {
+			go s.serveTCP(listener)
+		} else {
+			go s.serveUDP(listener)
+		}
+	}
+	for key, listener := range s.listeners {
+		listener.endpoints = endpointsByKey[key]
+		listener.framing = listener.endpoints[0].Framing
+	}
+	return nil
+}
+
+func (s *SocketHandler) registerSocketEndpoints(mocks []*Mock, templates map[string]string) map[string][]*SocketEndpoint {
+	endpointsByKey := map[string][]*SocketEndpoint{}
+	endpointCounter := 0
+	for _, mock := range mocks {
+		for _, endpoint := range mock.Sockets {
+			endpointCounter++
+			if len(endpoint.ID) == 0 {
+				endpoint.ID = "socket" + strconv.Itoa(endpointCounter)
+			}
+			if err := s.initSocketEndpoint(endpoint, templates); err != nil {
+				s.logger.Error(fmt.Sprintf("Can't initialize socket endpoint id '%s', skipping endpoint ", endpoint.ID), zap.Error(err))
+				continue
+			}
+			key := socketListenerKey(endpoint.Protocol, endpoint.Port)
+			if len(endpointsByKey[key]) > 0 && endpointsByKey[key][0].Framing != endpoint.Framing {
+				s.logger.Error(fmt.Sprintf("Framing '%s' of socket endpoint id '%s' differs from the framing '%s' of the other endpoints of %s port %d, skipping endpoint",
+					endpoint.Framing, endpoint.ID, endpointsByKey[key][0].Framing, endpoint.Protocol, endpoint.Port))
+				continue
+			}
+			endpointsByKey[key] = append(endpointsByKey[key], endpoint)
+			s.logger.Info(fmt.Sprintf("register socket endpoint with id '%s' for %s port %d", endpoint.ID, endpoint.Protocol, endpoint.Port))
+		}
+	}
+	for _, endpoints := range endpointsByKey {
+		sort.SliceStable(endpoints, func(i, j int) bool { return endpoints[i].Prio > endpoints[j].Prio })
+	}
+	return endpointsByKey
+}
+
+func (s *SocketHandler) initSocketEndpoint(endpoint *SocketEndpoint, templates map[string]string) error {
+	endpoint.Protocol = strings.ToLower(endpoint.Protocol)
+	if len(endpoint.Protocol) == 0 {
+		endpoint.Protocol = socketProtocolTCP
+	}
+	if endpoint.Protocol != socketProtocolTCP && endpoint.Protocol != socketProtocolUDP {
+		return fmt.Errorf("protocol '%s' of socket endpoint id '%s' must be 'tcp' or 'udp'", endpoint.Protocol, endpoint.ID)
+	}
+	if endpoint.Port < 0 || endpoint.Port > 65535 {
+		return fmt.Errorf("port %d of socket endpoint id '%s' is invalid", endpoint.Port, endpoint.ID)
+	}
+	if len(endpoint.Framing) == 0 {
+		endpoint.Framing = socketFramingLine
+	}
+	if endpoint.Framing != socketFramingLine && endpoint.Framing != socketFramingRaw {
+		return fmt.Errorf("framing '%s' of socket endpoint id '%s' must be 'line' or 'raw'", endpoint.Framing, endpoint.ID)
+	}
+	if endpoint.Request != nil {
+		if err := initSocketMatchRequest(endpoint.ID, endpoint.Request); err != nil {
+			return err
+		}
+	}
+	if endpoint.Response == nil {
+		endpoint.Response = &SocketResponse{}
+	}
+	endpoint.Response.Template = template.New(endpoint.ID).Funcs(sprig.TxtFuncMap()).Funcs(s.requestHandler.envFuncMap).Funcs(s.requestHandler.funcMap)
+	for name, text := range templates {
+		if _, err := endpoint.Response.Template.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	_, err := endpoint.Response.Template.New(templateSocketResponse).Parse(endpoint.Response.Body)
+	return err
+}
+
+func initSocketMatchRequest(endpointID string, request *SocketMatchRequest) error {
+	if len(request.Regexp) > 0 {
+		messageRegexp, err := regexp.Compile(request.Regexp)
+		if err != nil {
+			return fmt.Errorf("error parsing request.regexp of socket endpoint id '%s': %v", endpointID, err)
+		}
+		request.MessageRegexp = messageRegexp
+	}
+	if len(request.HexPrefix) > 0 {
+		prefix, err := hex.DecodeString(strings.ReplaceAll(request.HexPrefix, " ", ""))
+		if err != nil {
+			return fmt.Errorf("error parsing request.hexPrefix of socket endpoint id '%s': %v", endpointID, err)
+		}
+		request.Prefix = prefix
+	}
+	return nil
+}
+
+func (s *SocketHandler) listen(endpoint *SocketEndpoint) (*socketListener, error) {
+	listener := &socketListener{protocol: endpoint.Protocol, port: endpoint.Port, framing: endpoint.Framing, conns: map[net.Conn]bool{}}
+	address := ":" + strconv.Itoa(endpoint.Port)
+	var err error
+	if endpoint.Protocol == socketProtocolTCP {
+		listener.tcpListener, err = net.Listen(socketProtocolTCP, address)
+	} else {
+		listener.udpConn, err = net.ListenPacket(socketProtocolUDP, address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s port %d: %v", endpoint.Protocol, endpoint.Port, err)
+	}
+	s.logger.Info(fmt.Sprintf("serving %s socket endpoints at %v", endpoint.Protocol, listener.addr()))
+	return listener, nil
+}
+
+func (l *socketListener) addr() net.Addr {
+	if l.tcpListener != nil {
+		return l.tcpListener.Addr()
+	}
+	return l.udpConn.LocalAddr()
+}
+
+func (l *socketListener) close() {
+	if l.tcpListener != nil {
+		l.tcpListener.Close()
+		l.connsLock.Lock()
+		for conn := range l.conns {
+			conn.Close()
+		}
+		l.connsLock.Unlock()
+	} else {
+		l.udpConn.Close()
+	}
+}
+
+func (s *SocketHandler) serveTCP(listener *socketListener) {
+	defer s.serving.Done()
+	for {
+		conn, err := listener.tcpListener.Accept()
+		if err != nil {
+			return
+		}
+		s.serving.Add(1)
+		go s.handleTCPConn(listener, conn)
+	}
+}
+
+func (s *SocketHandler) handleTCPConn(listener *socketListener, conn net.Conn) {
+	defer s.serving.Done()
+	listener.connsLock.Lock()
+	listener.conns[conn] = true
+	listener.connsLock.Unlock()
+	defer func() {
+		listener.connsLock.Lock()
+		delete(listener.conns, conn)
+		listener.connsLock.Unlock()
+		conn.Close()
+	}()
+	s.lock.RLock()
+	framing := listener.framing
+	s.lock.RUnlock()
+	reader := bufio.NewReaderSize(conn, maxSocketMessageSize)
+	for {
+		message, err := readSocketMessage(reader, framing)
+		if len(message) > 0 || (err == nil && framing == socketFramingLine) {
+			reply, closeConn := s.handleMessage(listener, message, conn.LocalAddr(), conn.RemoteAddr())
+			if len(reply) > 0 {
+				if _, err := conn.Write(reply); err != nil {
+					s.logger.Debug(fmt.Sprintf("error writing reply to %v: %v", conn.RemoteAddr(), err))
+					return
+				}
+			}
+			if closeConn {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				s.logger.Debug(fmt.Sprintf("tcp connection from %v closed: %v", conn.RemoteAddr(), err))
+			}
+			return
+		}
+	}
+}
+
+/*
+readSocketMessage reads the next message, with line framing a message ends with a newline, which is not part of the message
+*/
+func readSocketMessage(reader *bufio.Reader, framing string) ([]byte, error) {
+	if framing == socketFramingRaw {
+		buffer := make([]byte, maxSocketMessageSize)
+		n, err := reader.Read(buffer)
+		return buffer[:n], err
+	}
+	line, err := reader.ReadSlice('\n')
+	message := bytes.TrimRight(line, "\r\n")
+	return append([]byte{}, message...), err
+}
+
+func (s *SocketHandler) serveUDP(listener *socketListener) {
+	defer s.serving.Done()
+	buffer := make([]byte, maxSocketMessageSize)
+	for {
+		n, remoteAddr, err := listener.udpConn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		message := append([]byte{}, buffer[:n]...)
+		s.lock.RLock()
+		framing := listener.framing
+		s.lock.RUnlock()
+		if framing == socketFramingLine {
+			message = bytes.TrimRight(message, "\r\n")
+		}
+		reply, _ := s.handleMessage(listener, message, listener.udpConn.LocalAddr(), remoteAddr)
+		if len(reply) > 0 {
+			if _, err := listener.udpConn.WriteTo(reply, remoteAddr); err != nil {
+				s.logger.Debug(fmt.Sprintf("error writing reply to %v: %v", remoteAddr, err))
+			}
+		}
+	}
+}
+
+func matchSocketMessage(matchRequest *SocketMatchRequest, message []byte) bool {
+	if matchRequest == nil {
+		return true
+	}
+	if len(matchRequest.Line) > 0 && matchRequest.Line != string(message) {
+		return false
+	}
+	if matchRequest.MessageRegexp != nil && !matchRequest.MessageRegexp.Match(message) {
+		return false
+	}
+	if len(matchRequest.Prefix) > 0 && !bytes.HasPrefix(message, matchRequest.Prefix) {
+		return false
+	}
+	return true
+}
+
+/*
+handleMessage matches a received message with the endpoints of the listener and renders the reply of the matching endpoint
+*/
+func (s *SocketHandler) handleMessage(listener *socketListener, message []byte, localAddr, remoteAddr net.Addr) ([]byte, bool) {
+	s.lock.RLock()
+	endpoints := listener.endpoints
+	s.lock.RUnlock()
+	url := listener.protocol + "://" + localAddr.String()
+	actualRequest := &matches.ActualRequest{Method: strings.ToUpper(listener.protocol), URL: url, Header: map[string][]string{}, Host: remoteAddr.String()}
+	actualMessage := &matches.ActualMessage{Timestamp: time.Now()}
+	actualMessage.Data, actualMessage.Binary, _ = matches.EncodeBody(message, len(message))
+	for _, endpoint := range endpoints {
+		if !matchSocketMessage(endpoint.Request, message) {
+			continue
+		}
+		templateData := &responseTemplateData{
+			RequestURL:    url,
+			RequestHost:   remoteAddr.String(),
+			RequestHeader: map[string]string{},
+			RequestBody:   string(message),
+		}
+		var bodyData map[string]interface{}
+		if err := json.Unmarshal(message, &bodyData); err == nil { // ignore when no json
+			templateData.RequestBodyJSONData = bodyData
+		}
+		var reply bytes.Buffer
+		if err := endpoint.Response.Template.ExecuteTemplate(&reply, templateSocketResponse, templateData); err != nil {
+			s.logger.Error(fmt.Sprintf("Error rendering reply of socket endpoint id '%s'", endpoint.ID), zap.Error(err))
+			return nil, false
+		}
+		match := &matches.Match{EndpointID: endpoint.ID, Timestamp: time.Now(), ActualRequest: actualRequest,
+			ActualResponse: &matches.ActualResponse{Header: map[string][]string{}},
+			ActualMessages: []*matches.ActualMessage{actualMessage}}
+		s.requestHandler.matchstore.AddMatch(endpoint.ID, match)
+		matchesMetric.With(prometheus.Labels{"endpoint": endpoint.ID}).Inc()
+		return reply.Bytes(), endpoint.Response.Close
+	}
+	mismatch := &matches.Mismatch{
+		MismatchDetails: fmt.Sprintf("%s message %q on port %d didn't match an endpoint", listener.protocol, message, listener.port),
+		Timestamp:       time.Now(),
+		ActualRequest:   actualRequest}
+	s.requestHandler.matchstore.AddMismatch(mismatch)
+	mismatchesMetric.Inc()
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+func startSocketHandler(t *testing.T) (*RequestHandler, *SocketHandler, matches.Matchstore) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/socketmocks", "*-mock.yaml", false, matchstore, nil, "DEBUG")
+	socketHandler := NewSocketHandler(mockRequestHandler)
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	t.Cleanup(socketHandler.Shutdown)
+	return mockRequestHandler, socketHandler, matchstore
+}
+
+func dialSocket(t *testing.T, socketHandler *SocketHandler, endpointID string) net.Conn {
+	addr := socketHandler.Addr(endpointID)
+	if !assert.NotNil(t, addr, "no listener for endpoint '%s'", endpointID) {
+		t.FailNow()
+	}
+	conn, err := net.Dial(addr.Network(), addr.String())
+	assert.NoError(t, err)
+	assert.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSocketHandler_tcp(t *testing.T) {
+	_, socketHandler, matchstore := startSocketHandler(t)
+	assert.Equal(t, socketHandler.Addr("legacyPing"), socketHandler.Addr("legacyQuit"))
+	conn := dialSocket(t, socketHandler, "legacyPing")
+	reader := bufio.NewReader(conn)
+	readLine := func() string {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		return line
+	}
+	_, err := conn.Write([]byte("PING\r\nGET user\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "PONG\r\n", readLine())
+	assert.Equal(t, "VALUE USER\n", readLine())
+	_, err = conn.Write([]byte("UNKNOWN\nQUIT\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "BYE\n", readLine())
+	_, err = reader.ReadByte()
+	assert.Error(t, err, "connection must be closed after QUIT")
+	conn.Close()
+	socketHandler.Shutdown() // waits until the messages are handled
+
+	getMatches, err := matchstore.GetMatches("legacyGet")
+	assert.NoError(t, err)
+	if assert.Len(t, getMatches, 1) {
+		assert.Equal(t, "TCP", getMatches[0].ActualRequest.Method)
+		assert.Equal(t, "tcp://"+conn.RemoteAddr().String(), getMatches[0].ActualRequest.URL)
+		assert.Equal(t, conn.LocalAddr().String(), getMatches[0].ActualRequest.Host)
+		if assert.Len(t, getMatches[0].ActualMessages, 1) {
+			assert.Equal(t, "GET user", getMatches[0].ActualMessages[0].Data)
+		}
+	}
+	mismatches, err := matchstore.GetMismatches()
+	assert.NoError(t, err)
+	if assert.Len(t, mismatches, 1) {
+		assert.Contains(t, mismatches[0].MismatchDetails, `tcp message "UNKNOWN"`)
+	}
+}
+
+func TestSocketHandler_udp(t *testing.T) {
+	_, socketHandler, matchstore := startSocketHandler(t)
+	conn := dialSocket(t, socketHandler, "udpBinary")
+	_, err := conn.Write([]byte("<34>Oct 11 22:14:15 mymachine su: 'su root' failed"))
+	assert.NoError(t, err)
+	_, err = conn.Write([]byte{0xca, 0xfe, 0x01})
+	assert.NoError(t, err)
+	reply := make([]byte, 100)
+	n, err := conn.Read(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, "ack 3", string(reply[:n]))
+	socketHandler.Shutdown() // waits until the messages are handled
+
+	syslogMatches, err := matchstore.GetMatches("syslog")
+	assert.NoError(t, err)
+	if assert.Len(t, syslogMatches, 1) {
+		assert.Equal(t, "UDP", syslogMatches[0].ActualRequest.Method)
+		assert.Equal(t, "<34>Oct 11 22:14:15 mymachine su: 'su root' failed", syslogMatches[0].ActualMessages[0].Data)
+		assert.False(t, syslogMatches[0].ActualMessages[0].Binary)
+	}
+	binaryMatches, err := matchstore.GetMatches("udpBinary")
+	assert.NoError(t, err)
+	if assert.Len(t, binaryMatches, 1) {
+		assert.True(t, binaryMatches[0].ActualMessages[0].Binary)
+		assert.Equal(t, "yv4B", binaryMatches[0].ActualMessages[0].Data)
+	}
+}
+
+func TestSocketHandler_reload(t *testing.T) {
+	mockRequestHandler, socketHandler, _ := startSocketHandler(t)
+	addr := socketHandler.Addr("legacyPing")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assert.Equal(t, addr, socketHandler.Addr("legacyPing"), "listener must be kept on reload")
+	conn := dialSocket(t, socketHandler, "legacyPing")
+	_, err := conn.Write([]byte("PING\n"))
+	assert.NoError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "PONG\r\n", line)
+
+	socketHandler.Shutdown()
+	assert.Nil(t, socketHandler.Addr("legacyPing"))
+	_, err = net.Dial(addr.Network(), addr.String())
+	assert.Error(t, err)
+}
+
+func TestSocketHandler_initSocketEndpoint_errors(t *testing.T) {
+	_, socketHandler, _ := startSocketHandler(t)
+	for endpoint, expectedError := range map[*SocketEndpoint]string{
+		{ID: "wrongProtocol", Protocol: "sctp"}:                                    "protocol 'sctp' of socket endpoint id 'wrongProtocol' must be 'tcp' or 'udp'",
+		{ID: "wrongPort", Port: 70000}:                                             "port 70000 of socket endpoint id 'wrongPort' is invalid",
+		{ID: "wrongFraming", Framing: "length"}:                                    "framing 'length' of socket endpoint id 'wrongFraming' must be 'line' or 'raw'",
+		{ID: "wrongRegexp", Request: &SocketMatchRequest{Regexp: "(["}}:            "error parsing request.regexp of socket endpoint id 'wrongRegexp'",
+		{ID: "wrongHex", Request: &SocketMatchRequest{HexPrefix: "xyz"}}:           "error parsing request.hexPrefix of socket endpoint id 'wrongHex'",
+		{ID: "wrongTemplate", Response: &SocketResponse{Body: "{{ .RequestBody "}}: "unclosed action",
+	} {
+		assert.ErrorContains(t, socketHandler.initSocketEndpoint(endpoint, nil), expectedError)
+	}
+}
+
+func TestMatchSocketMessage(t *testing.T) {
+	assert.True(t, matchSocketMessage(nil, []byte("anything")))
+	assert.True(t, matchSocketMessage(&SocketMatchRequest{Prefix: []byte{0x01, 0x02}}, []byte{0x01, 0x02, 0x03}))
+	assert.False(t, matchSocketMessage(&SocketMatchRequest{Prefix: []byte{0x01, 0x02}}, []byte{0x01}))
+	assert.False(t, matchSocketMessage(&SocketMatchRequest{Line: "PING"}, []byte("PING ")))
+}
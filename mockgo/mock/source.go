@@ -0,0 +1,455 @@
+package mock
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Source provides mockfiles and the files which are referenced by them, e.g. through response.bodyFilename
+*/
+type Source interface {
+	// Name returns a human readable name of the source
+	Name() string
+	// Open returns the current content of the source, remote sources are refreshed when they have changed
+	Open() (fs.FS, error)
+	// MockFiles returns the paths of the mockfiles in fsys matching the filename pattern
+	MockFiles(fsys fs.FS, pattern string, recursive bool) ([]string, error)
+}
+
+/*
+DefaultSourceSizeLimit is the default maximum number of bytes of a downloaded mockfile or bundle and of the uncompressed content of a bundle
+*/
+const DefaultSourceSizeLimit = 100 * 1024 * 1024
+
+/*
+NewFSSource creates a Source for a fs.FS, e.g. for mockfiles which are embedded in a go binary
+*/
+func NewFSSource(name string, fsys fs.FS) Source {
+	return &fsSource{name: name, fsys: fsys}
+}
+
+/*
+NewBundleSource creates a Source for a local '.tar.gz', '.tgz' or '.zip' file containing mockfiles
+*/
+func NewBundleSource(filename string) Source {
+	return &bundleSource{filename: filename, sizeLimit: DefaultSourceSizeLimit}
+}
+
+/*
+NewHTTPSource creates a Source for a mockfile or a bundle which is downloaded from an http(s) url.
+The content is refreshed with every Open call, using the ETag of the last download.
+*/
+func NewHTTPSource(sourceURL string, client *http.Client) (Source, error) {
+	parsedURL, err := url.Parse(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &httpSource{url: parsedURL, client: client, sizeLimit: DefaultSourceSizeLimit}, nil
+}
+
+/*
+sizeLimitedSource is a Source which reads its content into memory and fails when the content exceeds the size limit
+*/
+type sizeLimitedSource interface {
+	setSizeLimit(limit int64)
+}
+
+type fsSource struct {
+	name string
+	fsys fs.FS
+	file string
+}
+
+func (s *fsSource) Name() string {
+	return s.name
+}
+
+func (s *fsSource) Open() (fs.FS, error) {
+	return s.fsys, nil
+}
+
+func (s *fsSource) MockFiles(fsys fs.FS, pattern string, recursive bool) ([]string, error) {
+	if len(s.file) > 0 {
+		return []string{s.file}, nil
+	}
+	return walkMatch(fsys, pattern, recursive)
+}
+
+type bundleSource struct {
+	filename  string
+	sizeLimit int64
+}
+
+func (s *bundleSource) Name() string {
+	return s.filename
+}
+
+func (s *bundleSource) Open() (fs.FS, error) {
+	file, err := os.Open(s.filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	content, err := readLimited(file, s.filename, s.sizeLimit)
+	if err != nil {
+		return nil, err
+	}
+	return openBundle(s.filename, content, s.sizeLimit)
+}
+
+func (s *bundleSource) setSizeLimit(limit int64) {
+	s.sizeLimit = limit
+}
+
+func (s *bundleSource) MockFiles(fsys fs.FS, pattern string, recursive bool) ([]string, error) {
+	return walkMatch(fsys, pattern, recursive)
+}
+
+type httpSource struct {
+	url       *url.URL
+	client    *http.Client
+	mutex     sync.Mutex
+	etag      string
+	content   []byte
+	sizeLimit int64
+}
+
+func (s *httpSource) Name() string {
+	return s.url.String()
+}
+
+func (s *httpSource) Open() (fs.FS, error) {
+	content, sizeLimit, err := s.fetch()
+	if err != nil {
+		return nil, err
+	}
+	if isBundle(s.url.Path) {
+		return openBundle(s.url.Path, content, sizeLimit)
+	}
+	return &httpFS{base: s.url, client: s.client, mockFile: path.Base(s.url.Path), mockFileContent: content, sizeLimit: sizeLimit}, nil
+}
+
+func (s *httpSource) setSizeLimit(limit int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sizeLimit = limit
+}
+
+func (s *httpSource) MockFiles(fsys fs.FS, pattern string, recursive bool) ([]string, error) {
+	if httpFsys, ok := fsys.(*httpFS); ok {
+		return []string{httpFsys.mockFile}, nil
+	}
+	return walkMatch(fsys, pattern, recursive)
+}
+
+/*
+fetch downloads the content of the source and returns it together with the size limit which applies to it
+*/
+func (s *httpSource) fetch() ([]byte, int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	request, err := http.NewRequest(http.MethodGet, s.url.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(s.etag) > 0 && s.content != nil {
+		request.Header.Set("If-None-Match", s.etag)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return s.content, s.sizeLimit, nil
+	case http.StatusOK:
+		content, err := readLimited(response.Body, s.url.String(), s.sizeLimit)
+		if err != nil {
+			return nil, 0, err
+		}
+		s.content = content
+		s.etag = response.Header.Get("ETag")
+		return content, s.sizeLimit, nil
+	default:
+		return nil, 0, fmt.Errorf("can't download mock source '%s': %s", s.url, response.Status)
+	}
+}
+
+/*
+httpFS is a fs.FS which contains a downloaded mockfile, further files are downloaded relative to the url of the mockfile
+*/
+type httpFS struct {
+	base            *url.URL
+	client          *http.Client
+	mockFile        string
+	mockFileContent []byte
+	sizeLimit       int64
+}
+
+func (h *httpFS) Open(name string) (fs.File, error) {
+	if name == h.mockFile {
+		return memFS{name: {data: h.mockFileContent}}.Open(name)
+	}
+	fileURL, err := h.base.Parse(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	response, err := h.client.Get(fileURL.String())
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.New(response.Status)}
+	}
+	content, err := readLimited(response.Body, fileURL.String(), h.sizeLimit)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return memFS{name: {data: content}}.Open(name)
+}
+
+/*
+localFS is a fs.FS for a local directory, which in contrast to os.DirFS allows to reference files outside of the directory
+*/
+type localFS string
+
+func (l localFS) Open(name string) (fs.File, error) {
+	return os.Open(filepath.Join(string(l), filepath.FromSlash(name)))
+}
+
+/*
+memFS is a read-only fs.FS for files in memory, the directories are derived from the paths of the files
+*/
+type memFS map[string]*memFile
+
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if file, ok := m[name]; ok {
+		return &openMemFile{Reader: bytes.NewReader(file.data), info: file.info(path.Base(name))}, nil
+	}
+	entries, err := m.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &openMemDir{info: &memFileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+/*
+ReadDir returns the files and the subdirectories of a directory sorted by name
+*/
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	entries := map[string]fs.DirEntry{}
+	for filePath, file := range m {
+		if !strings.HasPrefix(filePath, prefix) {
+			continue
+		}
+		entryName := strings.TrimPrefix(filePath, prefix)
+		if i := strings.Index(entryName, "/"); i >= 0 {
+			entries[entryName[:i]] = fs.FileInfoToDirEntry(&memFileInfo{name: entryName[:i], dir: true})
+		} else {
+			entries[entryName] = fs.FileInfoToDirEntry(file.info(entryName))
+		}
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sorted := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+	return sorted, nil
+}
+
+func (f *memFile) info(name string) *memFileInfo {
+	return &memFileInfo{name: name, size: int64(len(f.data)), modTime: f.modTime}
+}
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i *memFileInfo) Name() string       { return i.name }
+func (i *memFileInfo) Size() int64        { return i.size }
+func (i *memFileInfo) ModTime() time.Time { return i.modTime }
+func (i *memFileInfo) IsDir() bool        { return i.dir }
+func (i *memFileInfo) Sys() any           { return nil }
+func (i *memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type openMemFile struct {
+	*bytes.Reader
+	info *memFileInfo
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+type openMemDir struct {
+	info    *memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openMemDir) Close() error               { return nil }
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *openMemDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	entries := d.entries[d.offset:]
+	if count > 0 && len(entries) == 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && count < len(entries) {
+		entries = entries[:count]
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
+
+func isBundle(filename string) bool {
+	return strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz") || strings.HasSuffix(filename, ".zip")
+}
+
+/*
+readLimited reads the content of a mock source, an error is returned when it exceeds the size limit
+*/
+func readLimited(reader io.Reader, name string, sizeLimit int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, sizeLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > sizeLimit {
+		return nil, fmt.Errorf("mock source '%s' exceeds the size limit of %d bytes", name, sizeLimit)
+	}
+	return content, nil
+}
+
+/*
+openBundle extracts the files of a bundle into memory, an error is returned when their total size exceeds the size limit
+*/
+func openBundle(filename string, content []byte, sizeLimit int64) (fs.FS, error) {
+	if strings.HasSuffix(filename, ".zip") {
+		return openZipBundle(filename, content, sizeLimit)
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	mapFS := memFS{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := readLimited(tarReader, filename, sizeLimit)
+		if err != nil {
+			return nil, err
+		}
+		sizeLimit -= int64(len(data))
+		mapFS[path.Clean(strings.TrimPrefix(header.Name, "/"))] = &memFile{data: data, modTime: header.ModTime}
+	}
+	return mapFS, nil
+}
+
+func openZipBundle(filename string, content []byte, sizeLimit int64) (fs.FS, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	mapFS := memFS{}
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := readLimited(entry, filename, sizeLimit)
+		entry.Close()
+		if err != nil {
+			return nil, err
+		}
+		sizeLimit -= int64(len(data))
+		mapFS[path.Clean(strings.TrimPrefix(file.Name, "/"))] = &memFile{data: data, modTime: file.Modified}
+	}
+	return mapFS, nil
+}
+
+func walkMatch(fsys fs.FS, pattern string, recursive bool) ([]string, error) {
+	var matches []string
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if !recursive && filePath != "." {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if matched, err := path.Match(pattern, path.Base(filePath)); err != nil {
+			return err
+		} else if matched {
+			matches = append(matches, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
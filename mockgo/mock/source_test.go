@@ -0,0 +1,188 @@
+package mock
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/stretchr/testify/assert"
+)
+
+var sourceMockFile = []byte(`endpoints:
+  - id: "fromSource"
+    request:
+      path: "/source"
+    response:
+      bodyFilename: "source-response.json"
+`)
+
+var sourceResponse = []byte(`{ "from": "source" }`)
+
+func assertSourceEndpoint(t *testing.T, mockRequestHandler *RequestHandler, path string) {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	endpoint, match, _, pathParams, queryParams := mockRequestHandler.matchRequestToEndpoint(request)
+	if assert.NotNil(t, endpoint, "no endpoint found for path '%s'", path) {
+		recorder := httptest.NewRecorder()
+		mockRequestHandler.renderResponse(recorder, request, endpoint, match, pathParams, queryParams)
+		assert.Equal(t, "fromSource", endpoint.ID)
+		assert.Equal(t, string(sourceResponse), recorder.Body.String())
+	}
+}
+
+func TestMockRequestHandler_LoadFiles_fsSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"source-mock.yaml":     {Data: sourceMockFile},
+		"source-response.json": {Data: sourceResponse},
+	}
+	mockRequestHandler := NewRequestHandler("/__", "", "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	mockRequestHandler.AddMockDir(&MockDir{PathPrefix: "/embedded", Source: NewFSSource("embedded", fsys)})
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assertSourceEndpoint(t, mockRequestHandler, "/embedded/source")
+}
+
+func TestMockRequestHandler_LoadFiles_bundleSources(t *testing.T) {
+	dir := t.TempDir()
+
+	var tarGz bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tarGz)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range map[string][]byte{"source-mock.yaml": sourceMockFile, "source-response.json": sourceResponse} {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mocks.tar.gz"), tarGz.Bytes(), 0644))
+
+	var zipContent bytes.Buffer
+	zipWriter := zip.NewWriter(&zipContent)
+	for name, content := range map[string][]byte{"source-mock.yaml": sourceMockFile, "source-response.json": sourceResponse} {
+		writer, err := zipWriter.Create(name)
+		assert.NoError(t, err)
+		_, err = writer.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zipWriter.Close())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mocks.zip"), zipContent.Bytes(), 0644))
+
+	mockRequestHandler := NewRequestHandler("/__", filepath.Join(dir, "mocks.tar.gz")+"=/targz,"+filepath.Join(dir, "mocks.zip")+"=/zip",
+		"*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assertSourceEndpoint(t, mockRequestHandler, "/targz/source")
+	assertSourceEndpoint(t, mockRequestHandler, "/zip/source")
+}
+
+func TestMockRequestHandler_LoadFiles_httpSource(t *testing.T) {
+	mockFileDownloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/mocks/source-mock.yaml":
+			if request.Header.Get("If-None-Match") == `"v1"` {
+				writer.WriteHeader(http.StatusNotModified)
+				return
+			}
+			mockFileDownloads++
+			writer.Header().Set("ETag", `"v1"`)
+			writer.Write(sourceMockFile)
+		case "/mocks/source-response.json":
+			writer.Write(sourceResponse)
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockRequestHandler := NewRequestHandler("/__", server.URL+"/mocks/source-mock.yaml=/remote", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assertSourceEndpoint(t, mockRequestHandler, "/remote/source")
+
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	assertSourceEndpoint(t, mockRequestHandler, "/remote/source")
+	assert.Equal(t, 1, mockFileDownloads)
+}
+
+func TestHTTPSource_Open_error(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	source, err := NewHTTPSource(server.URL+"/notexists-mock.yaml", nil)
+	assert.NoError(t, err)
+	_, err = source.Open()
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestMockRequestHandler_LoadFiles_sourceSizeLimit(t *testing.T) {
+	var tarGz bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tarGz)
+	tarWriter := tar.NewWriter(gzipWriter)
+	bomb := make([]byte, 64*1024)
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "bomb.json", Mode: 0644, Size: int64(len(bomb)), Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write(bomb)
+	assert.NoError(t, err)
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+
+	var zipContent bytes.Buffer
+	zipWriter := zip.NewWriter(&zipContent)
+	writer, err := zipWriter.Create("bomb.json")
+	assert.NoError(t, err)
+	_, err = writer.Write(bomb)
+	assert.NoError(t, err)
+	assert.NoError(t, zipWriter.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/mocks.tar.gz":
+			writer.Write(tarGz.Bytes())
+		case "/mocks.zip":
+			writer.Write(zipContent.Bytes())
+		case "/source-mock.yaml":
+			writer.Write(sourceMockFile)
+		case "/source-response.json":
+			writer.Write(bomb)
+		}
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/mocks.tar.gz", "/mocks.zip"} {
+		mockRequestHandler := NewRequestHandler("/__", server.URL+path, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+		assert.NoError(t, mockRequestHandler.LoadFiles(), "bundle '%s' within the default limit", path)
+
+		mockRequestHandler = NewRequestHandler("/__", server.URL+path, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+		mockRequestHandler.SetSourceSizeLimit(int64(len(bomb)) - 1)
+		assert.ErrorContains(t, mockRequestHandler.LoadFiles(), "exceeds the size limit of 65535 bytes", "uncompressed content of bundle '%s'", path)
+
+		mockRequestHandler = NewRequestHandler("/__", server.URL+path, "*-mock.yaml", false, matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+		mockRequestHandler.SetSourceSizeLimit(100)
+		assert.ErrorContains(t, mockRequestHandler.LoadFiles(), "exceeds the size limit of 100 bytes", "download of bundle '%s'", path)
+	}
+
+	source, err := NewHTTPSource(server.URL+"/source-mock.yaml", nil)
+	assert.NoError(t, err)
+	source.(sizeLimitedSource).setSizeLimit(int64(len(sourceMockFile)))
+	fsys, err := source.Open()
+	assert.NoError(t, err)
+	_, err = fs.ReadFile(fsys, "source-response.json")
+	assert.ErrorContains(t, err, "exceeds the size limit")
+}
+
+func TestMemFS(t *testing.T) {
+	fsys := memFS{
+		"mocks/orders-mock.yaml":   {data: sourceMockFile},
+		"mocks/data/response.json": {data: sourceResponse},
+		"source-response.json":     {data: sourceResponse},
+	}
+	assert.NoError(t, fstest.TestFS(fsys, "mocks/orders-mock.yaml", "mocks/data/response.json", "source-response.json"))
+	_, err := fsys.Open("notexists.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,131 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/go-http-utils/headers"
+)
+
+const templateResponseStream = "responseStream"
+
+func streamEventTemplateName(index int, field string) string {
+	return fmt.Sprintf("%s-%d-%s", templateResponseStream, index, field)
+}
+
+/*
+initStream parses the templates of the events of a streamed response
+*/
+func (r *RequestHandler) initStream(endpoint *Endpoint) error {
+	stream := endpoint.Response.Stream
+	if stream == nil {
+		return nil
+	}
+	if len(endpoint.Response.Body) > 0 || len(endpoint.Response.BodyFilename) > 0 || hasRawBody(endpoint.Response) {
+		return fmt.Errorf("error parsing endpoint id '%s' , response.stream can't be defined together with a response body", endpoint.ID)
+	}
+	if len(stream.Events) == 0 {
+		return fmt.Errorf("error parsing endpoint id '%s' , response.stream must have at least one event", endpoint.ID)
+	}
+	var loopDelay time.Duration
+	for i, event := range stream.Events {
+		if len(event.Delay) > 0 {
+			delay, err := time.ParseDuration(event.Delay)
+			if err != nil {
+				return fmt.Errorf("error parsing delay of event %d of endpoint id '%s': %v", i, endpoint.ID, err)
+			}
+			event.DelayDuration = delay
+			loopDelay += delay
+		}
+		for field, text := range map[string]string{"event": event.Event, "id": event.ID, "data": event.Data} {
+			if _, err := endpoint.Response.Template.New(streamEventTemplateName(i, field)).Parse(text); err != nil {
+				return err
+			}
+		}
+	}
+	if stream.Loop && loopDelay <= 0 {
+		return fmt.Errorf("error parsing endpoint id '%s' , response.stream with loop must have at least one event with a delay", endpoint.ID)
+	}
+	return nil
+}
+
+func (r *RequestHandler) renderStreamEvent(endpoint *Endpoint, index int, responseTemplateData *responseTemplateData) ([]byte, error) {
+	rendered := map[string]string{}
+	for _, field := range []string{"event", "id", "data"} {
+		var renderedField bytes.Buffer
+		if err := endpoint.Response.Template.ExecuteTemplate(&renderedField, streamEventTemplateName(index, field), responseTemplateData); err != nil {
+			return nil, err
+		}
+		rendered[field] = renderedField.String()
+	}
+	var event bytes.Buffer
+	if len(rendered["event"]) > 0 {
+		fmt.Fprintf(&event, "event: %s\n", rendered["event"])
+	}
+	if len(rendered["id"]) > 0 {
+		fmt.Fprintf(&event, "id: %s\n", rendered["id"])
+	}
+	if retry := endpoint.Response.Stream.Events[index].Retry; retry > 0 {
+		fmt.Fprintf(&event, "retry: %d\n", retry)
+	}
+	for _, line := range strings.Split(rendered["data"], "\n") {
+		fmt.Fprintf(&event, "data: %s\n", line)
+	}
+	event.WriteString("\n")
+	return event.Bytes(), nil
+}
+
+/*
+serveStream writes the events of a streamed response as server-sent events until the client disconnects,
+the number of delivered events is recorded in the match, which is stored when the stream ends
+*/
+func (r *RequestHandler) serveStream(writer http.ResponseWriter, request *http.Request, endpoint *Endpoint, match *matches.Match, responseTemplateData *responseTemplateData, responseStatus int) error {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("streaming is not supported by the response writer")
+	}
+	if len(writer.Header().Get(headers.ContentType)) == 0 {
+		writer.Header().Set(headers.ContentType, "text/event-stream")
+	}
+	if len(writer.Header().Get(headers.CacheControl)) == 0 {
+		writer.Header().Set(headers.CacheControl, "no-cache")
+	}
+	match.ActualResponse = r.newActualResponse(match, responseStatus, writer.Header(), nil)
+	writer.WriteHeader(responseStatus)
+	flusher.Flush()
+
+	streamedEvents := 0
+	defer func() {
+		match.ActualResponse.StreamedEvents = streamedEvents
+	}()
+	events := endpoint.Response.Stream.Events
+	for i := 0; endpoint.Response.Stream.Loop || i < len(events); i++ {
+		index := i % len(events)
+		if delay := events[index].DelayDuration; delay > 0 {
+			select {
+			case <-request.Context().Done():
+				return nil
+			case <-time.After(delay):
+			}
+		} else if request.Context().Err() != nil {
+			return nil
+		}
+		responseTemplateData.StreamedEvents = streamedEvents
+		event, err := r.renderStreamEvent(endpoint, index, responseTemplateData)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("Error rendering event %d of stream of endpoint '%s': %v", index, endpoint.ID, err))
+			return nil
+		}
+		if _, err := writer.Write(event); err != nil {
+			r.logger.Debug(fmt.Sprintf("stream of endpoint '%s' closed after %d events: %v", endpoint.ID, streamedEvents, err))
+			return nil
+		}
+		flusher.Flush()
+		streamedEvents++
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/alitari/mockgo-server/mockgo/testutil"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockRequestHandler_serving_stream(t *testing.T) {
+	expectedBody := "event: orders\nid: 0\nretry: 1000\ndata: first line\ndata: second line\n\n" +
+		"event: orders\nid: 1\ndata: { \"done\": true }\n\n"
+	assert.NoError(t, testutil.AssertResponseOfRequestCall(t,
+		testutil.CreateOutgoingRequest(t, http.MethodGet, "/stream/orders", testutil.CreateHeader(), ""),
+		func(response *http.Response, responseBody string) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+			assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+			assert.Equal(t, expectedBody, responseBody)
+		}))
+}
+
+func TestMockRequestHandler_serving_streamLoop_clientDisconnect(t *testing.T) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "stream-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	server := httptest.NewServer(router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/streamloop", nil)
+	assert.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	reader := bufio.NewReader(response.Body)
+	for i := 0; i < 3; i++ {
+		for line := ""; line != "\n"; {
+			line, err = reader.ReadString('\n')
+			assert.NoError(t, err)
+		}
+	}
+	cancel()
+	io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+	server.Close() // waits until the stream is finished
+
+	streamMatches, err := matchstore.GetMatches("streamLoop")
+	assert.NoError(t, err)
+	if assert.Len(t, streamMatches, 1) {
+		assert.GreaterOrEqual(t, streamMatches[0].ActualResponse.StreamedEvents, 3)
+	}
+}
+
+func TestMockRequestHandler_initStream_errors(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	err := mockRequestHandler.initResponseTemplates(&Endpoint{ID: "bodyAndStream", Response: &Response{Body: "body",
+		Stream: &Stream{Events: []*StreamEvent{{Data: "data"}}}}}, nil, nil)
+	assert.ErrorContains(t, err, "response.stream can't be defined together with a response body")
+	err = mockRequestHandler.initResponseTemplates(&Endpoint{ID: "noEvents", Response: &Response{Stream: &Stream{}}}, nil, nil)
+	assert.ErrorContains(t, err, "response.stream must have at least one event")
+	err = mockRequestHandler.initResponseTemplates(&Endpoint{ID: "wrongDelay", Response: &Response{
+		Stream: &Stream{Events: []*StreamEvent{{Data: "data", Delay: "soon"}}}}}, nil, nil)
+	assert.ErrorContains(t, err, "error parsing delay of event 0 of endpoint id 'wrongDelay'")
+	err = mockRequestHandler.initResponseTemplates(&Endpoint{ID: "loopWithoutDelay", Response: &Response{
+		Stream: &Stream{Loop: true, Events: []*StreamEvent{{Data: "first"}, {Data: "second", Delay: "0s"}}}}}, nil, nil)
+	assert.ErrorContains(t, err, "response.stream with loop must have at least one event with a delay")
+}
@@ -0,0 +1,272 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/websocket"
+)
+
+const templateResponseWebSocket = "responseWebSocket"
+const templateResponseWebSocketPush = "responseWebSocketPush"
+
+const webSocketCloseTimeout = 1 * time.Second
+
+func webSocketReplyTemplateName(stepIndex, replyIndex int) string {
+	return fmt.Sprintf("%s-%d-%d", templateResponseWebSocket, stepIndex, replyIndex)
+}
+
+func webSocketPushTemplateName(index int) string {
+	return fmt.Sprintf("%s-%d", templateResponseWebSocketPush, index)
+}
+
+/*
+initWebSocket parses the templates and matchers of a websocket response
+*/
+func (r *RequestHandler) initWebSocket(endpoint *Endpoint) error {
+	ws := endpoint.Response.WebSocket
+	if ws == nil {
+		return nil
+	}
+	if len(endpoint.Response.Body) > 0 || len(endpoint.Response.BodyFilename) > 0 || hasRawBody(endpoint.Response) || endpoint.Response.Stream != nil {
+		return fmt.Errorf("error parsing endpoint id '%s' , response.websocket can't be defined together with a response body or stream", endpoint.ID)
+	}
+	if err := initWebSocketScript(endpoint, ws.Script); err != nil {
+		return err
+	}
+	for i, push := range ws.Push {
+		interval, err := time.ParseDuration(push.Interval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("error parsing interval of push %d of endpoint id '%s': '%s' is not a positive duration", i, endpoint.ID, push.Interval)
+		}
+		push.IntervalDuration = interval
+		if _, err := endpoint.Response.Template.New(webSocketPushTemplateName(i)).Parse(push.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+initWebSocketScript parses the expected bodies and the reply templates of the steps of a websocket script
+*/
+func initWebSocketScript(endpoint *Endpoint, script []*WebSocketStep) error {
+	for i, step := range script {
+		if step.Expect != nil && len(step.Expect.Body) > 0 {
+			bodyRegexp, err := regexp.Compile(step.Expect.Body)
+			if err != nil {
+				return fmt.Errorf("error parsing expect.body of step %d of endpoint id '%s': %v", i, endpoint.ID, err)
+			}
+			step.Expect.BodyRegexp = bodyRegexp
+		}
+		for j, reply := range step.Reply {
+			if _, err := endpoint.Response.Template.New(webSocketReplyTemplateName(i, j)).Parse(reply); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+lookupJSONPath returns the value of a dot separated path, e.g. 'items.0.name', in a json document
+*/
+func lookupJSONPath(data interface{}, jsonPath string) (interface{}, bool) {
+	for _, key := range strings.Split(jsonPath, ".") {
+		switch node := data.(type) {
+		case map[string]interface{}:
+			val, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			data = val
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			data = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+func matchWebSocketMessage(expect *WebSocketExpect, message []byte) bool {
+	if expect == nil {
+		return true
+	}
+	if expect.BodyRegexp != nil && !expect.BodyRegexp.Match(message) {
+		return false
+	}
+	if len(expect.JSON) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(message, &data); err != nil {
+			return false
+		}
+		for jsonPath, expected := range expect.JSON {
+			val, ok := lookupJSONPath(data, jsonPath)
+			if !ok || fmt.Sprint(val) != expected {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type webSocketConn struct {
+	*websocket.Conn
+	writeLock sync.Mutex
+}
+
+func (c *webSocketConn) writeText(message []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	return c.WriteMessage(websocket.TextMessage, message)
+}
+
+func (c *webSocketConn) close(code int, reason string) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	return c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(webSocketCloseTimeout))
+}
+
+/*
+isWebSocketOriginAllowed accepts requests without an origin, from the same origin and from the allowed origins, '*' allows any origin
+*/
+func isWebSocketOriginAllowed(request *http.Request, allowedOrigins []string) bool {
+	origin := request.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	if originURL, err := url.Parse(origin); err == nil && strings.EqualFold(originURL.Host, request.Host) {
+		return true
+	}
+	for _, allowedOrigin := range allowedOrigins {
+		if allowedOrigin == "*" || strings.EqualFold(strings.TrimSuffix(allowedOrigin, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+serveWebSocket upgrades the connection to a websocket and runs the script and the pushes of the endpoint.
+Inbound messages are recorded in the match, which is stored when the connection is closed.
+A message which doesn't match the script closes the connection with a policy violation.
+*/
+func (r *RequestHandler) serveWebSocket(writer http.ResponseWriter, request *http.Request, endpoint *Endpoint, match *matches.Match, responseTemplateData *responseTemplateData) error {
+	if !websocket.IsWebSocketUpgrade(request) {
+		return fmt.Errorf("endpoint '%s' expects a websocket upgrade request", endpoint.ID)
+	}
+	responseHeader := writer.Header().Clone()
+	responseHeader.Del("Sec-Websocket-Extensions")
+	upgrader := websocket.Upgrader{CheckOrigin: func(request *http.Request) bool {
+		return isWebSocketOriginAllowed(request, endpoint.Response.WebSocket.Origins)
+	}}
+	wsConn, err := upgrader.Upgrade(writer, request, responseHeader)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("Error upgrading endpoint '%s' to websocket: %v", endpoint.ID, err))
+		return nil
+	}
+	conn := &webSocketConn{Conn: wsConn}
+	defer conn.Close()
+	match.ActualResponse = r.newActualResponse(match, http.StatusSwitchingProtocols, responseHeader, nil)
+	var actualMessages []*matches.ActualMessage
+	defer func() {
+		match.ActualMessages = actualMessages
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+	for i, push := range endpoint.Response.WebSocket.Push {
+		go r.pushWebSocketMessages(conn, endpoint, i, push, *responseTemplateData, done)
+	}
+
+	script := endpoint.Response.WebSocket.Script
+	for step := 0; ; step++ {
+		if step < len(script) && script[step].Expect == nil {
+			if closed := r.runWebSocketStep(conn, endpoint, step, responseTemplateData); closed {
+				return nil
+			}
+			continue
+		}
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			r.logger.Debug(fmt.Sprintf("websocket of endpoint '%s' closed: %v", endpoint.ID, err))
+			return nil
+		}
+		actualMessages = append(actualMessages, &matches.ActualMessage{Timestamp: time.Now(), Binary: messageType == websocket.BinaryMessage, Data: string(message)})
+		if step >= len(script) {
+			continue
+		}
+		if !matchWebSocketMessage(script[step].Expect, message) {
+			r.logger.Info(fmt.Sprintf("websocket message '%s' doesn't match step %d of endpoint '%s'", message, step, endpoint.ID))
+			conn.close(websocket.ClosePolicyViolation, fmt.Sprintf("unexpected message in step %d", step))
+			return nil
+		}
+		responseTemplateData.WebSocketMessage = string(message)
+		responseTemplateData.WebSocketMessageJSONData = nil
+		messageData := map[string]interface{}{}
+		if err := json.Unmarshal(message, &messageData); err == nil { // ignore when no json
+			responseTemplateData.WebSocketMessageJSONData = messageData
+		}
+		if closed := r.runWebSocketStep(conn, endpoint, step, responseTemplateData); closed {
+			return nil
+		}
+	}
+}
+
+func (r *RequestHandler) runWebSocketStep(conn *webSocketConn, endpoint *Endpoint, step int, responseTemplateData *responseTemplateData) bool {
+	for i := range endpoint.Response.WebSocket.Script[step].Reply {
+		var reply bytes.Buffer
+		if err := endpoint.Response.Template.ExecuteTemplate(&reply, webSocketReplyTemplateName(step, i), responseTemplateData); err != nil {
+			r.logger.Error(fmt.Sprintf("Error rendering reply %d of step %d of endpoint '%s': %v", i, step, endpoint.ID, err))
+			conn.close(websocket.CloseInternalServerErr, "error rendering reply")
+			return true
+		}
+		if err := conn.writeText(reply.Bytes()); err != nil {
+			return true
+		}
+	}
+	if wsClose := endpoint.Response.WebSocket.Script[step].Close; wsClose != nil {
+		code := wsClose.Code
+		if code == 0 {
+			code = websocket.CloseNormalClosure
+		}
+		conn.close(code, wsClose.Reason)
+		return true
+	}
+	return false
+}
+
+func (r *RequestHandler) pushWebSocketMessages(conn *webSocketConn, endpoint *Endpoint, index int, push *WebSocketPush, responseTemplateData responseTemplateData, done chan struct{}) {
+	ticker := time.NewTicker(push.IntervalDuration)
+	defer ticker.Stop()
+	for pushed := 0; push.Count == 0 || pushed < push.Count; pushed++ {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		responseTemplateData.PushedMessages = pushed
+		var message bytes.Buffer
+		if err := endpoint.Response.Template.ExecuteTemplate(&message, webSocketPushTemplateName(index), &responseTemplateData); err != nil {
+			r.logger.Error(fmt.Sprintf("Error rendering push %d of endpoint '%s': %v", index, endpoint.ID, err))
+			return
+		}
+		if err := conn.writeText(message.Bytes()); err != nil {
+			return
+		}
+	}
+}
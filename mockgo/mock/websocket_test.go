@@ -0,0 +1,146 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alitari/mockgo-server/mockgo/matches"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func startWebSocketServer(t *testing.T) (matches.Matchstore, *httptest.Server) {
+	matchstore := matches.NewInMemoryMatchstore(uint16(100))
+	mockRequestHandler := NewRequestHandler("/__", "../../test/mocks", "websocket-mock.yaml", false, matchstore, nil, "DEBUG")
+	assert.NoError(t, mockRequestHandler.LoadFiles())
+	router := mux.NewRouter()
+	mockRequestHandler.AddRoutes(router)
+	return matchstore, httptest.NewServer(router)
+}
+
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) string {
+	_, message, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	return string(message)
+}
+
+func TestMockRequestHandler_serving_websocketScript(t *testing.T) {
+	matchstore, server := startWebSocketServer(t)
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/news", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "news", response.Header.Get("X-Channel"))
+	assert.Equal(t, `{ "type": "welcome", "channel": "news" }`, readWebSocketMessage(t, conn))
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{ "type": "subscribe", "topic": "sports" }`)))
+	assert.Equal(t, `{ "type": "subscribed", "topic": "sports" }`, readWebSocketMessage(t, conn))
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("ping")))
+	assert.Equal(t, "pong", readWebSocketMessage(t, conn))
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("bye")))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, 4000), "expected close error with code 4000, got: %v", err)
+	conn.Close()
+	server.Close()
+
+	var websocketMatches []*matches.Match
+	assert.Eventually(t, func() bool { // the match is stored when the hijacked connection is closed
+		websocketMatches, err = matchstore.GetMatches("websocket")
+		return err == nil && len(websocketMatches) > 0
+	}, time.Second, 10*time.Millisecond)
+	if assert.Len(t, websocketMatches, 1) {
+		assert.Equal(t, http.StatusSwitchingProtocols, websocketMatches[0].ActualResponse.StatusCode)
+		if assert.Len(t, websocketMatches[0].ActualMessages, 3) {
+			assert.Equal(t, `{ "type": "subscribe", "topic": "sports" }`, websocketMatches[0].ActualMessages[0].Data)
+			assert.Equal(t, "ping", websocketMatches[0].ActualMessages[1].Data)
+			assert.Equal(t, "bye", websocketMatches[0].ActualMessages[2].Data)
+		}
+	}
+}
+
+func TestMockRequestHandler_serving_websocketUnexpectedMessage(t *testing.T) {
+	_, server := startWebSocketServer(t)
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/news", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	readWebSocketMessage(t, conn)
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{ "type": "unsubscribe" }`)))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "expected policy violation, got: %v", err)
+}
+
+func TestMockRequestHandler_serving_websocketPush(t *testing.T) {
+	_, server := startWebSocketServer(t)
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/wspush", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	for i, expected := range []string{"tick 0", "tick 1", "tick 2"} {
+		assert.Equal(t, expected, readWebSocketMessage(t, conn), "push %d", i)
+	}
+}
+
+func TestMockRequestHandler_serving_websocketOrigin(t *testing.T) {
+	_, server := startWebSocketServer(t)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	for _, test := range []struct {
+		path, origin string
+		allowed      bool
+	}{
+		{"/ws/news", server.URL, true},
+		{"/ws/news", "http://evil.example.com", false},
+		{"/wspush", "http://localhost:3000", true},
+		{"/wspush", "http://evil.example.com", false},
+	} {
+		conn, response, err := websocket.DefaultDialer.Dial(wsURL+test.path, http.Header{"Origin": {test.origin}})
+		if test.allowed {
+			if assert.NoError(t, err, "origin '%s' for '%s'", test.origin, test.path) {
+				conn.Close()
+			}
+			continue
+		}
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake, "origin '%s' for '%s'", test.origin, test.path)
+		if assert.NotNil(t, response) {
+			assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		}
+	}
+}
+
+func TestMockRequestHandler_serving_websocketNoUpgrade(t *testing.T) {
+	_, server := startWebSocketServer(t)
+	defer server.Close()
+	response, err := http.Get(server.URL + "/wspush")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestMatchWebSocketMessage(t *testing.T) {
+	expect := &WebSocketExpect{JSON: map[string]string{"items.1.name": "second", "count": "2"}}
+	assert.True(t, matchWebSocketMessage(expect, []byte(`{ "count": 2, "items": [ { "name": "first" }, { "name": "second" } ] }`)))
+	assert.False(t, matchWebSocketMessage(expect, []byte(`{ "count": 2, "items": [ { "name": "first" } ] }`)))
+	assert.False(t, matchWebSocketMessage(expect, []byte(`no json`)))
+	assert.True(t, matchWebSocketMessage(nil, []byte(`anything`)))
+}
+
+func TestMockRequestHandler_initWebSocket_errors(t *testing.T) {
+	mockRequestHandler := NewRequestHandler("", "../../test/mocks", "*-mock.yaml", false,
+		matches.NewInMemoryMatchstore(uint16(100)), nil, "DEBUG")
+	err := mockRequestHandler.initResponseTemplates(&Endpoint{ID: "bodyAndWebsocket", Response: &Response{Body: "body", WebSocket: &WebSocket{}}}, nil, nil)
+	assert.ErrorContains(t, err, "response.websocket can't be defined together with a response body or stream")
+	err = mockRequestHandler.initResponseTemplates(&Endpoint{ID: "wrongRegexp", Response: &Response{WebSocket: &WebSocket{
+		Script: []*WebSocketStep{{Expect: &WebSocketExpect{Body: "(["}}}}}}, nil, nil)
+	assert.ErrorContains(t, err, "error parsing expect.body of step 0 of endpoint id 'wrongRegexp'")
+	err = mockRequestHandler.initResponseTemplates(&Endpoint{ID: "wrongInterval", Response: &Response{WebSocket: &WebSocket{
+		Push: []*WebSocketPush{{Interval: "0s", Message: "tick"}}}}}, nil, nil)
+	assert.ErrorContains(t, err, "error parsing interval of push 0 of endpoint id 'wrongInterval'")
+}
@@ -2,15 +2,20 @@ package starter
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/alitari/mockgo-server/mockgo/kvstore"
+	"github.com/alitari/mockgo-server/mockgo/mail"
 	"github.com/alitari/mockgo-server/mockgo/matches"
 	"github.com/alitari/mockgo-server/mockgo/mock"
 	"github.com/alitari/mockgo-server/mockgo/util"
@@ -18,6 +23,8 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -39,15 +46,36 @@ type RequestHandler interface {
 
 // BasicConfiguration is the basic configuration model of the server which is defined via environment variables
 type BasicConfiguration struct {
-	LoglevelAPI     string `default:"INFO" split_words:"true"`
-	LoglevelMock    string `default:"INFO" split_words:"true"`
-	MockPort        int    `default:"8081" split_words:"true"`
-	MockDir         string `default:"." split_words:"true"`
-	MockFilepattern string `default:"*-mock.*" split_words:"true"`
-	MatchesCapacity int    `default:"1000" split_words:"true"`
-	APIPathPrefix   string `default:"/__" split_words:"true"`
-	APIUsername     string `default:"mockgo" split_words:"true"`
-	APIPassword     string `default:"password" split_words:"true"`
+	LoglevelAPI               string        `default:"INFO" split_words:"true"`
+	LoglevelMock              string        `default:"INFO" split_words:"true"`
+	MockPort                  int           `default:"8081" split_words:"true"`
+	MockListeners             string        `split_words:"true"`
+	MockH2c                   bool          `default:"false" split_words:"true"`
+	MockTLSCertFile           string        `split_words:"true"`
+	MockTLSKeyFile            string        `split_words:"true"`
+	MockTLSSelfSigned         bool          `default:"false" split_words:"true"`
+	MockTLSSelfSignedHosts    []string      `default:"localhost,127.0.0.1" split_words:"true"`
+	MockTLSSelfSignedCAFile   string        `split_words:"true"`
+	MockTLSClientCAFile       string        `split_words:"true"`
+	MockTLSClientCertRequired bool          `default:"false" split_words:"true"`
+	MockDir                   string        `default:"." split_words:"true"`
+	MockDirRecursive          bool          `default:"false" split_words:"true"`
+	MockFilepattern           string        `default:"*-mock.*" split_words:"true"`
+	MockSourceSizeLimit       int64         `default:"104857600" split_words:"true"`
+	MockGrpcPort              int           `default:"0" split_words:"true"`
+	MockProtoFilepattern      string        `default:"*.proto,*.protoset" split_words:"true"`
+	MockSMTPPort              int           `default:"0" split_words:"true"`
+	MockSMTPMailbox           string        `default:"smtp" split_words:"true"`
+	MatchesCapacity           int           `default:"1000" split_words:"true"`
+	MatchesTTL                time.Duration `default:"0s" split_words:"true"`
+	MatchesMemoryLimit        int64         `default:"0" split_words:"true"`
+	MatchesBodyLimit          int           `default:"65536" split_words:"true"`
+	TemplateEnvAllowlist      []string      `split_words:"true"`
+	TemplateFileAllowlist     []string      `split_words:"true"`
+	APIPathPrefix             string        `default:"/__" split_words:"true"`
+	APIPort                   int           `default:"0" split_words:"true"`
+	APIUsername               string        `default:"mockgo" split_words:"true"`
+	APIPassword               string        `default:"password" split_words:"true"`
 }
 
 // Info returns a string with the configuration info
@@ -62,35 +90,76 @@ func (c *BasicConfiguration) Info() string {
 
 API: 
   Path prefix: '%s' ("API_PATH_PREFIX")
+  Port: %v ("API_PORT")
   BasicAuth User: '%s' ("API_USERNAME")
   BasicAuth Password: %s ("API_PASSWORD")
   LogLevel: '%v' ("LOGLEVEL_API")
 
 Mock Server:
   Port: %v ("MOCK_PORT")
+  Listeners: '%s' ("MOCK_LISTENERS")
+  H2C: %v ("MOCK_H2C")
+  TLS cert file: '%s' ("MOCK_TLS_CERT_FILE")
+  TLS key file: '%s' ("MOCK_TLS_KEY_FILE")
+  TLS self-signed: %v ("MOCK_TLS_SELF_SIGNED")
+  TLS self-signed hosts: %v ("MOCK_TLS_SELF_SIGNED_HOSTS")
+  TLS self-signed ca file: '%s' ("MOCK_TLS_SELF_SIGNED_CA_FILE")
+  TLS client ca file: '%s' ("MOCK_TLS_CLIENT_CA_FILE")
+  TLS client cert required: %v ("MOCK_TLS_CLIENT_CERT_REQUIRED")
   Dir: '%s' ("MOCK_DIR")
+  Dir recursive: %v ("MOCK_DIR_RECURSIVE")
   Filepattern: '%s' ("MOCK_FILEPATTERN")
+  Source size limit: %d ("MOCK_SOURCE_SIZE_LIMIT")
+  Grpc port: %v ("MOCK_GRPC_PORT")
+  Proto filepattern: '%s' ("MOCK_PROTO_FILEPATTERN")
+  SMTP port: %v ("MOCK_SMTP_PORT")
+  SMTP mailbox: '%s' ("MOCK_SMTP_MAILBOX")
   LogLevel: '%v' ("LOGLEVEL_MOCK")
+  Template env allowlist: %v ("TEMPLATE_ENV_ALLOWLIST")
+  Template file allowlist: %v ("TEMPLATE_FILE_ALLOWLIST")
   
 Matches:
   Capacity: %d ("MATCHES_CAPACITY")
+  TTL: %v ("MATCHES_TTL")
+  Memory limit: %d ("MATCHES_MEMORY_LIMIT")
+  Body limit: %d ("MATCHES_BODY_LIMIT")
   `,
-		c.APIPathPrefix, c.APIUsername, passwordMessage, c.LoglevelAPI,
-		c.MockPort, c.MockDir, c.MockFilepattern, c.LoglevelMock,
-		c.MatchesCapacity)
+		c.APIPathPrefix, c.APIPort, c.APIUsername, passwordMessage, c.LoglevelAPI,
+		c.MockPort, c.MockListeners, c.MockH2c, c.MockTLSCertFile, c.MockTLSKeyFile,
+		c.MockTLSSelfSigned, c.MockTLSSelfSignedHosts, c.MockTLSSelfSignedCAFile, c.MockTLSClientCAFile, c.MockTLSClientCertRequired,
+		c.MockDir, c.MockDirRecursive, c.MockFilepattern, c.MockSourceSizeLimit, c.MockGrpcPort, c.MockProtoFilepattern, c.MockSMTPPort, c.MockSMTPMailbox, c.LoglevelMock, c.TemplateEnvAllowlist, c.TemplateFileAllowlist,
+		c.MatchesCapacity, c.MatchesTTL, c.MatchesMemoryLimit, c.MatchesBodyLimit)
+}
+
+// MatchesRetention returns the retention of the matchstore
+func (c *BasicConfiguration) MatchesRetention() *matches.Retention {
+	return &matches.Retention{Capacity: c.MatchesCapacity, TTL: c.MatchesTTL, MemoryLimit: c.MatchesMemoryLimit}
 }
 
 // BasicConfig is the basic mock configuration
 var BasicConfig *BasicConfiguration
 
-var server *http.Server
+var servers []*http.Server
+
+var serversMutex sync.Mutex
+
+var tlsConfig *tls.Config
 
 // Serving is the function which starts the server
 var Serving func(router *mux.Router) error
 
+// ServingAPI is the function which starts the server for the api, when the api has an own port
+var ServingAPI func(router *mux.Router) error
+
+// ServingListener is the function which starts the server of a mock listener with an own port
+var ServingListener func(port int, router *mux.Router) error
+
 // Shutdown is the function which stops the server
 var Shutdown func() error
 
+// LeaderElection decides whether this instance runs the jobs of the mockfiles, without it every instance runs the jobs
+var LeaderElection mock.LeaderElection
+
 // logger is the basic logger
 var logger *zap.Logger
 
@@ -118,52 +187,87 @@ func SetupRouter(variant, versionTag, configInfo string, matchStore matches.Matc
 	fmt.Printf(banner, variant, versionTag)
 	logger.Info(BasicConfig.Info() + configInfo)
 	router := mux.NewRouter()
-	router.NewRoute().Name("health").Path(BasicConfig.APIPathPrefix + "/health").Methods(http.MethodGet).
+	apiRouter := router
+	if BasicConfig.APIPort > 0 {
+		apiRouter = mux.NewRouter()
+	}
+	apiRouter.NewRoute().Name("health").Path(BasicConfig.APIPathPrefix + "/health").Methods(http.MethodGet).
 		HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			writer.WriteHeader(http.StatusOK)
 			writer.Write([]byte("OK"))
 		})
 
-	router.Use(util.BasicAuthMiddleware(BasicConfig.APIPathPrefix, BasicConfig.APIUsername, BasicConfig.APIPassword))
-	mockHandler := mock.NewRequestHandler(BasicConfig.APIPathPrefix, BasicConfig.MockDir, BasicConfig.MockFilepattern, matchStore,
-		kvstore.NewKVStoreTemplateFuncMap(kvStore), BasicConfig.LoglevelMock)
+	apiRouter.Use(util.BasicAuthMiddleware(BasicConfig.APIPathPrefix, BasicConfig.APIUsername, BasicConfig.APIPassword))
+	mockHandler := newMockHandler(BasicConfig.MockDir, matchStore, kvStore)
+	var grpcHandler *mock.GrpcHandler
+	if BasicConfig.MockGrpcPort > 0 {
+		grpcHandler = mock.NewGrpcHandler(mockHandler, BasicConfig.MockProtoFilepattern)
+	}
+	socketHandler := mock.NewSocketHandler(mockHandler)
+	mock.NewJobScheduler(mockHandler, LeaderElection)
 	if err := mockHandler.LoadFiles(); err != nil {
 		logger.Fatal("can't load mockfiles", zap.Error(err))
 	}
+	mockListeners, err := mock.ParseMockListeners(BasicConfig.MockListeners, BasicConfig.MockDirRecursive)
+	if err != nil {
+		logger.Fatal("can't parse mock listeners", zap.Error(err))
+	}
 	matchHandler := matches.NewRequestHandler(BasicConfig.APIPathPrefix, matchStore, BasicConfig.LoglevelAPI)
+	matchHandler.SetVersion(versionTag)
 	kvHandler := kvstore.NewRequestHandler(BasicConfig.APIPathPrefix, kvStore, BasicConfig.LoglevelAPI)
+	mailHandler := mail.NewRequestHandler(BasicConfig.APIPathPrefix, BasicConfig.MockSMTPMailbox, matchStore, BasicConfig.LoglevelAPI)
 
-	mockHandler.AddRoutes(router)
-	matchHandler.AddRoutes(router)
-	kvHandler.AddRoutes(router)
+	mockHandler.AddAPIRoutes(apiRouter)
+	matchHandler.AddRoutes(apiRouter)
+	kvHandler.AddRoutes(apiRouter)
+	mailHandler.AddRoutes(apiRouter)
 
 	mock.RegisterMetrics()
-	router.NewRoute().Name("metrics").Path(BasicConfig.APIPathPrefix + "/metrics").Handler(promhttp.Handler())
+	matches.RegisterMetrics()
+	apiRouter.NewRoute().Name("metrics").Path(BasicConfig.APIPathPrefix + "/metrics").Handler(promhttp.Handler())
 
-	if Serving == nil {
-		Serving = DefaultServing
+	listenerRouters, mockHandlers, socketHandlers := addMockListeners(mockListeners, mockHandler, router, apiRouter, matchStore, kvStore)
+	mockHandler.AddMockRoutes(router, apiRouter == router)
+	matchHandler.SetEndpointIDs(func() []string {
+		var endpointIDs []string
+		for _, handler := range mockHandlers {
+			endpointIDs = append(endpointIDs, handler.EndpointIDs()...)
+		}
+		return endpointIDs
+	})
+
+	if len(BasicConfig.MockTLSCertFile) > 0 || BasicConfig.MockTLSSelfSigned {
+		tlsConfig, err = createTLSConfig()
+		if err != nil {
+			logger.Fatal("can't create tls configuration", zap.Error(err))
+		}
 	}
-	if Shutdown == nil {
-		Shutdown = DefaultShutdown
+	setDefaultServing()
+	var smtpServer *mail.SMTPServer
+	smtpPort, grpcPort := BasicConfig.MockSMTPPort, BasicConfig.MockGrpcPort
+	if smtpPort > 0 {
+		smtpServer = mail.NewSMTPServer(BasicConfig.MockSMTPMailbox, matchStore, BasicConfig.LoglevelMock)
 	}
 
 	g, gCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		return Serving(router)
 	})
+	if apiRouter != router {
+		g.Go(func() error {
+			return ServingAPI(apiRouter)
+		})
+	}
+	for port, listenerRouter := range listenerRouters {
+		port, listenerRouter := port, listenerRouter
+		g.Go(func() error {
+			return ServingListener(port, listenerRouter)
+		})
+	}
+	serveGrpcAndSMTP(g, grpcHandler, grpcPort, smtpServer, smtpPort)
 	g.Go(func() error {
 		<-gCtx.Done()
-
-		logger.Info("shutting down matchstore ...")
-		if err := matchStore.Shutdown(); err != nil {
-			logger.Error("can't shutdown matchstore", zap.Error(err))
-		}
-		logger.Info("shutting down kvstore ...")
-		if err := kvStore.Shutdown(); err != nil {
-			logger.Error("can't shutdown kvstore", zap.Error(err))
-		}
-		logger.Info("shutting down http server ...")
-		return Shutdown()
+		return shutdownServers(smtpServer, grpcHandler, append(socketHandlers, socketHandler), mockHandlers, matchStore, kvStore)
 	})
 
 	if err := g.Wait(); err != nil {
@@ -171,14 +275,199 @@ func SetupRouter(variant, versionTag, configInfo string, matchStore matches.Matc
 	}
 }
 
-// DefaultServing is the default server
-func DefaultServing(router *mux.Router) error {
-	server = &http.Server{Addr: ":" + strconv.Itoa(BasicConfig.MockPort), Handler: router}
-	logger.Info("serving http  ...", zap.String("address", server.Addr))
+// addMockListeners creates the request handlers of the mock listeners, it returns the routers of the listeners with an own port,
+// all mock handlers and the socket handlers of the listeners. The grpc server only serves the mockfiles of MOCK_DIR.
+func addMockListeners(mockListeners []*mock.MockListener, mockHandler *mock.RequestHandler, router, apiRouter *mux.Router, matchStore matches.Matchstore, kvStore kvstore.Storage) (map[int]*mux.Router, []*mock.RequestHandler, []*mock.SocketHandler) {
+	listenerRouters := map[int]*mux.Router{}
+	mockHandlers := []*mock.RequestHandler{mockHandler}
+	var socketHandlers []*mock.SocketHandler
+	for _, mockListener := range mockListeners {
+		listenerHandler := newMockHandler("", matchStore, kvStore)
+		listenerHandler.RejectGrpc()
+		socketHandlers = append(socketHandlers, mock.NewSocketHandler(listenerHandler))
+		mock.NewJobScheduler(listenerHandler, LeaderElection)
+		mockHandlers = append(mockHandlers, listenerHandler)
+		for _, mockDir := range mockListener.MockDirs {
+			listenerHandler.AddMockDir(mockDir)
+		}
+		if err := listenerHandler.LoadFiles(); err != nil {
+			logger.Fatal(fmt.Sprintf("can't load mockfiles of mock listener '%s'", mockListener.Name()), zap.Error(err))
+		}
+		mockHandler.ReloadWith(listenerHandler)
+		if mockListener.Port > 0 {
+			listenerRouter := mux.NewRouter()
+			listenerHandler.AddMockRoutes(listenerRouter, false)
+			listenerRouters[mockListener.Port] = listenerRouter
+		} else {
+			hostRouter := router.Host(mockListener.Host).Subrouter()
+			listenerHandler.AddMockRoutes(hostRouter, apiRouter == router)
+			hostRouter.NewRoute().HandlerFunc(http.NotFound) // requests to a virtual host are not matched with other mockfiles
+		}
+	}
+	return listenerRouters, mockHandlers, socketHandlers
+}
+
+// setDefaultServing sets the default functions for serving and shutdown, which aren't defined
+func setDefaultServing() {
+	if Serving == nil {
+		Serving = DefaultServing
+	}
+	if ServingAPI == nil {
+		ServingAPI = DefaultServingAPI
+	}
+	if ServingListener == nil {
+		ServingListener = DefaultServingListener
+	}
+	if Shutdown == nil {
+		Shutdown = DefaultShutdown
+	}
+}
+
+// serveGrpcAndSMTP starts the grpc server and the smtp server, when they are configured
+func serveGrpcAndSMTP(g *errgroup.Group, grpcHandler *mock.GrpcHandler, grpcPort int, smtpServer *mail.SMTPServer, smtpPort int) {
+	if grpcHandler != nil {
+		g.Go(func() error {
+			listener, err := net.Listen("tcp", ":"+strconv.Itoa(grpcPort))
+			if err != nil {
+				return err
+			}
+			return grpcHandler.Serve(listener)
+		})
+	}
+	if smtpServer != nil {
+		g.Go(func() error {
+			listener, err := net.Listen("tcp", ":"+strconv.Itoa(smtpPort))
+			if err != nil {
+				return err
+			}
+			return smtpServer.Serve(listener)
+		})
+	}
+}
+
+// shutdownServers stops all servers, jobs and callbacks and shuts down the stores
+func shutdownServers(smtpServer *mail.SMTPServer, grpcHandler *mock.GrpcHandler, socketHandlers []*mock.SocketHandler, mockHandlers []*mock.RequestHandler, matchStore matches.Matchstore, kvStore kvstore.Storage) error {
+	if smtpServer != nil {
+		logger.Info("shutting down smtp server ...")
+		smtpServer.Shutdown()
+	}
+	logger.Info("stopping jobs and cancelling pending callbacks ...")
+	for _, mockHandler := range mockHandlers {
+		mockHandler.ShutdownJobs()
+		mockHandler.ShutdownCallbacks()
+	}
+	shutdownLeaderElection()
+
+	logger.Info("shutting down matchstore ...")
+	if err := matchStore.Shutdown(); err != nil {
+		logger.Error("can't shutdown matchstore", zap.Error(err))
+	}
+	logger.Info("shutting down kvstore ...")
+	if err := kvStore.Shutdown(); err != nil {
+		logger.Error("can't shutdown kvstore", zap.Error(err))
+	}
+	if grpcHandler != nil {
+		logger.Info("shutting down grpc server ...")
+		grpcHandler.Shutdown()
+	}
+	logger.Info("shutting down socket listeners ...")
+	for _, socketHandler := range socketHandlers {
+		socketHandler.Shutdown()
+	}
+	logger.Info("shutting down http server ...")
+	return Shutdown()
+}
+
+// shutdownLeaderElection releases the leadership, a leader election which is the matchstore is shut down with the matchstore
+func shutdownLeaderElection() {
+	if _, isMatchstore := LeaderElection.(matches.Matchstore); isMatchstore {
+		return
+	}
+	if leaderElection, ok := LeaderElection.(interface{ Shutdown() error }); ok {
+		logger.Info("shutting down leader election ...")
+		if err := leaderElection.Shutdown(); err != nil {
+			logger.Error("can't shutdown leader election", zap.Error(err))
+		}
+	}
+}
+
+func createTLSConfig() (*tls.Config, error) {
+	var certificate tls.Certificate
+	var err error
+	if BasicConfig.MockTLSSelfSigned {
+		var caPEM []byte
+		certificate, caPEM, err = util.CreateSelfSignedCertificate(BasicConfig.MockTLSSelfSignedHosts)
+		if err != nil {
+			return nil, err
+		}
+		if len(BasicConfig.MockTLSSelfSignedCAFile) > 0 {
+			if err := os.WriteFile(BasicConfig.MockTLSSelfSignedCAFile, caPEM, 0644); err != nil {
+				return nil, fmt.Errorf("error writing self-signed ca file '%s': %v", BasicConfig.MockTLSSelfSignedCAFile, err)
+			}
+		}
+		logger.Info("created self-signed certificate, trust this ca:\n" + string(caPEM))
+	} else {
+		certificate, err = tls.LoadX509KeyPair(BasicConfig.MockTLSCertFile, BasicConfig.MockTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading tls certificate '%s': %v", BasicConfig.MockTLSCertFile, err)
+		}
+	}
+	return util.CreateTLSConfig(certificate, BasicConfig.MockTLSClientCAFile, BasicConfig.MockTLSClientCertRequired)
+}
+
+func newMockHandler(mockDir string, matchStore matches.Matchstore, kvStore kvstore.Storage) *mock.RequestHandler {
+	mockHandler := mock.NewRequestHandler(BasicConfig.APIPathPrefix, mockDir, BasicConfig.MockFilepattern, BasicConfig.MockDirRecursive, matchStore,
+		kvstore.NewKVStoreTemplateFuncMap(kvStore), BasicConfig.LoglevelMock)
+	mockHandler.SetTemplateAllowlists(BasicConfig.TemplateEnvAllowlist, BasicConfig.TemplateFileAllowlist)
+	mockHandler.SetMatchesBodyLimit(BasicConfig.MatchesBodyLimit)
+	mockHandler.SetSourceSizeLimit(BasicConfig.MockSourceSizeLimit)
+	return mockHandler
+}
+
+/*
+serve starts a server on the port, HTTP/2 is negotiated with TLS or used without TLS when h2c is enabled
+*/
+func serve(name string, port int, router *mux.Router) error {
+	var handler http.Handler = router
+	if BasicConfig.MockH2c {
+		handler = h2c.NewHandler(router, &http2.Server{})
+	}
+	server := &http.Server{Addr: ":" + strconv.Itoa(port), Handler: handler, TLSConfig: tlsConfig}
+	serversMutex.Lock()
+	servers = append(servers, server)
+	serversMutex.Unlock()
+	if tlsConfig != nil {
+		logger.Info("serving https ...", zap.String("server", name), zap.String("address", server.Addr))
+		return server.ListenAndServeTLS("", "")
+	}
+	logger.Info("serving http  ...", zap.String("server", name), zap.String("address", server.Addr))
 	return server.ListenAndServe()
 }
 
-// DefaultShutdown is the default shutdown
+// DefaultServing is the default server for the mocks
+func DefaultServing(router *mux.Router) error {
+	return serve("mock", BasicConfig.MockPort, router)
+}
+
+// DefaultServingAPI is the default server for the api
+func DefaultServingAPI(router *mux.Router) error {
+	return serve("api", BasicConfig.APIPort, router)
+}
+
+// DefaultServingListener is the default server for a mock listener
+func DefaultServingListener(port int, router *mux.Router) error {
+	return serve("mock listener", port, router)
+}
+
+// DefaultShutdown is the default shutdown, it stops all servers
 func DefaultShutdown() error {
-	return server.Shutdown(context.Background())
+	serversMutex.Lock()
+	defer serversMutex.Unlock()
+	var shutdownErr error
+	for _, server := range servers {
+		if err := server.Shutdown(context.Background()); err != nil && shutdownErr == nil {
+			shutdownErr = err
+		}
+	}
+	return shutdownErr
 }
@@ -0,0 +1,17 @@
+package util
+
+import "hash/fnv"
+
+/*
+ShardIndex returns the index of the shard for the keys, the keys are distributed with the FNV-1a hash of the keys separated by a zero byte
+*/
+func ShardIndex(shards int, keys ...string) int {
+	hash := fnv.New32a()
+	for k, key := range keys {
+		if k > 0 {
+			hash.Write([]byte{0})
+		}
+		hash.Write([]byte(key))
+	}
+	return int(hash.Sum32() % uint32(shards))
+}
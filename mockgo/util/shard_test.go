@@ -0,0 +1,22 @@
+package util
+
+import (
+	"hash/fnv"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardIndex(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		key := "key" + strconv.Itoa(i)
+		hash := fnv.New32a()
+		hash.Write([]byte(key))
+		assert.Equal(t, int(hash.Sum32()%16), ShardIndex(16, key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(key))
+		assert.Equal(t, int(hash.Sum32()%16), ShardIndex(16, key, key))
+	}
+	assert.Equal(t, 0, ShardIndex(1, "key"))
+}
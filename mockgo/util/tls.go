@@ -0,0 +1,143 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+const certificateValidity = 365 * 24 * time.Hour
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+/*
+CreateCA creates a self-signed certificate authority
+*/
+func CreateCA(commonName string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, key, nil
+}
+
+/*
+CreateCertificate creates a certificate signed by the ca which can be used for server and client authentication, hosts are dns names or ip addresses
+*/
+func CreateCertificate(ca *x509.Certificate, caKey *ecdsa.PrivateKey, commonName string, hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der, ca.Raw}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+/*
+CertificatePEM encodes a certificate in PEM format
+*/
+func CertificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+/*
+CreateSelfSignedCertificate creates a server certificate for the hosts, which is signed by a new generated ca, the ca is returned in PEM format
+*/
+func CreateSelfSignedCertificate(hosts []string) (tls.Certificate, []byte, error) {
+	ca, caKey, err := CreateCA("mockgo-server self-signed ca")
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("error creating self-signed ca: %v", err)
+	}
+	certificate, err := CreateCertificate(ca, caKey, "mockgo-server", hosts)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("error creating self-signed certificate: %v", err)
+	}
+	return certificate, CertificatePEM(ca), nil
+}
+
+/*
+CreateTLSConfig creates the tls configuration of a server. Client certificates are always requested, they are verified when a client ca file is given
+*/
+func CreateTLSConfig(certificate tls.Certificate, clientCAFile string, clientCertRequired bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	if len(clientCAFile) == 0 {
+		if clientCertRequired {
+			tlsConfig.ClientAuth = tls.RequireAnyClientCert
+		} else {
+			tlsConfig.ClientAuth = tls.RequestClientCert
+		}
+		return tlsConfig, nil
+	}
+	clientCAs, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client ca file '%s': %v", clientCAFile, err)
+	}
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	if !tlsConfig.ClientCAs.AppendCertsFromPEM(clientCAs) {
+		return nil, fmt.Errorf("no certificates found in client ca file '%s'", clientCAFile)
+	}
+	if clientCertRequired {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSelfSignedCertificate(t *testing.T) {
+	certificate, caPEM, err := CreateSelfSignedCertificate([]string{"localhost", "127.0.0.1"})
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	assert.True(t, roots.AppendCertsFromPEM(caPEM))
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		_, err = certificate.Leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: host})
+		assert.NoError(t, err, "host %s", host)
+	}
+	_, err = certificate.Leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "example.com"})
+	assert.Error(t, err)
+}
+
+func TestCreateTLSConfig(t *testing.T) {
+	ca, caKey, err := CreateCA("test ca")
+	assert.NoError(t, err)
+	certificate, err := CreateCertificate(ca, caKey, "server", []string{"localhost"})
+	assert.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, CertificatePEM(ca), 0600))
+
+	for _, test := range []struct {
+		clientCAFile       string
+		clientCertRequired bool
+		expectedClientAuth tls.ClientAuthType
+	}{
+		{"", false, tls.RequestClientCert},
+		{"", true, tls.RequireAnyClientCert},
+		{caFile, false, tls.VerifyClientCertIfGiven},
+		{caFile, true, tls.RequireAndVerifyClientCert},
+	} {
+		tlsConfig, err := CreateTLSConfig(certificate, test.clientCAFile, test.clientCertRequired)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedClientAuth, tlsConfig.ClientAuth)
+		assert.Equal(t, test.clientCAFile != "", tlsConfig.ClientCAs != nil)
+	}
+
+	_, err = CreateTLSConfig(certificate, filepath.Join(t.TempDir(), "notexists.pem"), false)
+	assert.ErrorContains(t, err, "error reading client ca file")
+	noCertFile := filepath.Join(t.TempDir(), "nocert.pem")
+	assert.NoError(t, os.WriteFile(noCertFile, []byte("no cert"), 0600))
+	_, err = CreateTLSConfig(certificate, noCertFile, false)
+	assert.ErrorContains(t, err, "no certificates found in client ca file")
+}